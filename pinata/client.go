@@ -1,20 +1,32 @@
 package pinata
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 
 	"github.com/PinataCloud/pinata-go-sdk/pinata/files"
+	"github.com/PinataCloud/pinata-go-sdk/pinata/gateway"
+	"github.com/PinataCloud/pinata-go-sdk/pinata/groups"
+	"github.com/PinataCloud/pinata-go-sdk/pinata/internal/request"
+	"github.com/PinataCloud/pinata-go-sdk/pinata/keys"
+	"github.com/PinataCloud/pinata-go-sdk/pinata/signatures"
 	"github.com/PinataCloud/pinata-go-sdk/pinata/types"
 	"github.com/PinataCloud/pinata-go-sdk/pinata/upload"
 )
 
 // Client is the main Pinata SDK client
 type Client struct {
-	Config *types.Config
-	Files  *files.Service
-	Upload *upload.Service
+	Config     *types.Config
+	Files      *files.Service
+	Gateway    *gateway.Service
+	Groups     *groups.Service
+	Keys       *keys.Service
+	Signatures *signatures.Service
+	Upload     *upload.Service
 }
 
 // DefaultAPIURL is the default API endpoint
@@ -23,17 +35,10 @@ const DefaultAPIURL = "https://api.pinata.cloud/v3"
 // DefaultUploadURL is the default upload endpoint
 const DefaultUploadURL = "https://uploads.pinata.cloud/v3"
 
-// New creates a new Pinata SDK client with the provided JWT and gateway
-func New(jwt string, gateway string) *Client {
-	config := &types.Config{
-		PinataJWT:     jwt,
-		PinataGateway: gateway,
-		APIUrl:        DefaultAPIURL,
-		UploadUrl:     DefaultUploadURL,
-		CustomHeaders: make(map[string]string),
-	}
-
-	return NewWithConfig(config)
+// New creates a new Pinata SDK client with the provided JWT and gateway. Options
+// are applied on top of the default configuration.
+func New(jwt string, gateway string, opts ...Option) *Client {
+	return NewWithConfig(NewConfig(jwt, gateway, opts...))
 }
 
 // NewWithConfig creates a new Pinata SDK client with a custom configuration
@@ -44,16 +49,58 @@ func NewWithConfig(config *types.Config) *Client {
 
 	// Initialize the services with the configuration
 	client.Files = files.New(config)
+	client.Gateway = gateway.New(config)
+	client.Groups = groups.New(config)
+	client.Keys = keys.New(config)
+	client.Signatures = signatures.New(config)
 	client.Upload = upload.New(config)
 
 	return client
 }
 
+// NewWithConfigValidated is like NewWithConfig but first checks the
+// configuration with Config.Validate, returning its error instead of a client
+// that would fail on its first request
+func NewWithConfigValidated(config *types.Config) (*Client, error) {
+	if config == nil {
+		return nil, fmt.Errorf("config is required")
+	}
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	return NewWithConfig(config), nil
+}
+
+// GatewayURL returns the URL of a public CID on the configured gateway, e.g.
+// https://example.mypinata.cloud/ipfs/{cid}. Optional path segments address files
+// inside a directory CID.
+func (c *Client) GatewayURL(cid string, paths ...string) string {
+	return c.Gateway.Public.URL(cid, paths...)
+}
+
+// GatewayImageURL returns the URL of a public image CID with gateway image
+// transforms such as width, height and format applied
+func (c *Client) GatewayImageURL(cid string, opts gateway.ImageOptions) (string, error) {
+	return c.Gateway.Public.ImageURL(cid, opts)
+}
+
+// PrivateGatewayURL returns the URL of a private CID on the configured gateway.
+// Private content still needs a signed access link to be downloaded.
+func (c *Client) PrivateGatewayURL(cid string, paths ...string) string {
+	return c.Gateway.Private.URL(cid, paths...)
+}
+
 // TestAuthentication tests if the JWT is valid
 func (c *Client) TestAuthentication() (bool, error) {
-	url := fmt.Sprintf("https://api.pinata.cloud/data/testAuthentication")
+	return c.TestAuthenticationContext(context.Background())
+}
+
+// TestAuthenticationContext is like TestAuthentication but carries ctx through to the request
+func (c *Client) TestAuthenticationContext(ctx context.Context) (bool, error) {
+	url := fmt.Sprintf("%s/data/testAuthentication", apiBaseURL(c.Config.APIUrl))
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return false, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -65,17 +112,144 @@ func (c *Client) TestAuthentication() (bool, error) {
 		req.Header.Set(key, value)
 	}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := request.Do(c.Config, req)
 	if err != nil {
 		return false, fmt.Errorf("failed to send request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return false, fmt.Errorf("authentication failed (status %d): %s", resp.StatusCode, string(body))
+		return false, fmt.Errorf("authentication failed: %w", request.Error(resp))
 	}
 
 	return true, nil
 }
+
+// Ping checks that the API can be reached, for liveness probes. Unlike
+// TestAuthentication an invalid or missing JWT is not an error: authorized
+// reports whether the API accepted it, and err is only set when the API could
+// not be reached or answered with an unexpected status.
+func (c *Client) Ping() (authorized bool, err error) {
+	return c.PingContext(context.Background())
+}
+
+// PingContext is like Ping but carries ctx through to the request
+func (c *Client) PingContext(ctx context.Context) (authorized bool, err error) {
+	url := fmt.Sprintf("%s/data/testAuthentication", apiBaseURL(c.Config.APIUrl))
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Without a JWT the request is still sent, so reachability can be checked
+	if strings.TrimSpace(c.Config.PinataJWT) != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Config.PinataJWT)
+	}
+
+	// Add custom headers if any
+	for key, value := range c.Config.CustomHeaders {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := request.Do(c.Config, req)
+	if err != nil {
+		return false, fmt.Errorf("API unreachable: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusUnauthorized, http.StatusForbidden:
+		io.Copy(io.Discard, resp.Body)
+		return false, nil
+	default:
+		return false, request.Error(resp)
+	}
+}
+
+// Usage returns the number of files and bytes stored by the account, from the
+// API's summary endpoint rather than by listing every file
+func (c *Client) Usage() (*types.Usage, error) {
+	return c.UsageContext(context.Background())
+}
+
+// UsageContext is like Usage but carries ctx through to the request
+func (c *Client) UsageContext(ctx context.Context) (*types.Usage, error) {
+	url := fmt.Sprintf("%s/data/userPinnedDataTotal", apiBaseURL(c.Config.APIUrl))
+
+	req, err := request.New(ctx, c.Config, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := request.Do(c.Config, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, request.Error(resp)
+	}
+
+	// The totals are sent as numbers or numeric strings
+	var response struct {
+		PinCount                     json.Number `json:"pin_count"`
+		PinSizeTotal                 json.Number `json:"pin_size_total"`
+		PinSizeWithReplicationsTotal json.Number `json:"pin_size_with_replications_total"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	usage := &types.Usage{}
+	for _, field := range []struct {
+		value json.Number
+		dest  *int64
+	}{
+		{response.PinCount, &usage.FileCount},
+		{response.PinSizeTotal, &usage.TotalSize},
+		{response.PinSizeWithReplicationsTotal, &usage.TotalSizeWithReplications},
+	} {
+		if field.value == "" {
+			continue
+		}
+		n, err := field.value.Int64()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+		*field.dest = n
+	}
+
+	return usage, nil
+}
+
+// RateLimitStatus returns the API rate limit reported with the latest response,
+// so callers can slow down before they are limited. ok is false until a response
+// with rate limit headers has been received. It is safe to call concurrently
+// with requests.
+func (c *Client) RateLimitStatus() (status types.RateLimitStatus, ok bool) {
+	return request.RateLimitStatus(c.Config)
+}
+
+// Close stops the client from sending new requests, failing them with
+// types.ErrClientClosed, and waits until requests already in flight have
+// finished or ctx is done. Idle connections are closed unless the requests
+// go through a custom HTTPClient.
+func (c *Client) Close(ctx context.Context) error {
+	return request.Shutdown(ctx, c.Config)
+}
+
+// apiBaseURL strips the version path from the configured API URL, leaving the
+// root used by unversioned endpoints such as /data/testAuthentication
+func apiBaseURL(apiURL string) string {
+	if apiURL == "" {
+		apiURL = DefaultAPIURL
+	}
+
+	base := strings.TrimSuffix(apiURL, "/")
+	return strings.TrimSuffix(base, "/v3")
+}
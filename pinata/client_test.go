@@ -0,0 +1,286 @@
+package pinata
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/PinataCloud/pinata-go-sdk/pinata/types"
+)
+
+// newTestClient points a client at srv for both the API and uploads
+func newTestClient(srv *httptest.Server, opts ...Option) *Client {
+	opts = append([]Option{WithAPIURL(srv.URL), WithUploadURL(srv.URL)}, opts...)
+	return New("test-jwt", "example.mypinata.cloud", opts...)
+}
+
+func TestTimeoutExceeded(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	client := newTestClient(srv, WithTimeout(20*time.Millisecond))
+
+	start := time.Now()
+	_, err := client.Files.Public.Get("file-1")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got %v, want a deadline exceeded error", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("request took %s despite a 20ms timeout", elapsed)
+	}
+}
+
+func TestContextDeadlineWinsOverLongerTimeout(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	client := newTestClient(srv, WithTimeout(time.Minute))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if _, err := client.Files.Public.GetContext(ctx, "file-1"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got %v, want a deadline exceeded error", err)
+	}
+}
+
+func TestTestAuthenticationUsesAPIURL(t *testing.T) {
+	var path, auth, custom string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, auth, custom = r.URL.Path, r.Header.Get("Authorization"), r.Header.Get("X-Env")
+		if auth != "Bearer test-jwt" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"message":"Congratulations! You are communicating with the Pinata API!"}`))
+	}))
+	defer srv.Close()
+
+	client := New("test-jwt", "example", WithAPIURL(srv.URL+"/v3"), WithHeader("X-Env", "test"))
+
+	ok, err := client.TestAuthentication()
+	if err != nil || !ok {
+		t.Fatalf("got %v, %v", ok, err)
+	}
+	if path != "/data/testAuthentication" || custom != "test" {
+		t.Fatalf("request to %s with X-Env %q", path, custom)
+	}
+
+	client = New("wrong-jwt", "example", WithAPIURL(srv.URL+"/v3/"))
+	if ok, err := client.TestAuthentication(); ok || err == nil {
+		t.Fatalf("got %v, %v for a rejected JWT", ok, err)
+	}
+}
+
+func TestAPIBaseURL(t *testing.T) {
+	tests := map[string]string{
+		"":                            "https://api.pinata.cloud",
+		"https://api.pinata.cloud/v3": "https://api.pinata.cloud",
+		"http://localhost:8080/v3/":   "http://localhost:8080",
+		"http://localhost:8080":       "http://localhost:8080",
+	}
+	for apiURL, want := range tests {
+		if got := apiBaseURL(apiURL); got != want {
+			t.Errorf("apiBaseURL(%q) = %q, want %q", apiURL, got, want)
+		}
+	}
+}
+
+func TestGatewayURL(t *testing.T) {
+	tests := []struct {
+		gateway string
+		key     string
+		paths   []string
+		want    string
+	}{
+		{"example", "", nil, "https://example.mypinata.cloud/ipfs/bafy"},
+		{"example.mypinata.cloud", "", nil, "https://example.mypinata.cloud/ipfs/bafy"},
+		{"https://files.example.com/", "", nil, "https://files.example.com/ipfs/bafy"},
+		{"example", "", []string{"dir", "a.txt"}, "https://example.mypinata.cloud/ipfs/bafy/dir/a.txt"},
+		{"example", "", []string{"/dir/sub/", "my file.txt"}, "https://example.mypinata.cloud/ipfs/bafy/dir/sub/my%20file.txt"},
+		{"example", "", []string{"", "/"}, "https://example.mypinata.cloud/ipfs/bafy"},
+		{"example", "k&y", []string{"a.txt"}, "https://example.mypinata.cloud/ipfs/bafy/a.txt?pinataGatewayToken=k%26y"},
+	}
+
+	for _, tt := range tests {
+		client := New("jwt", tt.gateway)
+		client.Config.PinataGatewayKey = tt.key
+		if got := client.GatewayURL("bafy", tt.paths...); got != tt.want {
+			t.Errorf("GatewayURL with gateway %q and paths %q = %s, want %s", tt.gateway, tt.paths, got, tt.want)
+		}
+	}
+}
+
+func TestPrivateGatewayURL(t *testing.T) {
+	client := New("jwt", "example")
+	client.Config.PinataGatewayKey = "key"
+
+	want := "https://example.mypinata.cloud/files/bafy/a.txt?pinataGatewayToken=key"
+	if got := client.PrivateGatewayURL("bafy", "a.txt"); got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
+
+func TestPing(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/data/testAuthentication" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer test-jwt" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"message":"Congratulations!"}`))
+	}))
+	defer srv.Close()
+
+	authorized, err := newTestClient(srv).Ping()
+	if err != nil || !authorized {
+		t.Fatalf("valid JWT: got %t, %v", authorized, err)
+	}
+
+	client := newTestClient(srv)
+	client.Config.PinataJWT = "wrong"
+	authorized, err = client.Ping()
+	if err != nil || authorized {
+		t.Fatalf("invalid JWT: got %t, %v, want reachable but unauthorized", authorized, err)
+	}
+
+	client.Config.PinataJWT = ""
+	if authorized, err = client.Ping(); err != nil || authorized {
+		t.Fatalf("missing JWT: got %t, %v", authorized, err)
+	}
+
+	unreachable := newTestClient(srv, WithRetryPolicy(types.RetryPolicy{}))
+	srv.Close()
+	if _, err := unreachable.Ping(); err == nil {
+		t.Fatal("closed server: got no error")
+	}
+}
+
+func TestUsage(t *testing.T) {
+	responses := map[string]string{
+		"numbers": `{"pin_count":12,"pin_size_total":4096,"pin_size_with_replications_total":8192}`,
+		"strings": `{"pin_count":"12","pin_size_total":"4096","pin_size_with_replications_total":"8192"}`,
+	}
+	for name, body := range responses {
+		var path, auth string
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			path, auth = r.URL.Path, r.Header.Get("Authorization")
+			w.Write([]byte(body))
+		}))
+
+		usage, err := newTestClient(srv, WithAPIURL(srv.URL+"/v3")).Usage()
+		srv.Close()
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if *usage != (types.Usage{FileCount: 12, TotalSize: 4096, TotalSizeWithReplications: 8192}) {
+			t.Errorf("%s: got %+v", name, usage)
+		}
+		if path != "/data/userPinnedDataTotal" || auth != "Bearer test-jwt" {
+			t.Errorf("%s: requested %s with %q", name, path, auth)
+		}
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"pin_count":"lots"}`))
+	}))
+	defer srv.Close()
+	if _, err := newTestClient(srv).Usage(); err == nil {
+		t.Fatal("non-numeric total: got no error")
+	}
+}
+
+func TestRateLimitStatus(t *testing.T) {
+	var mu sync.Mutex
+	headers := map[string]string{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		for key, value := range headers {
+			w.Header().Set(key, value)
+		}
+		mu.Unlock()
+		w.Write([]byte(`{"message":"Congratulations!"}`))
+	}))
+	defer srv.Close()
+	setHeaders := func(h map[string]string) {
+		mu.Lock()
+		headers = h
+		mu.Unlock()
+	}
+
+	client := newTestClient(srv)
+	if _, ok := client.RateLimitStatus(); ok {
+		t.Fatal("status reported before any response")
+	}
+
+	setHeaders(map[string]string{"x-ratelimit-limit": "60", "x-ratelimit-remaining": "59", "x-ratelimit-reset": "30"})
+	before := time.Now()
+	if _, err := client.Ping(); err != nil {
+		t.Fatal(err)
+	}
+	status, ok := client.RateLimitStatus()
+	if !ok || status.Limit != 60 || status.Remaining != 59 {
+		t.Fatalf("got %+v, %t", status, ok)
+	}
+	if reset := status.Reset.Sub(before); reset < 29*time.Second || reset > 31*time.Second {
+		t.Fatalf("reset in %s, want about 30s", reset)
+	}
+
+	// A Unix reset time is used as is, and responses without headers leave the status alone
+	setHeaders(map[string]string{"x-ratelimit-limit": "60", "x-ratelimit-remaining": "12", "x-ratelimit-reset": "2000000000"})
+	if _, err := client.Ping(); err != nil {
+		t.Fatal(err)
+	}
+	setHeaders(nil)
+	if _, err := client.Ping(); err != nil {
+		t.Fatal(err)
+	}
+	status, _ = client.RateLimitStatus()
+	if status.Remaining != 12 || !status.Reset.Equal(time.Unix(2000000000, 0)) {
+		t.Fatalf("got %+v", status)
+	}
+
+	// Another client's config keeps its own status
+	if _, ok := newTestClient(srv).RateLimitStatus(); ok {
+		t.Fatal("status shared between clients")
+	}
+
+	setHeaders(map[string]string{"x-ratelimit-remaining": "5"})
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			client.Ping()
+		}()
+		go func() {
+			defer wg.Done()
+			client.RateLimitStatus()
+		}()
+	}
+	wg.Wait()
+	if status, _ := client.RateLimitStatus(); status.Remaining != 5 {
+		t.Fatalf("got %+v after concurrent requests", status)
+	}
+}
@@ -1,14 +1,14 @@
 package pinata
 
+import (
+	"net/http"
+	"time"
+
+	"github.com/PinataCloud/pinata-go-sdk/pinata/types"
+)
+
 // Config holds the configuration for the Pinata SDK client
-type Config struct {
-	PinataJWT        string
-	PinataGateway    string
-	PinataGatewayKey string
-	CustomHeaders    map[string]string
-	APIUrl           string
-	UploadUrl        string
-}
+type Config = types.Config
 
 // DefaultAPIUrl is the default API endpoint
 const DefaultAPIUrl = "https://api.pinata.cloud/v3"
@@ -16,13 +16,133 @@ const DefaultAPIUrl = "https://api.pinata.cloud/v3"
 // DefaultUploadUrl is the default upload endpoint
 const DefaultUploadUrl = "https://uploads.pinata.cloud/v3"
 
+// Option customizes a Config created by New or NewConfig
+type Option func(*Config)
+
+// WithAPIURL overrides the API endpoint, e.g. to target a staging or mock server
+func WithAPIURL(url string) Option {
+	return func(c *Config) {
+		c.APIUrl = url
+	}
+}
+
+// WithUploadURL overrides the upload endpoint
+func WithUploadURL(url string) Option {
+	return func(c *Config) {
+		c.UploadUrl = url
+	}
+}
+
+// WithHTTPClient sets the HTTP client used to send requests
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *Config) {
+		c.HTTPClient = client
+	}
+}
+
+// WithHeader adds a header sent with every request
+func WithHeader(key string, value string) Option {
+	return func(c *Config) {
+		if c.CustomHeaders == nil {
+			c.CustomHeaders = make(map[string]string)
+		}
+		c.CustomHeaders[key] = value
+	}
+}
+
+// WithTimeout sets the timeout applied to every HTTP request. Zero disables it.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Config) {
+		c.Timeout = timeout
+	}
+}
+
+// WithRetryPolicy sets how requests are retried after a transient failure
+func WithRetryPolicy(policy types.RetryPolicy) Option {
+	return func(c *Config) {
+		c.Retry = &policy
+	}
+}
+
+// WithUserAgent overrides the User-Agent header sent with every request
+func WithUserAgent(userAgent string) Option {
+	return func(c *Config) {
+		c.UserAgent = userAgent
+	}
+}
+
+// WithLogger sets the logger that receives a line for every request and response
+func WithLogger(logger types.Logger) Option {
+	return func(c *Config) {
+		c.Logger = logger
+	}
+}
+
+// WithDebug logs every request and response to stderr
+func WithDebug() Option {
+	return func(c *Config) {
+		c.Debug = true
+	}
+}
+
+// WithRateLimit caps the number of requests started per second
+func WithRateLimit(requestsPerSecond float64) Option {
+	return func(c *Config) {
+		c.RateLimit = requestsPerSecond
+	}
+}
+
+// WithCache caches up to size Files.Get results for ttl. A zero ttl keeps
+// entries until they are evicted or invalidated.
+func WithCache(size int, ttl time.Duration) Option {
+	return func(c *Config) {
+		c.CacheSize = size
+		c.CacheTTL = ttl
+	}
+}
+
+// WithDefaultGroups sets the groups file uploads to the public and private
+// networks go into when no group is given. Either may be empty.
+func WithDefaultGroups(publicGroupID string, privateGroupID string) Option {
+	return func(c *Config) {
+		c.DefaultPublicGroupID = publicGroupID
+		c.DefaultPrivateGroupID = privateGroupID
+	}
+}
+
+// WithFallbackGateways sets the gateways tried in order when the configured
+// gateway fails to serve a download, e.g. "gateway.pinata.cloud" as a backup
+// for a dedicated gateway
+func WithFallbackGateways(gateways ...string) Option {
+	return func(c *Config) {
+		c.FallbackGateways = gateways
+	}
+}
+
+// WithoutKeepAlives closes each connection after its request instead of keeping
+// it open for reuse
+func WithoutKeepAlives() Option {
+	return func(c *Config) {
+		c.DisableKeepAlives = true
+	}
+}
+
+// Version is the version of the SDK
+const Version = types.Version
+
 // NewConfig creates a default configuration with provided JWT and gateway
-func NewConfig(jwt string, gateway string) *Config {
-	return &Config{
+func NewConfig(jwt string, gateway string, opts ...Option) *Config {
+	config := &Config{
 		PinataJWT:     jwt,
 		PinataGateway: gateway,
 		APIUrl:        DefaultAPIUrl,
 		UploadUrl:     DefaultUploadUrl,
 		CustomHeaders: make(map[string]string),
 	}
+
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	return config
 }
@@ -0,0 +1,189 @@
+package pinata
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PinataCloud/pinata-go-sdk/pinata/types"
+	"github.com/PinataCloud/pinata-go-sdk/pinata/upload"
+)
+
+// recordingTransport records the URL of each request before sending it
+type recordingTransport struct {
+	urls []string
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.urls = append(t.urls, req.URL.String())
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestNewWithoutOptions(t *testing.T) {
+	client := New("jwt", "example.mypinata.cloud")
+
+	cfg := client.Config
+	if cfg.PinataJWT != "jwt" || cfg.PinataGateway != "example.mypinata.cloud" {
+		t.Fatalf("got credentials %q and gateway %q", cfg.PinataJWT, cfg.PinataGateway)
+	}
+	if cfg.APIUrl != DefaultAPIURL || cfg.UploadUrl != DefaultUploadURL {
+		t.Fatalf("got API URL %q and upload URL %q, want the defaults", cfg.APIUrl, cfg.UploadUrl)
+	}
+	if cfg.HTTPClient != nil || cfg.Timeout != 0 || len(cfg.CustomHeaders) != 0 {
+		t.Fatalf("got %+v, want no client, timeout or headers", cfg)
+	}
+}
+
+func TestWithAPIURLAndUploadURL(t *testing.T) {
+	var paths []string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, "api "+r.URL.Path)
+		fmt.Fprint(w, `{"data":{"id":"file-1"}}`)
+	}))
+	defer api.Close()
+	uploads := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, "uploads "+r.URL.Path)
+		fmt.Fprint(w, `{"data":{"id":"file-1"}}`)
+	}))
+	defer uploads.Close()
+
+	client := New("jwt", "example", WithAPIURL(api.URL), WithUploadURL(uploads.URL))
+
+	if _, err := client.Files.Public.Get("file-1"); err != nil {
+		t.Fatal(err)
+	}
+	data := &upload.FileData{Reader: bytes.NewReader([]byte("hi")), Name: "hi.txt", Size: 2}
+	if _, err := client.Upload.Public.FileReader(data, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "[api /files/public/file-1 uploads /files]"
+	if fmt.Sprint(paths) != want {
+		t.Fatalf("requests %v, want %s", paths, want)
+	}
+}
+
+func TestWithHTTPClient(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data":{"id":"file-1"}}`)
+	}))
+	defer srv.Close()
+
+	transport := &recordingTransport{}
+	client := newTestClient(srv, WithHTTPClient(&http.Client{Transport: transport}))
+
+	if _, err := client.Files.Public.Get("file-1"); err != nil {
+		t.Fatal(err)
+	}
+	if len(transport.urls) != 1 || transport.urls[0] != srv.URL+"/files/public/file-1" {
+		t.Fatalf("custom client sent %v", transport.urls)
+	}
+}
+
+func TestWithHeader(t *testing.T) {
+	var headers http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header
+		fmt.Fprint(w, `{"data":{"id":"file-1"}}`)
+	}))
+	defer srv.Close()
+
+	client := newTestClient(srv, WithHeader("X-Trace", "abc"), WithHeader("X-Env", "test"))
+
+	if _, err := client.Files.Public.Get("file-1"); err != nil {
+		t.Fatal(err)
+	}
+	if headers.Get("X-Trace") != "abc" || headers.Get("X-Env") != "test" {
+		t.Fatalf("got headers %v", headers)
+	}
+	if headers.Get("Authorization") != "Bearer test-jwt" {
+		t.Fatalf("Authorization %q, want the JWT", headers.Get("Authorization"))
+	}
+}
+
+func TestWithTimeout(t *testing.T) {
+	client := New("jwt", "example", WithTimeout(5*time.Second))
+	if client.Config.Timeout != 5*time.Second {
+		t.Fatalf("got timeout %s", client.Config.Timeout)
+	}
+}
+
+func TestEmptyJWTFailsBeforeSending(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	for _, jwt := range []string{"", "  "} {
+		client := New(jwt, "example", WithAPIURL(srv.URL), WithUploadURL(srv.URL))
+
+		if _, err := client.Files.Public.Get("file-1"); !errors.Is(err, types.ErrMissingJWT) {
+			t.Fatalf("Get with JWT %q: got %v, want ErrMissingJWT", jwt, err)
+		}
+		data := &upload.FileData{Reader: bytes.NewReader([]byte("hi")), Name: "hi.txt", Size: 2}
+		if _, err := client.Upload.Private.FileReader(data, nil); !errors.Is(err, types.ErrMissingJWT) {
+			t.Fatalf("FileReader with JWT %q: got %v, want ErrMissingJWT", jwt, err)
+		}
+	}
+	if requests != 0 {
+		t.Fatalf("sent %d requests without a JWT", requests)
+	}
+}
+
+func TestEmptyGatewayFailsBeforeSending(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer srv.Close()
+
+	client := newTestClient(srv)
+	client.Config.PinataGateway = ""
+
+	if _, err := client.Files.Private.CreateAccessLink(&types.AccessLinkOptions{CID: "bafy", Expires: 30}); !errors.Is(err, types.ErrMissingGateway) {
+		t.Fatalf("CreateAccessLink: got %v, want ErrMissingGateway", err)
+	}
+	if _, _, err := client.Gateway.Public.Get("bafy"); !errors.Is(err, types.ErrMissingGateway) {
+		t.Fatalf("gateway Get: got %v, want ErrMissingGateway", err)
+	}
+	if requests != 0 {
+		t.Fatalf("sent %d requests without a gateway", requests)
+	}
+}
+
+func TestNewWithConfigValidated(t *testing.T) {
+	client, err := NewWithConfigValidated(&types.Config{PinataJWT: "jwt", APIUrl: "https://api.example.com/v3"})
+	if err != nil || client == nil || client.Files == nil {
+		t.Fatalf("valid config: got %v, %v", client, err)
+	}
+
+	client, err = NewWithConfigValidated(&types.Config{APIUrl: "api.example.com"})
+	if client != nil || err == nil || !strings.Contains(err.Error(), "invalid config") || !strings.Contains(err.Error(), "PinataJWT is empty") {
+		t.Fatalf("invalid config: got %v, %v", client, err)
+	}
+
+	if _, err := NewWithConfigValidated(nil); err == nil {
+		t.Fatal("nil config: got no error")
+	}
+
+	// The permissive constructor still accepts anything
+	if NewWithConfig(&types.Config{APIUrl: "api.example.com"}) == nil {
+		t.Fatal("NewWithConfig returned nil")
+	}
+}
+
+func TestWithoutKeepAlives(t *testing.T) {
+	if NewConfig("jwt", "gateway").DisableKeepAlives {
+		t.Fatal("keep-alives disabled by default")
+	}
+	if !NewConfig("jwt", "gateway", WithoutKeepAlives()).DisableKeepAlives {
+		t.Fatal("WithoutKeepAlives did not disable keep-alives")
+	}
+}
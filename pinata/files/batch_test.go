@@ -0,0 +1,202 @@
+package files
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/PinataCloud/pinata-go-sdk/pinata/types"
+)
+
+func TestDeletePartialFailure(t *testing.T) {
+	var mu sync.Mutex
+	deleted := map[string]bool{}
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimPrefix(r.URL.Path, "/files/public/")
+		if r.Method != "DELETE" || strings.HasPrefix(id, "missing") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		mu.Lock()
+		deleted[id] = true
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	})
+	ids := []string{"a", "missing-1", "b", "missing-2", "c"}
+
+	responses, err := NewPublicService(cfg).Delete(ids)
+	if err == nil || err.Error() != "2 of 5 deletes failed" {
+		t.Fatalf("got error %v, want 2 of 5 deletes failed", err)
+	}
+
+	if len(responses) != len(ids) {
+		t.Fatalf("got %d responses, want %d", len(responses), len(ids))
+	}
+	for i, response := range responses {
+		if response.ID != ids[i] {
+			t.Fatalf("response %d is for %s, want %s", i, response.ID, ids[i])
+		}
+		if strings.HasPrefix(response.ID, "missing") {
+			if response.Status != "failed" || response.Error == "" {
+				t.Fatalf("%s: got status %q error %q, want a failure", response.ID, response.Status, response.Error)
+			}
+			continue
+		}
+		if response.Status != "deleted" || response.Error != "" || !deleted[response.ID] {
+			t.Fatalf("%s: got status %q error %q, want deleted", response.ID, response.Status, response.Error)
+		}
+	}
+}
+
+func TestDeleteByCID(t *testing.T) {
+	byCID := map[string]string{
+		"bafy-one":  `[{"id":"file-1"}]`,
+		"bafy-two":  `[{"id":"file-2"},{"id":"file-3"}]`,
+		"bafy-none": `[]`,
+	}
+	var mu sync.Mutex
+	var deleted []string
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			fmt.Fprintf(w, `{"data":{"files":%s,"next_page_token":""}}`, byCID[r.URL.Query().Get("cid")])
+		case "DELETE":
+			mu.Lock()
+			deleted = append(deleted, strings.TrimPrefix(r.URL.Path, "/files/private/"))
+			mu.Unlock()
+			fmt.Fprint(w, `{"data":null}`)
+		}
+	})
+	service := NewPrivateService(cfg)
+
+	results, err := service.DeleteByCID([]string{"bafy-one", "bafy-two", "bafy-none"}, nil)
+	if err == nil {
+		t.Fatal("got no error for the multi-match and missing CIDs")
+	}
+	if got := results[0]; got.CID != "bafy-one" || got.Status != "deleted" || fmt.Sprint(got.IDs) != "[file-1]" {
+		t.Errorf("single match: got %+v", got)
+	}
+	if got := results[1]; got.Status != "failed" || len(got.IDs) != 0 || !strings.Contains(got.Error, ErrMultipleFiles.Error()) {
+		t.Errorf("multi match: got %+v", got)
+	}
+	if got := results[2]; got.Status != "failed" || !strings.Contains(got.Error, ErrFileNotFound.Error()) {
+		t.Errorf("not found: got %+v", got)
+	}
+	if fmt.Sprint(deleted) != "[file-1]" {
+		t.Fatalf("deleted %v", deleted)
+	}
+
+	deleted = nil
+	results, err = service.DeleteByCID([]string{"bafy-two"}, &DeleteByCIDOptions{AllowMultiple: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(results[0].IDs) != "[file-2 file-3]" || fmt.Sprint(deleted) != "[file-2 file-3]" {
+		t.Fatalf("allow multiple: got %+v and deleted %v", results[0], deleted)
+	}
+}
+
+func TestUpdateBatchPartialFailure(t *testing.T) {
+	var mu sync.Mutex
+	names := map[string]string{}
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimPrefix(r.URL.Path, "/files/private/")
+		if r.Method != "PUT" || strings.HasPrefix(id, "missing") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		var payload struct {
+			Name string `json:"name"`
+		}
+		json.NewDecoder(r.Body).Decode(&payload)
+		mu.Lock()
+		names[id] = payload.Name
+		mu.Unlock()
+		fmt.Fprintf(w, `{"data":{"id":%q,"name":%q}}`, id, payload.Name)
+	})
+
+	updates := []UpdateOptions{
+		{ID: "file-1", Name: "one"},
+		{ID: "missing-2", Name: "two"},
+		{ID: "file-3", Name: "three"},
+		{ID: "", Name: "no id"},
+	}
+	results, err := NewPrivateService(cfg).UpdateBatch(updates)
+	if err == nil {
+		t.Fatal("got no error for the failed updates")
+	}
+	if len(results) != len(updates) {
+		t.Fatalf("got %d results, want %d", len(results), len(updates))
+	}
+
+	for i, want := range []string{"updated", "failed", "updated", "failed"} {
+		if results[i].Status != want || results[i].ID != updates[i].ID {
+			t.Errorf("update %d: got %+v, want %s", i, results[i], want)
+		}
+	}
+	if results[0].File == nil || results[0].File.Name != "one" || results[2].File.Name != "three" {
+		t.Errorf("updated files: got %+v and %+v", results[0].File, results[2].File)
+	}
+	if results[1].Error == "" || results[1].File != nil || results[3].Error == "" {
+		t.Errorf("failed updates: got %+v and %+v", results[1], results[3])
+	}
+	if fmt.Sprint(names) != "map[file-1:one file-3:three]" {
+		t.Fatalf("renamed %v", names)
+	}
+
+	if _, err := NewPrivateService(cfg).UpdateBatch(nil); !errors.Is(err, ErrNoFileID) {
+		t.Fatalf("no updates: got %v", err)
+	}
+}
+
+func TestGetManyFoundAndMissing(t *testing.T) {
+	var mu sync.Mutex
+	requested := map[string]int{}
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimPrefix(r.URL.Path, "/files/private/")
+		mu.Lock()
+		requested[id]++
+		mu.Unlock()
+		if strings.HasPrefix(id, "missing") {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error":"file not found"}`)
+			return
+		}
+		fmt.Fprintf(w, `{"data":{"id":%q,"name":"%s.txt"}}`, id, id)
+	})
+
+	files, errs := NewPrivateService(cfg).GetMany([]string{"file-1", "missing-2", "file-3", "file-1", "", "missing-2"})
+
+	if len(files) != 2 || files["file-1"].Name != "file-1.txt" || files["file-3"].Name != "file-3.txt" {
+		t.Fatalf("found %v", files)
+	}
+	if len(errs) != 2 || !types.IsNotFound(errs["missing-2"]) || !errors.Is(errs[""], ErrNoFileID) {
+		t.Fatalf("errors %v", errs)
+	}
+	if fmt.Sprint(requested) != "map[file-1:1 file-3:1 missing-2:1]" {
+		t.Fatalf("requested %v, want each ID once", requested)
+	}
+}
+
+func TestGetManyCancelled(t *testing.T) {
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request to %s", r.URL.Path)
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	files, errs := NewPublicService(cfg).GetManyContext(ctx, []string{"file-1", "file-2"})
+	if len(files) != 0 || len(errs) != 2 {
+		t.Fatalf("got %v and %v, want an error per ID", files, errs)
+	}
+	for id, err := range errs {
+		if !errors.Is(err, context.Canceled) || !strings.Contains(err.Error(), "not attempted") {
+			t.Errorf("%s: got %v, want a not attempted error wrapping the cancellation", id, err)
+		}
+	}
+}
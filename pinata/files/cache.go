@@ -0,0 +1,131 @@
+package files
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/PinataCloud/pinata-go-sdk/pinata/types"
+)
+
+// fileCache is a concurrency-safe LRU cache of Get results keyed by network and ID
+type fileCache struct {
+	mu      sync.Mutex
+	size    int
+	ttl     time.Duration
+	order   *list.List // front is most recently used
+	entries map[string]*list.Element
+}
+
+// cacheEntry is the value stored in fileCache.order
+type cacheEntry struct {
+	key     string
+	file    types.File
+	expires time.Time
+}
+
+// newFileCache returns the cache configured by cfg, or nil when caching is off
+func newFileCache(config interface{}) *fileCache {
+	cfg, ok := config.(*types.Config)
+	if !ok || cfg.CacheSize <= 0 {
+		return nil
+	}
+
+	return &fileCache{
+		size:    cfg.CacheSize,
+		ttl:     cfg.CacheTTL,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+// cacheKey builds the key for a file on a network
+func cacheKey(network string, id string) string {
+	return network + "/" + id
+}
+
+// get returns a copy of the cached file, if present and not expired
+func (c *fileCache) get(key string) (*types.File, bool) {
+	if c == nil {
+		return nil, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := element.Value.(*cacheEntry)
+	if !entry.expires.IsZero() && time.Now().After(entry.expires) {
+		c.order.Remove(element)
+		delete(c.entries, key)
+		return nil, false
+	}
+
+	c.order.MoveToFront(element)
+	return copyFile(&entry.file), true
+}
+
+// put stores a copy of file, evicting the least recently used entry when full
+func (c *fileCache) put(key string, file *types.File) {
+	if c == nil || file == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var expires time.Time
+	if c.ttl > 0 {
+		expires = time.Now().Add(c.ttl)
+	}
+
+	if element, ok := c.entries[key]; ok {
+		element.Value = &cacheEntry{key: key, file: *copyFile(file), expires: expires}
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&cacheEntry{key: key, file: *copyFile(file), expires: expires})
+
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+// remove drops key from the cache
+func (c *fileCache) remove(key string) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[key]; ok {
+		c.order.Remove(element)
+		delete(c.entries, key)
+	}
+}
+
+// copyFile returns a copy of file that shares no maps or pointers with it, so
+// callers cannot change cached entries
+func copyFile(file *types.File) *types.File {
+	copied := *file
+	if file.KeyValues != nil {
+		copied.KeyValues = make(map[string]string, len(file.KeyValues))
+		for key, value := range file.KeyValues {
+			copied.KeyValues[key] = value
+		}
+	}
+	if file.GroupID != nil {
+		groupID := *file.GroupID
+		copied.GroupID = &groupID
+	}
+	return &copied
+}
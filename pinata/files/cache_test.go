@@ -0,0 +1,162 @@
+package files
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/PinataCloud/pinata-go-sdk/pinata/types"
+)
+
+// newCacheTest serves files by ID, naming each after the number of GETs served
+// so far, and accepts updates and deletes. It returns the number of GETs.
+func newCacheTest(t *testing.T, size int, ttl time.Duration) (*types.Config, func() int) {
+	t.Helper()
+
+	var mu sync.Mutex
+	gets := 0
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		id := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+		switch r.Method {
+		case "GET":
+			gets++
+			fmt.Fprintf(w, `{"data":{"id":%q,"name":"v%d","keyvalues":{"k":"v"}}}`, id, gets)
+		case "PUT":
+			fmt.Fprintf(w, `{"data":{"id":%q,"name":"updated"}}`, id)
+		case "DELETE":
+			w.WriteHeader(http.StatusOK)
+		}
+	})
+	cfg.CacheSize = size
+	cfg.CacheTTL = ttl
+
+	return cfg, func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return gets
+	}
+}
+
+func TestCacheHitAvoidsRequest(t *testing.T) {
+	cfg, gets := newCacheTest(t, 10, 0)
+	service := NewPublicService(cfg)
+
+	first, err := service.Get("file-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := service.Get("file-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gets() != 1 || second.Name != first.Name {
+		t.Fatalf("%d GETs, second result %+v, want 1 GET and the cached file", gets(), second)
+	}
+
+	// Callers get copies, so changing one does not change the cache
+	second.KeyValues["k"] = "changed"
+	third, _ := service.Get("file-1")
+	if third.KeyValues["k"] != "v" {
+		t.Fatalf("cached file was modified through a returned copy: %+v", third)
+	}
+}
+
+func TestCacheInvalidatedByUpdateAndDelete(t *testing.T) {
+	cfg, gets := newCacheTest(t, 10, 0)
+	service := NewPrivateService(cfg)
+
+	service.Get("file-1")
+	if _, err := service.Update(&UpdateOptions{ID: "file-1", Name: "updated"}); err != nil {
+		t.Fatal(err)
+	}
+	if file, _ := service.Get("file-1"); file.Name != "v2" || gets() != 2 {
+		t.Fatalf("after Update: got %+v after %d GETs, want a fresh GET", file, gets())
+	}
+
+	if _, err := service.Delete([]string{"file-1"}); err != nil {
+		t.Fatal(err)
+	}
+	service.Get("file-1")
+	if gets() != 3 {
+		t.Fatalf("after Delete: %d GETs, want a fresh GET", gets())
+	}
+
+	service.InvalidateCache("file-1")
+	service.Get("file-1")
+	if gets() != 4 {
+		t.Fatalf("after InvalidateCache: %d GETs, want a fresh GET", gets())
+	}
+}
+
+func TestCacheKeyedByNetwork(t *testing.T) {
+	cfg, gets := newCacheTest(t, 10, 0)
+
+	NewPublicService(cfg).Get("file-1")
+	NewPrivateService(cfg).Get("file-1")
+	if gets() != 2 {
+		t.Fatalf("%d GETs, want one per network", gets())
+	}
+}
+
+func TestCacheTTLAndEviction(t *testing.T) {
+	cfg, gets := newCacheTest(t, 10, 20*time.Millisecond)
+	service := NewPublicService(cfg)
+
+	service.Get("file-1")
+	time.Sleep(30 * time.Millisecond)
+	service.Get("file-1")
+	if gets() != 2 {
+		t.Fatalf("%d GETs, want the expired entry fetched again", gets())
+	}
+
+	cfg, gets = newCacheTest(t, 2, 0)
+	service = NewPublicService(cfg)
+	service.Get("a")
+	service.Get("b")
+	service.Get("a") // a is now the most recently used
+	service.Get("c") // evicts b
+	service.Get("a")
+	if gets() != 3 {
+		t.Fatalf("%d GETs, want a to stay cached", gets())
+	}
+	service.Get("b")
+	if gets() != 4 {
+		t.Fatalf("%d GETs, want b to have been evicted", gets())
+	}
+}
+
+func TestCacheOffByDefault(t *testing.T) {
+	cfg, gets := newCacheTest(t, 0, 0)
+	service := NewPublicService(cfg)
+
+	service.Get("file-1")
+	service.Get("file-1")
+	if gets() != 2 {
+		t.Fatalf("%d GETs, want no caching", gets())
+	}
+}
+
+func TestCacheConcurrentUse(t *testing.T) {
+	cfg, _ := newCacheTest(t, 4, 0)
+	service := NewPublicService(cfg)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			id := fmt.Sprintf("file-%d", i%6)
+			if _, err := service.Get(id); err != nil {
+				t.Error(err)
+			}
+			service.InvalidateCache(id)
+		}()
+	}
+	wg.Wait()
+}
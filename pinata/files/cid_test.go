@@ -0,0 +1,80 @@
+package files
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/PinataCloud/pinata-go-sdk/pinata/types"
+)
+
+// cidLookup is the CID based lookup API shared by PublicService and PrivateService
+type cidLookup interface {
+	GetByCIDContext(ctx context.Context, cid string) (*types.File, error)
+	ExistsContext(ctx context.Context, cid string) (bool, error)
+}
+
+// newCIDLookups serves the list endpoint of both networks with no file for any
+// CID except bafy-one, held by one file, and bafy-two, held by two
+func newCIDLookups(t *testing.T) map[string]cidLookup {
+	t.Helper()
+
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/files/public" && r.URL.Path != "/files/private" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		switch r.URL.Query().Get("cid") {
+		case "bafy-one":
+			fmt.Fprint(w, `{"data":{"files":[{"id":"file-1","cid":"bafy-one"}]}}`)
+		case "bafy-two":
+			fmt.Fprint(w, `{"data":{"files":[{"id":"file-1","cid":"bafy-two"},{"id":"file-2","cid":"bafy-two"}]}}`)
+		default:
+			fmt.Fprint(w, `{"data":{"files":[]}}`)
+		}
+	})
+
+	return map[string]cidLookup{
+		"public":  NewPublicService(cfg),
+		"private": NewPrivateService(cfg),
+	}
+}
+
+func TestGetByCID(t *testing.T) {
+	for network, service := range newCIDLookups(t) {
+		file, err := service.GetByCIDContext(context.Background(), "bafy-one")
+		if err != nil {
+			t.Fatalf("%s found: %v", network, err)
+		}
+		if file.ID != "file-1" || file.Network != network {
+			t.Fatalf("%s found: got %+v", network, file)
+		}
+
+		if _, err := service.GetByCIDContext(context.Background(), "bafy-none"); !errors.Is(err, ErrFileNotFound) {
+			t.Fatalf("%s not found: got %v, want ErrFileNotFound", network, err)
+		}
+		if _, err := service.GetByCIDContext(context.Background(), "bafy-two"); !errors.Is(err, ErrMultipleFiles) {
+			t.Fatalf("%s multiple: got %v, want ErrMultipleFiles", network, err)
+		}
+	}
+}
+
+func TestExists(t *testing.T) {
+	for network, service := range newCIDLookups(t) {
+		for cid, want := range map[string]bool{"bafy-one": true, "bafy-two": true, "bafy-none": false} {
+			exists, err := service.ExistsContext(context.Background(), cid)
+			if err != nil {
+				t.Fatalf("%s %s: %v", network, cid, err)
+			}
+			if exists != want {
+				t.Errorf("%s Exists(%s) = %t, want %t", network, cid, exists, want)
+			}
+		}
+
+		if _, err := service.ExistsContext(context.Background(), ""); !errors.Is(err, ErrNoCID) {
+			t.Fatalf("%s without a CID: got %v, want ErrNoCID", network, err)
+		}
+	}
+}
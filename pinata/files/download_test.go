@@ -0,0 +1,116 @@
+package files
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PinataCloud/pinata-go-sdk/pinata/types"
+)
+
+// newDownloadTest serves the download link endpoint, which signs links to
+// /gateway/files/{cid} on the same server, and passes gateway requests to
+// gateway
+func newDownloadTest(t *testing.T, gateway http.HandlerFunc) (*types.Config, *map[string]interface{}) {
+	t.Helper()
+
+	var payload map[string]interface{}
+	var cfg *types.Config
+	cfg = newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/files/private/download_link":
+			json.NewDecoder(r.Body).Decode(&payload)
+			link := fmt.Sprintf("%s/gateway/files/bafy?X-Signature=sig", cfg.APIUrl)
+			fmt.Fprintf(w, `{"data":%q}`, link)
+		case strings.HasPrefix(r.URL.Path, "/gateway/"):
+			gateway(w, r)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	cfg.PinataGateway = "example"
+	return cfg, &payload
+}
+
+func TestDownload(t *testing.T) {
+	var auth, signature string
+	cfg, payload := newDownloadTest(t, func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		signature = r.URL.Query().Get("X-Signature")
+		w.Header().Set("Content-Type", "text/plain")
+		fmt.Fprint(w, "private content")
+	})
+
+	data, contentType, err := NewPrivateService(cfg).Download("bafy", 60)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "private content" || contentType != "text/plain" {
+		t.Fatalf("got %q (%s)", data, contentType)
+	}
+	if (*payload)["url"] != "https://example.mypinata.cloud/files/bafy" || (*payload)["expires"] != float64(60) {
+		t.Fatalf("download link requested with %v", *payload)
+	}
+	if signature != "sig" {
+		t.Fatalf("gateway request signature %q, want the signed link's", signature)
+	}
+	if auth != "" {
+		t.Fatalf("JWT sent to the gateway: %q", auth)
+	}
+}
+
+func TestDownloadStream(t *testing.T) {
+	cfg, _ := newDownloadTest(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		fmt.Fprint(w, strings.Repeat("x", 1<<16))
+	})
+
+	body, contentType, err := NewPrivateService(cfg).DownloadStream("bafy", 60)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer body.Close()
+
+	n, err := io.Copy(io.Discard, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1<<16 || contentType != "application/octet-stream" {
+		t.Fatalf("read %d bytes of %s", n, contentType)
+	}
+}
+
+func TestDownloadGatewayError(t *testing.T) {
+	cfg, _ := newDownloadTest(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, `{"error":"link expired"}`)
+	})
+
+	if _, _, err := NewPrivateService(cfg).Download("bafy", 60); types.StatusCode(err) != http.StatusForbidden {
+		t.Fatalf("got %v, want a 403", err)
+	}
+}
+
+func TestDownloadHonorsContext(t *testing.T) {
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+	cfg, _ := newDownloadTest(t, func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if _, _, err := NewPrivateService(cfg).DownloadContext(ctx, "bafy", 60); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got %v, want context.DeadlineExceeded", err)
+	}
+}
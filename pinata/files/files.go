@@ -1,5 +1,27 @@
 package files
 
+import "errors"
+
+// Sentinel errors returned when a required input is missing, so callers can tell
+// validation failures apart from API errors with errors.Is
+var (
+	ErrNoFileID       = errors.New("file ID is required")
+	ErrNoCID          = errors.New("CID is required")
+	ErrMissingSwapCID = errors.New("swap CID is required")
+)
+
+// ErrFileNotFound is returned by GetByCID and reported by DeleteByCID when no
+// file has the CID
+var ErrFileNotFound = errors.New("no file found for CID")
+
+// ErrMultipleFiles is returned by GetByCID, and reported by DeleteByCID unless
+// AllowMultiple is set, when more than one file has the CID
+var ErrMultipleFiles = errors.New("more than one file found for CID")
+
+// ErrNotVectorized is returned by QueryByFile when the source file has no
+// vectors to compare against; vectorize it first with Vectorize
+var ErrNotVectorized = errors.New("file is not vectorized")
+
 // Service provides file-related operations for Pinata
 type Service struct {
 	config  interface{}
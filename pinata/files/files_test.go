@@ -0,0 +1,52 @@
+package files
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/PinataCloud/pinata-go-sdk/pinata/types"
+)
+
+func TestValidationErrors(t *testing.T) {
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+	})
+	public := NewPublicService(cfg)
+	private := NewPrivateService(cfg)
+
+	tests := []struct {
+		name string
+		call func() error
+		want error
+	}{
+		{"public Get", func() error { _, err := public.Get(""); return err }, ErrNoFileID},
+		{"private Get", func() error { _, err := private.Get(""); return err }, ErrNoFileID},
+		{"public GetByCID", func() error { _, err := public.GetByCID(""); return err }, ErrNoCID},
+		{"public Update", func() error { _, err := public.Update(&UpdateOptions{}); return err }, ErrNoFileID},
+		{"private Update", func() error { _, err := private.Update(nil); return err }, ErrNoFileID},
+		{"public Delete", func() error { _, err := public.Delete(nil); return err }, ErrNoFileID},
+		{"private Delete", func() error { _, err := private.Delete([]string{}); return err }, ErrNoFileID},
+		{"public AddSwap", func() error { _, err := public.AddSwap(&SwapOptions{SwapCID: "bafy2"}); return err }, ErrNoCID},
+		{"public AddSwap without swap CID", func() error { _, err := public.AddSwap(&SwapOptions{CID: "bafy"}); return err }, ErrMissingSwapCID},
+		{"private AddSwap without swap CID", func() error { _, err := private.AddSwap(&SwapOptions{CID: "bafy"}); return err }, ErrMissingSwapCID},
+		{"public DeleteSwap", func() error { return public.DeleteSwap("") }, ErrNoCID},
+		{"private PinByHash", func() error { _, err := private.PinByHash(nil); return err }, ErrNoCID},
+		{"private Vectorize", func() error { _, err := private.Vectorize(""); return err }, ErrNoFileID},
+	}
+
+	for _, tt := range tests {
+		if err := tt.call(); !errors.Is(err, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestValidationErrorsAreNotAPIErrors(t *testing.T) {
+	_, err := NewPublicService(&types.Config{PinataJWT: "jwt"}).Get("")
+
+	var apiErr *types.APIError
+	if errors.As(err, &apiErr) || types.StatusCode(err) != 0 {
+		t.Fatalf("validation error %v looks like a server error", err)
+	}
+}
@@ -0,0 +1,107 @@
+package files
+
+import (
+	"context"
+
+	"github.com/PinataCloud/pinata-go-sdk/pinata/internal/pager"
+	"github.com/PinataCloud/pinata-go-sdk/pinata/types"
+)
+
+// FileIterator walks through the files of a List query, fetching pages as needed
+//
+//	it := client.Files.Public.ListIter(nil)
+//	for it.Next() {
+//		file := it.File()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type FileIterator struct {
+	pager *pager.Pager[types.File]
+}
+
+func newFileIterator(ctx context.Context, list func(context.Context, *ListOptions) (*types.FileListResponse, error), opts *ListOptions) *FileIterator {
+	var query ListOptions
+	if opts != nil {
+		query = *opts
+	}
+
+	fetch := func(ctx context.Context, token string) (*pager.Page[types.File], error) {
+		query.PageToken = token
+		resp, err := list(ctx, &query)
+		if err != nil || resp == nil {
+			return nil, err
+		}
+		return &pager.Page[types.File]{Items: resp.Files, NextPageToken: resp.NextPageToken}, nil
+	}
+
+	// A size filter can empty a page that is not the last, so keep going then
+	return &FileIterator{pager: pager.New(ctx, query.PageToken, query.filtersSize(), fetch)}
+}
+
+// Next advances to the next file, fetching the next page when the current one is
+// exhausted. It returns false when there are no more files or an error occurred.
+func (it *FileIterator) Next() bool {
+	return it.pager.Next()
+}
+
+// File returns the current file
+func (it *FileIterator) File() types.File {
+	return it.pager.Item()
+}
+
+// Err returns the error that stopped the iteration, if any
+func (it *FileIterator) Err() error {
+	return it.pager.Err()
+}
+
+// collectFiles drains an iterator into a slice
+func collectFiles(it *FileIterator) ([]types.File, error) {
+	return it.pager.Collect()
+}
+
+// PinQueueIterator walks through the jobs of a Queue query, fetching pages as needed.
+// It is used the same way as FileIterator.
+type PinQueueIterator struct {
+	pager *pager.Pager[types.PinQueueItem]
+}
+
+func newPinQueueIterator(ctx context.Context, queue func(context.Context, *PinQueueOptions) (*types.PinQueueResponse, error), opts *PinQueueOptions) *PinQueueIterator {
+	var query PinQueueOptions
+	if opts != nil {
+		query = *opts
+	}
+
+	fetch := func(ctx context.Context, token string) (*pager.Page[types.PinQueueItem], error) {
+		query.PageToken = token
+		resp, err := queue(ctx, &query)
+		if err != nil || resp == nil {
+			return nil, err
+		}
+		return &pager.Page[types.PinQueueItem]{Items: resp.Items, NextPageToken: resp.NextPageToken}, nil
+	}
+
+	return &PinQueueIterator{pager: pager.New(ctx, query.PageToken, false, fetch)}
+}
+
+// Next advances to the next job, fetching the next page when the current one is
+// exhausted. It returns false when there are no more jobs or an error occurred.
+func (it *PinQueueIterator) Next() bool {
+	return it.pager.Next()
+}
+
+// Item returns the current job
+func (it *PinQueueIterator) Item() types.PinQueueItem {
+	return it.pager.Item()
+}
+
+// Err returns the error that stopped the iteration, if any
+func (it *PinQueueIterator) Err() error {
+	return it.pager.Err()
+}
+
+// collectPinQueue drains an iterator into a slice
+func collectPinQueue(it *PinQueueIterator) ([]types.PinQueueItem, error) {
+	return it.pager.Collect()
+}
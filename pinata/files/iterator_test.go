@@ -0,0 +1,131 @@
+package files
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/PinataCloud/pinata-go-sdk/pinata/types"
+)
+
+// pagedFiles serves three pages of files on /files/{network}, chained by page
+// tokens, and records the token of each request. A page listed in fail
+// returns a server error.
+func pagedFiles(t *testing.T, network string, fail string) (*types.Config, *[]string) {
+	t.Helper()
+
+	pages := map[string]string{
+		"":   `{"data":{"files":[{"id":"f1"},{"id":"f2"}],"next_page_token":"p2"}}`,
+		"p2": `{"data":{"files":[{"id":"f3"},{"id":"f4"}],"next_page_token":"p3"}}`,
+		"p3": `{"data":{"files":[{"id":"f5"}],"next_page_token":""}}`,
+	}
+
+	var tokens []string
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/files/"+network {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		token := r.URL.Query().Get("pageToken")
+		tokens = append(tokens, token)
+		if token == fail {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"error":"bad page"}`)
+			return
+		}
+		fmt.Fprint(w, pages[token])
+	})
+	return cfg, &tokens
+}
+
+func fileIDs(files []types.File) []string {
+	ids := make([]string, len(files))
+	for i, file := range files {
+		ids[i] = file.ID
+	}
+	return ids
+}
+
+func TestListAllFollowsPageTokens(t *testing.T) {
+	want := "[f1 f2 f3 f4 f5]"
+
+	cfg, tokens := pagedFiles(t, "public", "none")
+	files, err := NewPublicService(cfg).ListAll(&ListOptions{Limit: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(fileIDs(files)) != want {
+		t.Fatalf("public: got %v, want %s", fileIDs(files), want)
+	}
+	if fmt.Sprint(*tokens) != "[ p2 p3]" {
+		t.Fatalf("public: requested page tokens %q", *tokens)
+	}
+
+	cfg, _ = pagedFiles(t, "private", "none")
+	files, err = NewPrivateService(cfg).ListAll(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(fileIDs(files)) != want {
+		t.Fatalf("private: got %v, want %s", fileIDs(files), want)
+	}
+}
+
+func TestListIterStreamsPages(t *testing.T) {
+	cfg, tokens := pagedFiles(t, "private", "none")
+
+	it := NewPrivateService(cfg).ListIter(nil)
+	var ids []string
+	for it.Next() {
+		ids = append(ids, it.File().ID)
+		// Pages are only fetched as the iteration reaches them
+		if len(ids) == 1 && len(*tokens) != 1 {
+			t.Fatalf("fetched %d pages before reading the second file", len(*tokens))
+		}
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(ids) != "[f1 f2 f3 f4 f5]" {
+		t.Fatalf("got %v", ids)
+	}
+	if it.Next() {
+		t.Fatal("Next returned true after the last page")
+	}
+}
+
+func TestListIterStopsOnError(t *testing.T) {
+	cfg, _ := pagedFiles(t, "public", "p2")
+
+	it := NewPublicService(cfg).ListIter(nil)
+	var ids []string
+	for it.Next() {
+		ids = append(ids, it.File().ID)
+	}
+	if types.StatusCode(it.Err()) != http.StatusBadRequest {
+		t.Fatalf("got error %v, want a 400", it.Err())
+	}
+	if fmt.Sprint(ids) != "[f1 f2]" {
+		t.Fatalf("got %v before the error, want [f1 f2]", ids)
+	}
+
+	if _, err := NewPublicService(cfg).ListAll(nil); err == nil {
+		t.Fatal("ListAll succeeded when a page failed")
+	}
+}
+
+func TestListIterStopsOnRepeatedToken(t *testing.T) {
+	requests := 0
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprint(w, `{"data":{"files":[{"id":"f1"}],"next_page_token":"same"}}`)
+	})
+
+	files, err := NewPublicService(cfg).ListAll(&ListOptions{PageToken: "same"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 || requests != 1 {
+		t.Fatalf("got %d files in %d requests, want 1 in 1", len(files), requests)
+	}
+}
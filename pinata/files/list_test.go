@@ -0,0 +1,216 @@
+package files
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// listQuery runs list against a server answering with an empty page and
+// returns the query it received
+func listQuery(t *testing.T, list func(*PublicService) error) (url.Values, error) {
+	t.Helper()
+
+	var query url.Values
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		fmt.Fprint(w, `{"data":{"files":[],"next_page_token":""}}`)
+	})
+	err := list(NewPublicService(cfg))
+	return query, err
+}
+
+func TestListCreatedRange(t *testing.T) {
+	after := time.Date(2024, 3, 1, 9, 30, 0, 0, time.FixedZone("CET", 3600))
+	before := time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC)
+
+	query, err := listQuery(t, func(s *PublicService) error {
+		_, err := s.List(&ListOptions{CreatedAfter: after, CreatedBefore: before})
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := query.Get("createdAfter"); got != "2024-03-01T08:30:00Z" {
+		t.Errorf("createdAfter = %q, want the UTC RFC 3339 time", got)
+	}
+	if got := query.Get("createdBefore"); got != "2024-03-31T00:00:00Z" {
+		t.Errorf("createdBefore = %q", got)
+	}
+}
+
+func TestListCreatedRangeOpenEnded(t *testing.T) {
+	query, err := listQuery(t, func(s *PublicService) error {
+		_, err := s.List(&ListOptions{CreatedAfter: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)})
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if query.Get("createdAfter") != "2024-01-01T00:00:00Z" || query.Has("createdBefore") {
+		t.Fatalf("got query %v", query)
+	}
+}
+
+func TestListCreatedRangeValidated(t *testing.T) {
+	now := time.Now()
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s", r.URL)
+	})
+
+	opts := &ListOptions{CreatedAfter: now, CreatedBefore: now.Add(-time.Hour)}
+	if _, err := NewPublicService(cfg).List(opts); err == nil {
+		t.Error("public List accepted CreatedAfter later than CreatedBefore")
+	}
+	if _, err := NewPrivateService(cfg).List(opts); err == nil {
+		t.Error("private List accepted CreatedAfter later than CreatedBefore")
+	}
+}
+
+func TestListKeyValueFilters(t *testing.T) {
+	query, err := listQuery(t, func(s *PublicService) error {
+		_, err := s.List(&ListOptions{
+			KeyValues: map[string]string{"env": "prod"},
+			KeyValueFilters: map[string]KeyValueFilter{
+				"team":  {Value: "data"},
+				"score": {Value: "10", Op: "gte"},
+			},
+		})
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := url.Values{
+		"keyvalues[env]":          {"prod"},
+		"keyvalues[team][value]":  {"data"},
+		"keyvalues[score][value]": {"10"},
+		"keyvalues[score][op]":    {"gte"},
+	}
+	if query.Encode() != want.Encode() {
+		t.Fatalf("got query %s, want %s", query.Encode(), want.Encode())
+	}
+}
+
+func TestListSizeBounds(t *testing.T) {
+	pages := map[string]string{
+		"":   `{"data":{"files":[{"id":"tiny","size":10},{"id":"small","size":100},{"id":"big","size":5000}],"next_page_token":"p2"}}`,
+		"p2": `{"data":{"files":[{"id":"huge","size":90000}],"next_page_token":"p3"}}`,
+		"p3": `{"data":{"files":[{"id":"medium","size":1000},{"id":"edge","size":5000}],"next_page_token":""}}`,
+	}
+	var queries []url.Values
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query())
+		fmt.Fprint(w, pages[r.URL.Query().Get("pageToken")])
+	})
+	service := NewPublicService(cfg)
+
+	tests := []struct {
+		name string
+		opts ListOptions
+		want string
+	}{
+		{"min", ListOptions{MinSize: 1000}, "[big huge medium edge]"},
+		{"max", ListOptions{MaxSize: 100}, "[tiny small]"},
+		{"both inclusive", ListOptions{MinSize: 100, MaxSize: 5000}, "[small big medium edge]"},
+		{"none", ListOptions{}, "[tiny small big huge medium edge]"},
+	}
+	for _, tt := range tests {
+		files, err := service.ListAll(&tt.opts)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if got := fmt.Sprint(fileIDs(files)); got != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.name, got, tt.want)
+		}
+	}
+
+	// A page filtered down to nothing still points at the next one
+	opts := &ListOptions{MinSize: 10000}
+	resp, err := service.List(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Files) != 0 || resp.NextPageToken != "p2" {
+		t.Fatalf("first page: got %+v", resp)
+	}
+
+	for _, query := range queries {
+		if query.Has("minSize") || query.Has("maxSize") {
+			t.Fatalf("sent size bounds to the API: %v", query)
+		}
+	}
+
+	queries = nil
+	if _, err := service.List(&ListOptions{MinSize: 10, MaxSize: 5}); err == nil || len(queries) != 0 {
+		t.Fatalf("inverted bounds: got %v after %d requests", err, len(queries))
+	}
+}
+
+func TestListSortBy(t *testing.T) {
+	for _, field := range []string{SortByName, SortBySize, SortByCreatedAt} {
+		for _, order := range []string{"asc", "DESC"} {
+			query, err := listQuery(t, func(s *PublicService) error {
+				_, err := s.List(&ListOptions{SortBy: field, Order: order})
+				return err
+			})
+			if err != nil {
+				t.Fatalf("%s %s: %v", field, order, err)
+			}
+			if query.Get("sortBy") != field || query.Get("order") != order {
+				t.Errorf("%s %s: sent %v", field, order, query)
+			}
+		}
+	}
+
+	query, err := listQuery(t, func(s *PublicService) error {
+		_, err := s.List(&ListOptions{Order: "asc"})
+		return err
+	})
+	if err != nil || query.Has("sortBy") {
+		t.Fatalf("order only: sent %v, %v", query, err)
+	}
+
+	for _, opts := range []ListOptions{{SortBy: "cid"}, {SortBy: "createdAt"}, {Order: "newest"}} {
+		query, err := listQuery(t, func(s *PublicService) error {
+			_, err := s.List(&opts)
+			return err
+		})
+		if err == nil || query != nil {
+			t.Errorf("%+v: got %v after sending %v", opts, err, query)
+		}
+	}
+}
+
+func TestListSetsNetwork(t *testing.T) {
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		// One file echoes a network, which the service's own network overrides
+		fmt.Fprint(w, `{"data":{"files":[{"id":"file-1"},{"id":"file-2","network":"somewhere"}],"next_page_token":""}}`)
+	})
+
+	public, err := NewPublicService(cfg).List(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	private, err := NewPrivateService(cfg).ListAll(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, file := range public.Files {
+		if file.Network != "public" {
+			t.Errorf("public %s: network %q", file.ID, file.Network)
+		}
+	}
+	for _, file := range private {
+		if file.Network != "private" {
+			t.Errorf("private %s: network %q", file.ID, file.Network)
+		}
+	}
+	if len(public.Files) != 2 || len(private) != 2 {
+		t.Fatalf("got %d and %d files", len(public.Files), len(private))
+	}
+}
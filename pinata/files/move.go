@@ -0,0 +1,48 @@
+package files
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/PinataCloud/pinata-go-sdk/pinata/types"
+	"github.com/PinataCloud/pinata-go-sdk/pinata/upload"
+)
+
+// moveLinkExpiry is how long, in seconds, the access link used to download a
+// private file being moved stays valid
+const moveLinkExpiry = 300
+
+// groupGetter looks up a group on the network of the file being moved
+type groupGetter interface {
+	GetContext(ctx context.Context, id string) (*types.Group, error)
+}
+
+// moveFileOptions returns the upload options for the copy of source, filling in
+// what opts leaves empty from the source file
+func moveFileOptions(ctx context.Context, source *types.File, opts *MoveOptions, groups groupGetter) (*upload.FileOptions, error) {
+	if source.NumberOfFiles > 1 {
+		return nil, fmt.Errorf("file %s is a folder, which cannot be moved", source.ID)
+	}
+
+	fileOpts := opts.FileOptions
+	if fileOpts.FileName == "" {
+		fileOpts.FileName = source.Name
+	}
+	if fileOpts.KeyValues == nil {
+		fileOpts.KeyValues = source.KeyValues
+	}
+	if fileOpts.GroupID == "" && fileOpts.GroupName == "" && !fileOpts.NoGroup {
+		if source.GroupID != nil {
+			group, err := groups.GetContext(ctx, *source.GroupID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get group of file to move: %w", err)
+			}
+			fileOpts.GroupName = group.Name
+		} else {
+			// Keep the copy out of the default group like the source
+			fileOpts.NoGroup = true
+		}
+	}
+
+	return &fileOpts, nil
+}
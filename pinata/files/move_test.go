@@ -0,0 +1,114 @@
+package files
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/PinataCloud/pinata-go-sdk/pinata/types"
+)
+
+// newMoveTest fakes the file, group, download and upload endpoints for moving
+// file-1, which is in group-1 ("photos"), off the network from. The same TLS
+// server acts as the gateway. Each request is recorded, uploads with their
+// form fields and content.
+func newMoveTest(t *testing.T, from string) (*types.Config, *[]string) {
+	t.Helper()
+
+	var calls []string
+	var cfg *types.Config
+	cfg = newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/files/"+from+"/file-1":
+			calls = append(calls, "get file")
+			fmt.Fprint(w, `{"data":{"id":"file-1","name":"cat.png","cid":"bafy","size":4,"group_id":"group-1","keyvalues":{"kind":"pet"}}}`)
+		case r.Method == "GET" && r.URL.Path == "/groups/"+from+"/group-1":
+			calls = append(calls, "get group")
+			fmt.Fprint(w, `{"data":{"id":"group-1","name":"photos"}}`)
+		case r.Method == "GET" && strings.HasPrefix(r.URL.Path, "/groups/"):
+			calls = append(calls, "find group "+r.URL.Query().Get("name"))
+			fmt.Fprint(w, `{"data":{"groups":[{"id":"group-9","name":"photos"}]}}`)
+		case r.Method == "POST" && r.URL.Path == "/files/private/download_link":
+			calls = append(calls, "download link")
+			fmt.Fprintf(w, `{"data":%q}`, cfg.APIUrl+"/files/bafy?X-Signature=sig")
+		case r.Method == "GET" && (r.URL.Path == "/files/bafy" || r.URL.Path == "/ipfs/bafy"):
+			calls = append(calls, "download")
+			w.Header().Set("Content-Type", "image/png")
+			fmt.Fprint(w, "meow")
+		case r.Method == "POST" && r.URL.Path == "/files":
+			file, _, err := r.FormFile("file")
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			content, _ := io.ReadAll(file)
+			calls = append(calls, fmt.Sprintf("upload network=%s name=%s group=%s keyvalues=%s content=%s",
+				r.FormValue("network"), r.FormValue("name"), r.FormValue("group_id"), r.FormValue("keyvalues"), content))
+			fmt.Fprint(w, `{"data":{"id":"file-2","name":"cat.png","cid":"bafy"}}`)
+		case r.Method == "DELETE" && r.URL.Path == "/files/"+from+"/file-1":
+			calls = append(calls, "delete")
+			fmt.Fprint(w, `{"data":null}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	return cfg, &calls
+}
+
+func TestMoveToPublic(t *testing.T) {
+	cfg, calls := newMoveTest(t, "private")
+
+	moved, err := NewPrivateService(cfg).MoveToPublic("file-1", &MoveOptions{DeleteSource: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if moved.ID != "file-2" {
+		t.Fatalf("got %+v", moved)
+	}
+
+	want := []string{
+		"get file",
+		"get group",
+		"download link",
+		"download",
+		"find group photos",
+		`upload network=public name=cat.png group=group-9 keyvalues={"kind":"pet"} content=meow`,
+		"delete",
+	}
+	if strings.Join(*calls, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("calls:\n%s\nwant:\n%s", strings.Join(*calls, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestMoveToPrivate(t *testing.T) {
+	cfg, calls := newMoveTest(t, "public")
+
+	opts := &MoveOptions{}
+	opts.FileName = "renamed.png"
+	opts.NoGroup = true
+	if _, err := NewPublicService(cfg).MoveToPrivate("file-1", opts); err != nil {
+		t.Fatal(err)
+	}
+
+	// The source is kept without DeleteSource
+	want := []string{
+		"get file",
+		"download",
+		`upload network=private name=renamed.png group= keyvalues={"kind":"pet"} content=meow`,
+	}
+	if strings.Join(*calls, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("calls:\n%s\nwant:\n%s", strings.Join(*calls, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestMoveRejectsFolders(t *testing.T) {
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data":{"id":"folder-1","cid":"bafy-dir","number_of_files":3}}`)
+	})
+
+	if _, err := NewPublicService(cfg).MoveToPrivate("folder-1", nil); err == nil || !strings.Contains(err.Error(), "is a folder") {
+		t.Fatalf("got %v", err)
+	}
+}
@@ -0,0 +1,216 @@
+package files
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/PinataCloud/pinata-go-sdk/pinata/types"
+)
+
+// pinService is the pin by CID API shared by PublicService and PrivateService
+type pinService interface {
+	PinByHash(opts *PinByHashOptions) (*types.PinByHashResponse, error)
+	Queue(opts *PinQueueOptions) (*types.PinQueueResponse, error)
+	CancelPinRequest(id string) error
+}
+
+func TestPinByHash(t *testing.T) {
+	for _, network := range []string{"public", "private"} {
+		t.Run(network, func(t *testing.T) {
+			var requests []string
+			var payload map[string]interface{}
+			cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+				requests = append(requests, r.Method+" "+r.URL.RequestURI())
+				switch r.Method {
+				case "POST":
+					json.NewDecoder(r.Body).Decode(&payload)
+					fmt.Fprint(w, `{"data":{"id":"req-1","cid":"bafy","status":"prechecking","name":"pinned"}}`)
+				case "GET":
+					fmt.Fprint(w, `{"data":{"jobs":[{"id":"req-1","cid":"bafy","status":"retrieving"}],"next_page_token":"next"}}`)
+				case "DELETE":
+					fmt.Fprint(w, `{"data":"OK"}`)
+				}
+			})
+
+			var service pinService = NewPublicService(cfg)
+			if network == "private" {
+				service = NewPrivateService(cfg)
+			}
+
+			pinned, err := service.PinByHash(&PinByHashOptions{CID: "bafy", Name: "pinned", KeyValues: map[string]string{"env": "test"}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if pinned.ID != "req-1" || pinned.Status != "prechecking" {
+				t.Fatalf("PinByHash: got %+v", pinned)
+			}
+			if payload["cid"] != "bafy" || payload["name"] != "pinned" || fmt.Sprint(payload["keyvalues"]) != "map[env:test]" {
+				t.Fatalf("PinByHash sent %v", payload)
+			}
+
+			queue, err := service.Queue(&PinQueueOptions{Status: "retrieving", Limit: 5})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(queue.Items) != 1 || queue.Items[0].ID != "req-1" || !queue.HasMore() {
+				t.Fatalf("Queue: got %+v", queue)
+			}
+
+			if err := service.CancelPinRequest("req-1"); err != nil {
+				t.Fatal(err)
+			}
+
+			prefix := "/files/" + network + "/pin_by_cid"
+			want := fmt.Sprint([]string{
+				"POST " + prefix,
+				"GET " + prefix + "?limit=5&status=retrieving",
+				"DELETE " + prefix + "/req-1",
+			})
+			if fmt.Sprint(requests) != want {
+				t.Fatalf("requests %v, want %v", requests, want)
+			}
+		})
+	}
+}
+
+func TestPinByHashRequiresCID(t *testing.T) {
+	service := NewPrivateService(&types.Config{PinataJWT: "jwt"})
+
+	if _, err := service.PinByHashContext(context.Background(), &PinByHashOptions{}); !errors.Is(err, ErrNoCID) {
+		t.Fatalf("got %v, want ErrNoCID", err)
+	}
+	if err := service.CancelPinRequest(""); err == nil {
+		t.Fatal("CancelPinRequest without an ID succeeded")
+	}
+}
+
+func TestCancelAllPinRequests(t *testing.T) {
+	pages := map[string]string{
+		"":   `{"data":{"jobs":[{"id":"req-1"},{"id":"req-2"}],"next_page_token":"p2"}}`,
+		"p2": `{"data":{"jobs":[{"id":"req-3"},{"id":"stuck-4"}],"next_page_token":"p3"}}`,
+		"p3": `{"data":{"jobs":[{"id":"req-5"}],"next_page_token":""}}`,
+	}
+
+	var mu sync.Mutex
+	var statuses, cancelled []string
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch r.Method {
+		case "GET":
+			statuses = append(statuses, r.URL.Query().Get("status"))
+			fmt.Fprint(w, pages[r.URL.Query().Get("pageToken")])
+		case "DELETE":
+			id := strings.TrimPrefix(r.URL.Path, "/files/private/pin_by_cid/")
+			if strings.HasPrefix(id, "stuck") {
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprint(w, `{"error":"request already completed"}`)
+				return
+			}
+			cancelled = append(cancelled, id)
+			fmt.Fprint(w, `{"data":"OK"}`)
+		}
+	})
+
+	done, failed, err := NewPrivateService(cfg).CancelAllPinRequests("retrieving")
+	if done != 4 || failed != 1 {
+		t.Fatalf("cancelled %d and failed %d, want 4 and 1", done, failed)
+	}
+	if err == nil || err.Error() != "1 of 5 cancellations failed" {
+		t.Fatalf("got error %v, want 1 of 5 cancellations failed", err)
+	}
+
+	sort.Strings(cancelled)
+	if fmt.Sprint(cancelled) != "[req-1 req-2 req-3 req-5]" {
+		t.Fatalf("cancelled %v", cancelled)
+	}
+	if fmt.Sprint(statuses) != "[retrieving retrieving retrieving]" {
+		t.Fatalf("queue pages requested with statuses %q", statuses)
+	}
+}
+
+func TestQueueAllFollowsPageTokens(t *testing.T) {
+	pages := map[string]string{
+		"":   `{"data":{"jobs":[{"id":"req-1"},{"id":"req-2"}],"next_page_token":"p2"}}`,
+		"p2": `{"data":{"jobs":[{"id":"req-3"}],"next_page_token":"p3"}}`,
+		"p3": `{"data":{"jobs":[{"id":"req-4"}],"next_page_token":""}}`,
+	}
+	var requests []string
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		requests = append(requests, r.URL.Path+" status="+query.Get("status")+" page="+query.Get("pageToken"))
+		fmt.Fprint(w, pages[query.Get("pageToken")])
+	})
+
+	items, err := NewPublicService(cfg).QueueAll(&PinQueueOptions{Status: "prechecking"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, item := range items {
+		ids = append(ids, item.ID)
+	}
+	if fmt.Sprint(ids) != "[req-1 req-2 req-3 req-4]" {
+		t.Fatalf("got jobs %v", ids)
+	}
+	want := "/files/public/pin_by_cid status=prechecking page=," +
+		"/files/public/pin_by_cid status=prechecking page=p2," +
+		"/files/public/pin_by_cid status=prechecking page=p3"
+	if strings.Join(requests, ",") != want {
+		t.Fatalf("requested %q", requests)
+	}
+
+	requests = nil
+	it := NewPrivateService(cfg).QueueIter(&PinQueueOptions{Status: "retrieving"})
+	count := 0
+	for it.Next() {
+		count++
+	}
+	if it.Err() != nil || count != 4 || len(requests) != 3 {
+		t.Fatalf("iterated %d jobs in %d requests, err %v", count, len(requests), it.Err())
+	}
+	if !strings.HasPrefix(requests[2], "/files/private/pin_by_cid status=retrieving page=p3") {
+		t.Fatalf("last request %q", requests[2])
+	}
+}
+
+func TestPinByHashHostNodes(t *testing.T) {
+	const node = "/ip4/203.0.113.7/tcp/4001/p2p/12D3KooWGRUVh7BmGHUBS1nqXvsfNMkjwQH9ASBSHBDYfJLVuV7d"
+
+	var payloads []map[string]interface{}
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&payload)
+		payloads = append(payloads, payload)
+		fmt.Fprint(w, `{"data":{"id":"req-1","cid":"bafy","status":"prechecking"}}`)
+	})
+	service := NewPrivateService(cfg)
+
+	opts, err := (&PinByHashOptions{CID: "bafy"}).WithHostNodes(node)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.PinByHash(opts); err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(payloads[0]["host_nodes"]) != "["+node+"]" {
+		t.Fatalf("sent %v", payloads[0])
+	}
+
+	var invalid *types.InvalidHostNodesError
+	if _, err := (&PinByHashOptions{CID: "bafy"}).WithHostNodes(node, "not-a-multiaddr"); !errors.As(err, &invalid) {
+		t.Fatalf("WithHostNodes: got %v", err)
+	}
+	_, err = service.PinByHash(&PinByHashOptions{CID: "bafy", HostNodes: []string{"/ip4/1.2.3.4/tcp/0"}})
+	if !errors.As(err, &invalid) || len(payloads) != 1 {
+		t.Fatalf("PinByHash: got %v after %d requests", err, len(payloads))
+	}
+}
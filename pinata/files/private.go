@@ -1,74 +1,193 @@
 package files
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"os"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/PinataCloud/pinata-go-sdk/pinata/groups"
+	"github.com/PinataCloud/pinata-go-sdk/pinata/internal/batch"
+	"github.com/PinataCloud/pinata-go-sdk/pinata/internal/request"
 	"github.com/PinataCloud/pinata-go-sdk/pinata/types"
+	"github.com/PinataCloud/pinata-go-sdk/pinata/upload"
 )
 
 // PrivateService provides operations for managing files on the private IPFS network
 type PrivateService struct {
 	config interface{}
+	cache  *fileCache
 }
 
 // NewPrivateService creates a new PrivateService with the provided configuration
 func NewPrivateService(config interface{}) *PrivateService {
 	return &PrivateService{
 		config: config,
+		cache:  newFileCache(config),
 	}
 }
 
 // Get retrieves a file by ID from the private IPFS network
 func (s *PrivateService) Get(id string) (*types.File, error) {
+	return s.GetContext(context.Background(), id)
+}
+
+// GetContext is like Get but carries ctx through to the underlying requests
+func (s *PrivateService) GetContext(ctx context.Context, id string) (*types.File, error) {
+	if id == "" {
+		return nil, ErrNoFileID
+	}
+
+	key := cacheKey("private", id)
+	if file, ok := s.cache.get(key); ok {
+		return file, nil
+	}
+
+	file, err := s.fetch(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	s.cache.put(key, file)
+	return file, nil
+}
+
+// GetMany retrieves several files by ID concurrently, e.g. to hydrate a list of
+// IDs. Duplicate IDs are fetched once. Every ID ends up in exactly one of the
+// returned maps: files holds the ones that were found and errs the error for
+// each one that was not, such as an API error with a 404 status.
+func (s *PrivateService) GetMany(ids []string) (files map[string]*types.File, errs map[string]error) {
+	return s.GetManyContext(context.Background(), ids)
+}
+
+// GetManyContext is like GetMany but carries ctx through to the underlying requests
+func (s *PrivateService) GetManyContext(ctx context.Context, ids []string) (files map[string]*types.File, errs map[string]error) {
+	files = make(map[string]*types.File, len(ids))
+	errs = make(map[string]error)
+
+	unique := make([]string, 0, len(ids))
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		if id == "" {
+			errs[id] = ErrNoFileID
+			continue
+		}
+		unique = append(unique, id)
+	}
+
+	results := make([]*types.File, len(unique))
+	failures, _ := batch.RunAll(ctx, len(unique), batch.DefaultConcurrency, "gets", func(ctx context.Context, i int) error {
+		var err error
+		results[i], err = s.GetContext(ctx, unique[i])
+		return err
+	})
+
+	for i, id := range unique {
+		switch {
+		case errors.Is(failures[i], batch.ErrNotAttempted):
+			// Only possible once ctx is done
+			errs[id] = fmt.Errorf("%w: %w", batch.ErrNotAttempted, ctx.Err())
+		case failures[i] != nil:
+			errs[id] = failures[i]
+		default:
+			files[id] = results[i]
+		}
+	}
+
+	return files, errs
+}
+
+// InvalidateCache drops the cached Get result for id. Update, Delete, Vectorize
+// and DeleteVectors do this automatically; it is only needed when the file is
+// changed by other means.
+func (s *PrivateService) InvalidateCache(id string) {
+	s.cache.remove(cacheKey("private", id))
+}
+
+// fetch retrieves a file by ID, bypassing the cache
+func (s *PrivateService) fetch(ctx context.Context, id string) (*types.File, error) {
 	cfg := s.config.(*types.Config)
 	url := fmt.Sprintf("%s/files/private/%s", cfg.APIUrl, id)
 
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+	var response *types.File
+	if err := request.DoRequest(ctx, cfg, "GET", url, nil, &response); err != nil {
+		return nil, err
 	}
 
-	req.Header.Set("Authorization", "Bearer "+cfg.PinataJWT)
-	req.Header.Set("Content-Type", "application/json")
+	return response, nil
+}
 
-	// Add custom headers if any
-	for key, value := range cfg.CustomHeaders {
-		req.Header.Set(key, value)
+// GetByCID retrieves the file with the given CID from the private IPFS network. It returns
+// ErrFileNotFound if no file matches and ErrMultipleFiles if more than one does.
+func (s *PrivateService) GetByCID(cid string) (*types.File, error) {
+	return s.GetByCIDContext(context.Background(), cid)
+}
+
+// GetByCIDContext is like GetByCID but carries ctx through to the underlying requests
+func (s *PrivateService) GetByCIDContext(ctx context.Context, cid string) (*types.File, error) {
+	if cid == "" {
+		return nil, ErrNoCID
 	}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	// Two results are enough to tell a unique match from an ambiguous one
+	list, err := s.ListContext(ctx, &ListOptions{CID: cid, Limit: 2})
 	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+	var files []types.File
+	if list != nil {
+		files = list.Files
 	}
 
-	var response struct {
-		Data *types.File `json:"data"`
+	switch len(files) {
+	case 0:
+		return nil, fmt.Errorf("%w: %s", ErrFileNotFound, cid)
+	case 1:
+		return &files[0], nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrMultipleFiles, cid)
 	}
+}
+
+// Exists reports whether at least one file on the private IPFS network has the given CID,
+// which lets callers skip uploading content that is already pinned
+func (s *PrivateService) Exists(cid string) (bool, error) {
+	return s.ExistsContext(context.Background(), cid)
+}
 
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+// ExistsContext is like Exists but carries ctx through to the underlying requests
+func (s *PrivateService) ExistsContext(ctx context.Context, cid string) (bool, error) {
+	if cid == "" {
+		return false, ErrNoCID
+	}
+
+	list, err := s.ListContext(ctx, &ListOptions{CID: cid, Limit: 1})
+	if err != nil {
+		return false, err
 	}
 
-	return response.Data, nil
+	return list != nil && len(list.Files) > 0, nil
 }
 
 // List retrieves a list of files from the private IPFS network
 func (s *PrivateService) List(opts *ListOptions) (*types.FileListResponse, error) {
+	return s.ListContext(context.Background(), opts)
+}
+
+// ListContext is like List but carries ctx through to the underlying requests
+func (s *PrivateService) ListContext(ctx context.Context, opts *ListOptions) (*types.FileListResponse, error) {
 	cfg := s.config.(*types.Config)
 	baseURL := fmt.Sprintf("%s/files/private", cfg.APIUrl)
 
@@ -76,6 +195,16 @@ func (s *PrivateService) List(opts *ListOptions) (*types.FileListResponse, error
 	params := url.Values{}
 
 	if opts != nil {
+		if !opts.CreatedAfter.IsZero() && !opts.CreatedBefore.IsZero() && opts.CreatedAfter.After(opts.CreatedBefore) {
+			return nil, fmt.Errorf("CreatedAfter must not be later than CreatedBefore")
+		}
+		if opts.MinSize > 0 && opts.MaxSize > 0 && opts.MinSize > opts.MaxSize {
+			return nil, fmt.Errorf("MinSize must not be larger than MaxSize")
+		}
+		if err := opts.checkSort(); err != nil {
+			return nil, err
+		}
+
 		if opts.Name != "" {
 			params.Add("name", opts.Name)
 		}
@@ -97,12 +226,21 @@ func (s *PrivateService) List(opts *ListOptions) (*types.FileListResponse, error
 		if opts.Order != "" {
 			params.Add("order", opts.Order)
 		}
+		if opts.SortBy != "" {
+			params.Add("sortBy", opts.SortBy)
+		}
 		if opts.Limit > 0 {
 			params.Add("limit", strconv.Itoa(opts.Limit))
 		}
 		if opts.PageToken != "" {
 			params.Add("pageToken", opts.PageToken)
 		}
+		if !opts.CreatedAfter.IsZero() {
+			params.Add("createdAfter", opts.CreatedAfter.UTC().Format(time.RFC3339))
+		}
+		if !opts.CreatedBefore.IsZero() {
+			params.Add("createdBefore", opts.CreatedBefore.UTC().Format(time.RFC3339))
+		}
 
 		// Add keyvalues if present
 		if len(opts.KeyValues) > 0 {
@@ -110,6 +248,12 @@ func (s *PrivateService) List(opts *ListOptions) (*types.FileListResponse, error
 				params.Add(fmt.Sprintf("keyvalues[%s]", key), value)
 			}
 		}
+		for key, filter := range opts.KeyValueFilters {
+			params.Add(fmt.Sprintf("keyvalues[%s][value]", key), filter.Value)
+			if filter.Op != "" {
+				params.Add(fmt.Sprintf("keyvalues[%s][op]", key), filter.Op)
+			}
+		}
 	}
 
 	// Append query parameters if any
@@ -118,148 +262,371 @@ func (s *PrivateService) List(opts *ListOptions) (*types.FileListResponse, error
 		requestURL = fmt.Sprintf("%s?%s", baseURL, params.Encode())
 	}
 
-	req, err := http.NewRequest("GET", requestURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+	var response *types.FileListResponse
+	if err := request.DoRequest(ctx, cfg, "GET", requestURL, nil, &response); err != nil {
+		return nil, err
 	}
 
-	req.Header.Set("Authorization", "Bearer "+cfg.PinataJWT)
-	req.Header.Set("Content-Type", "application/json")
-
-	// Add custom headers if any
-	for key, value := range cfg.CustomHeaders {
-		req.Header.Set(key, value)
+	// Size is filtered here since the API has no parameter for it, and the
+	// network is filled in since the list endpoint does not always echo it
+	if response != nil {
+		response.Files = opts.filterSize(response.Files)
+		for i := range response.Files {
+			response.Files[i].Network = "private"
+		}
 	}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
+	return response, nil
+}
+
+// ListAll retrieves every file matching opts from the private IPFS network, following
+// NextPageToken until all pages have been read. opts.Limit sets the page size.
+func (s *PrivateService) ListAll(opts *ListOptions) ([]types.File, error) {
+	return s.ListAllContext(context.Background(), opts)
+}
+
+// ListAllContext is like ListAll but carries ctx through to the underlying requests
+func (s *PrivateService) ListAllContext(ctx context.Context, opts *ListOptions) ([]types.File, error) {
+	return collectFiles(s.ListIterContext(ctx, opts))
+}
+
+// ListIter returns an iterator over every file matching opts on the private IPFS
+// network. Pages are fetched lazily, so large result sets are never held in memory.
+func (s *PrivateService) ListIter(opts *ListOptions) *FileIterator {
+	return s.ListIterContext(context.Background(), opts)
+}
+
+// ListIterContext is like ListIter but carries ctx through to the underlying requests
+func (s *PrivateService) ListIterContext(ctx context.Context, opts *ListOptions) *FileIterator {
+	return newFileIterator(ctx, s.ListContext, opts)
+}
+
+// Update updates file metadata
+func (s *PrivateService) Update(opts *UpdateOptions) (*types.File, error) {
+	return s.UpdateContext(context.Background(), opts)
+}
+
+// UpdateContext is like Update but carries ctx through to the underlying requests
+func (s *PrivateService) UpdateContext(ctx context.Context, opts *UpdateOptions) (*types.File, error) {
+	if opts == nil || opts.ID == "" {
+		return nil, ErrNoFileID
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+	if opts.MergeKeyValues && len(opts.KeyValues) > 0 {
+		current, err := s.fetch(ctx, opts.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get current keyvalues: %w", err)
+		}
+
+		merged := make(map[string]string, len(current.KeyValues)+len(opts.KeyValues))
+		for key, value := range current.KeyValues {
+			merged[key] = value
+		}
+		for key, value := range opts.KeyValues {
+			merged[key] = value
+		}
+
+		mergedOpts := *opts
+		mergedOpts.KeyValues = merged
+		opts = &mergedOpts
 	}
 
-	var response struct {
-		Data *types.FileListResponse `json:"data"`
+	return s.put(ctx, opts.ID, opts)
+}
+
+// UpdateBatch applies several updates, for example to retag many files at once.
+// The updates run concurrently through Update and every one is attempted: the
+// returned slice holds one entry per update, in order, with Status "updated" or
+// "failed", and the error is non-nil if any update failed.
+func (s *PrivateService) UpdateBatch(updates []UpdateOptions) ([]types.UpdateBatchResponse, error) {
+	return s.UpdateBatchContext(context.Background(), updates)
+}
+
+// UpdateBatchContext is like UpdateBatch but carries ctx through to the underlying requests
+func (s *PrivateService) UpdateBatchContext(ctx context.Context, updates []UpdateOptions) ([]types.UpdateBatchResponse, error) {
+	if len(updates) == 0 {
+		return nil, fmt.Errorf("%w: pass at least one update", ErrNoFileID)
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	responses := make([]types.UpdateBatchResponse, len(updates))
+	errs, err := batch.RunAll(ctx, len(updates), batch.DefaultConcurrency, "updates", func(ctx context.Context, i int) error {
+		file, err := s.UpdateContext(ctx, &updates[i])
+		responses[i].File = file
+		return err
+	})
+
+	for i, update := range updates {
+		responses[i].ID = update.ID
+		responses[i].Status, responses[i].Error = batch.Outcome(errs[i], "updated")
 	}
 
-	return response.Data, nil
+	return responses, err
 }
 
-// Update updates file metadata
-func (s *PrivateService) Update(opts *UpdateOptions) (*types.File, error) {
-	if opts == nil || opts.ID == "" {
-		return nil, fmt.Errorf("file ID is required")
+// RemoveKeyValues deletes the named keys from a file's keyvalues, leaving the
+// rest in place. Keys the file does not have are ignored.
+func (s *PrivateService) RemoveKeyValues(id string, keys []string) (*types.File, error) {
+	return s.RemoveKeyValuesContext(context.Background(), id, keys)
+}
+
+// RemoveKeyValuesContext is like RemoveKeyValues but carries ctx through to the underlying requests
+func (s *PrivateService) RemoveKeyValuesContext(ctx context.Context, id string, keys []string) (*types.File, error) {
+	if id == "" {
+		return nil, ErrNoFileID
+	}
+
+	current, err := s.fetch(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current keyvalues: %w", err)
+	}
+
+	remaining := make(map[string]string, len(current.KeyValues))
+	for key, value := range current.KeyValues {
+		remaining[key] = value
 	}
+	for _, key := range keys {
+		delete(remaining, key)
+	}
+
+	if len(remaining) == len(current.KeyValues) {
+		return current, nil
+	}
+
+	// keyvalues is sent even when empty so removing the last key clears the map
+	payload := struct {
+		KeyValues map[string]string `json:"keyvalues"`
+	}{KeyValues: remaining}
+
+	return s.put(ctx, id, payload)
+}
+
+// put sends body as the new metadata for the file with the given ID
+func (s *PrivateService) put(ctx context.Context, id string, body interface{}) (*types.File, error) {
+	defer s.InvalidateCache(id)
 
 	cfg := s.config.(*types.Config)
-	url := fmt.Sprintf("%s/files/private/%s", cfg.APIUrl, opts.ID)
+	url := fmt.Sprintf("%s/files/private/%s", cfg.APIUrl, id)
+
+	var response *types.File
+	if err := request.DoRequest(ctx, cfg, "PUT", url, body, &response); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// Replace uploads new content for the file with the given ID while keeping its
+// metadata, and swaps the old CID to the new one so existing gateway links serve
+// the new content. The swap is skipped when the content is unchanged. It returns
+// the new upload and the swap record.
+func (s *PrivateService) Replace(id string, file *os.File, opts *ReplaceOptions) (*types.UploadResponse, *types.SwapResponse, error) {
+	return s.ReplaceContext(context.Background(), id, file, opts)
+}
+
+// ReplaceContext is like Replace but carries ctx through to the underlying requests
+func (s *PrivateService) ReplaceContext(ctx context.Context, id string, file *os.File, opts *ReplaceOptions) (*types.UploadResponse, *types.SwapResponse, error) {
+	if id == "" {
+		return nil, nil, ErrNoFileID
+	}
+	if opts == nil {
+		opts = &ReplaceOptions{}
+	}
 
-	payload, err := json.Marshal(opts)
+	old, err := s.fetch(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request body: %w", err)
+		return nil, nil, fmt.Errorf("failed to get file to replace: %w", err)
 	}
 
-	req, err := http.NewRequest("PUT", url, bytes.NewBuffer(payload))
+	fileOpts := opts.FileOptions
+	if fileOpts.FileName == "" {
+		fileOpts.FileName = old.Name
+	}
+	if fileOpts.GroupID == "" && fileOpts.GroupName == "" && !fileOpts.NoGroup {
+		if old.GroupID != nil {
+			fileOpts.GroupID = *old.GroupID
+		} else {
+			// Keep the replacement out of the default group like the original
+			fileOpts.NoGroup = true
+		}
+	}
+	if fileOpts.KeyValues == nil {
+		fileOpts.KeyValues = old.KeyValues
+	}
+
+	uploaded, err := upload.NewPrivateService(s.config).FileContext(ctx, file, &fileOpts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, nil, fmt.Errorf("failed to upload replacement: %w", err)
 	}
 
-	req.Header.Set("Authorization", "Bearer "+cfg.PinataJWT)
-	req.Header.Set("Content-Type", "application/json")
+	var swap *types.SwapResponse
+	if uploaded.CID != old.CID {
+		swap, err = s.AddSwapContext(ctx, &SwapOptions{CID: old.CID, SwapCID: uploaded.CID})
+		if err != nil {
+			return uploaded, nil, fmt.Errorf("failed to swap %s to %s: %w", old.CID, uploaded.CID, err)
+		}
+	}
 
-	// Add custom headers if any
-	for key, value := range cfg.CustomHeaders {
-		req.Header.Set(key, value)
+	if opts.DeleteOld && uploaded.ID != id {
+		if err := s.deleteOne(ctx, id); err != nil {
+			return uploaded, swap, fmt.Errorf("failed to delete replaced file: %w", err)
+		}
+	}
+
+	return uploaded, swap, nil
+}
+
+// MoveToPublic copies a private file to the public network by downloading its content
+// and uploading it again, carrying over its name, keyvalues and group. With
+// opts.DeleteSource the source file is deleted afterwards. It returns the new
+// file's record, which has a new ID. Folders cannot be moved.
+func (s *PrivateService) MoveToPublic(id string, opts *MoveOptions) (*types.UploadResponse, error) {
+	return s.MoveToPublicContext(context.Background(), id, opts)
+}
+
+// MoveToPublicContext is like MoveToPublic but carries ctx through to the underlying requests
+func (s *PrivateService) MoveToPublicContext(ctx context.Context, id string, opts *MoveOptions) (*types.UploadResponse, error) {
+	if id == "" {
+		return nil, ErrNoFileID
+	}
+	if opts == nil {
+		opts = &MoveOptions{}
 	}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	source, err := s.fetch(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
+		return nil, fmt.Errorf("failed to get file to move: %w", err)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+	fileOpts, err := moveFileOptions(ctx, source, opts, groups.NewPrivateService(s.config))
+	if err != nil {
+		return nil, err
 	}
 
-	var response struct {
-		Data *types.File `json:"data"`
+	body, contentType, err := s.DownloadStreamContext(ctx, source.CID, moveLinkExpiry)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download file to move: %w", err)
 	}
+	defer body.Close()
 
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	data := upload.NewCustomFileData(body, fileOpts.FileName, source.Size, contentType)
+	moved, err := upload.NewPublicService(s.config).FileReaderContext(ctx, data, fileOpts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload file to the public network: %w", err)
 	}
 
-	return response.Data, nil
+	if opts.DeleteSource {
+		if err := s.deleteOne(ctx, id); err != nil {
+			return moved, fmt.Errorf("failed to delete source file: %w", err)
+		}
+	}
+
+	return moved, nil
 }
 
-// Delete removes files by their IDs
+// Delete removes files by their IDs. The deletes run concurrently and every ID is
+// attempted: the returned slice holds one entry per ID, in order, with Status
+// "deleted" or "failed", and the error is non-nil if any delete failed.
 func (s *PrivateService) Delete(ids []string) ([]types.DeleteResponse, error) {
+	return s.DeleteContext(context.Background(), ids)
+}
+
+// DeleteContext is like Delete but carries ctx through to the underlying requests
+func (s *PrivateService) DeleteContext(ctx context.Context, ids []string) ([]types.DeleteResponse, error) {
 	if len(ids) == 0 {
-		return nil, fmt.Errorf("at least one file ID is required")
+		return nil, fmt.Errorf("%w: pass at least one", ErrNoFileID)
 	}
 
-	cfg := s.config.(*types.Config)
+	responses := make([]types.DeleteResponse, len(ids))
+	errs, err := batch.RunAll(ctx, len(ids), batch.DefaultConcurrency, "deletes", func(ctx context.Context, i int) error {
+		return s.deleteOne(ctx, ids[i])
+	})
 
-	var responses []types.DeleteResponse
+	for i, id := range ids {
+		responses[i].ID = id
+		responses[i].Status, responses[i].Error = batch.Outcome(errs[i], "deleted")
+	}
 
-	// Process each ID individually
-	for _, id := range ids {
-		url := fmt.Sprintf("%s/files/private/%s", cfg.APIUrl, id)
+	return responses, err
+}
 
-		req, err := http.NewRequest("DELETE", url, nil)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create request: %w", err)
-		}
+// DeleteByCID deletes the files with the given CIDs from the private IPFS network,
+// looking up their IDs first. The returned slice is aligned with cids.
+func (s *PrivateService) DeleteByCID(cids []string, opts *DeleteByCIDOptions) ([]types.DeleteByCIDResponse, error) {
+	return s.DeleteByCIDContext(context.Background(), cids, opts)
+}
 
-		req.Header.Set("Authorization", "Bearer "+cfg.PinataJWT)
-		req.Header.Set("Content-Type", "application/json")
+// DeleteByCIDContext is like DeleteByCID but carries ctx through to the underlying requests
+func (s *PrivateService) DeleteByCIDContext(ctx context.Context, cids []string, opts *DeleteByCIDOptions) ([]types.DeleteByCIDResponse, error) {
+	if len(cids) == 0 {
+		return nil, fmt.Errorf("%w: pass at least one", ErrNoCID)
+	}
 
-		// Add custom headers if any
-		for key, value := range cfg.CustomHeaders {
-			req.Header.Set(key, value)
-		}
+	responses := make([]types.DeleteByCIDResponse, len(cids))
+	errs, err := batch.RunAll(ctx, len(cids), batch.DefaultConcurrency, "CID deletes", func(ctx context.Context, i int) error {
+		ids, err := s.deleteCID(ctx, cids[i], opts)
+		responses[i].IDs = ids
+		return err
+	})
 
-		client := &http.Client{}
-		resp, err := client.Do(req)
-		if err != nil {
-			return nil, fmt.Errorf("failed to send request: %w", err)
-		}
+	for i, cid := range cids {
+		responses[i].CID = cid
+		responses[i].Status, responses[i].Error = batch.Outcome(errs[i], "deleted")
+	}
 
-		// Check response status
-		if resp.StatusCode != http.StatusOK {
-			body, _ := io.ReadAll(resp.Body)
-			resp.Body.Close()
-			return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
-		}
+	return responses, err
+}
+
+// deleteCID deletes the files with cid and returns the IDs of those deleted
+func (s *PrivateService) deleteCID(ctx context.Context, cid string, opts *DeleteByCIDOptions) ([]string, error) {
+	if cid == "" {
+		return nil, ErrNoCID
+	}
 
-		// Add to successful deletions
-		responses = append(responses, types.DeleteResponse{
-			ID:     id,
-			Status: "deleted",
-		})
+	files, err := s.ListAllContext(ctx, &ListOptions{CID: cid})
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up files: %w", err)
+	}
 
-		resp.Body.Close()
+	switch {
+	case len(files) == 0:
+		return nil, fmt.Errorf("%w: %s", ErrFileNotFound, cid)
+	case len(files) > 1 && (opts == nil || !opts.AllowMultiple):
+		return nil, fmt.Errorf("%w: %s has %d files", ErrMultipleFiles, cid, len(files))
 	}
 
-	return responses, nil
+	deleted := make([]string, 0, len(files))
+	for _, file := range files {
+		if err := s.deleteOne(ctx, file.ID); err != nil {
+			return deleted, fmt.Errorf("failed to delete file %s: %w", file.ID, err)
+		}
+		deleted = append(deleted, file.ID)
+	}
+
+	return deleted, nil
+}
+
+// deleteOne removes a single file by ID
+func (s *PrivateService) deleteOne(ctx context.Context, id string) error {
+	defer s.InvalidateCache(id)
+
+	cfg := s.config.(*types.Config)
+	url := fmt.Sprintf("%s/files/private/%s", cfg.APIUrl, id)
+
+	return request.DoRequest(ctx, cfg, "DELETE", url, nil, nil)
 }
 
 // AddSwap creates a CID swap
 func (s *PrivateService) AddSwap(opts *SwapOptions) (*types.SwapResponse, error) {
-	if opts == nil || opts.CID == "" || opts.SwapCID == "" {
-		return nil, fmt.Errorf("CID and swap CID are required")
+	return s.AddSwapContext(context.Background(), opts)
+}
+
+// AddSwapContext is like AddSwap but carries ctx through to the underlying requests
+func (s *PrivateService) AddSwapContext(ctx context.Context, opts *SwapOptions) (*types.SwapResponse, error) {
+	if opts == nil || opts.CID == "" {
+		return nil, ErrNoCID
+	}
+	if opts.SwapCID == "" {
+		return nil, ErrMissingSwapCID
 	}
 
 	cfg := s.config.(*types.Config)
@@ -271,133 +638,298 @@ func (s *PrivateService) AddSwap(opts *SwapOptions) (*types.SwapResponse, error)
 		SwapCID: opts.SwapCID,
 	}
 
-	jsonData, err := json.Marshal(payload)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request body: %w", err)
+	var response *types.SwapResponse
+	if err := request.DoRequest(ctx, cfg, "PUT", url, payload, &response); err != nil {
+		return nil, err
 	}
 
-	req, err := http.NewRequest("PUT", url, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+	if response != nil && response.CID == "" {
+		response.CID = opts.CID
 	}
 
-	req.Header.Set("Authorization", "Bearer "+cfg.PinataJWT)
-	req.Header.Set("Content-Type", "application/json")
+	return response, nil
+}
 
-	// Add custom headers if any
-	for key, value := range cfg.CustomHeaders {
-		req.Header.Set(key, value)
-	}
+// GetSwapHistory retrieves the swap history for a CID
+func (s *PrivateService) GetSwapHistory(opts *SwapHistoryOptions) ([]types.SwapResponse, error) {
+	return s.GetSwapHistoryContext(context.Background(), opts)
+}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
+// GetSwapHistoryContext is like GetSwapHistory but carries ctx through to the underlying requests
+func (s *PrivateService) GetSwapHistoryContext(ctx context.Context, opts *SwapHistoryOptions) ([]types.SwapResponse, error) {
+	if opts == nil || opts.CID == "" {
+		return nil, ErrNoCID
+	}
+	if opts.Domain == "" {
+		return nil, fmt.Errorf("domain is required")
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+	cfg := s.config.(*types.Config)
+	url := fmt.Sprintf("%s/files/private/swap/%s?domain=%s", cfg.APIUrl, opts.CID, url.QueryEscape(opts.Domain))
+
+	var response []types.SwapResponse
+	if err := request.DoRequest(ctx, cfg, "GET", url, nil, &response); err != nil {
+		return nil, err
 	}
 
-	var response struct {
-		Data *types.SwapResponse `json:"data"`
+	for i := range response {
+		if response[i].CID == "" {
+			response[i].CID = opts.CID
+		}
+		if response[i].Domain == "" {
+			response[i].Domain = opts.Domain
+		}
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	return response, nil
+}
+
+// DeleteSwap removes a CID swap
+func (s *PrivateService) DeleteSwap(cid string) error {
+	return s.DeleteSwapContext(context.Background(), cid)
+}
+
+// DeleteSwapContext is like DeleteSwap but carries ctx through to the underlying requests
+func (s *PrivateService) DeleteSwapContext(ctx context.Context, cid string) error {
+	if cid == "" {
+		return ErrNoCID
 	}
 
-	return response.Data, nil
+	cfg := s.config.(*types.Config)
+	url := fmt.Sprintf("%s/files/private/swap/%s", cfg.APIUrl, cid)
+
+	return request.DoRequest(ctx, cfg, "DELETE", url, nil, nil)
 }
 
-// GetSwapHistory retrieves the swap history for a CID
-func (s *PrivateService) GetSwapHistory(opts *SwapHistoryOptions) ([]types.SwapResponse, error) {
-	if opts == nil || opts.CID == "" || opts.Domain == "" {
-		return nil, fmt.Errorf("CID and domain are required")
+// PinByHash pins a CID that already exists on IPFS to the private network
+func (s *PrivateService) PinByHash(opts *PinByHashOptions) (*types.PinByHashResponse, error) {
+	return s.PinByHashContext(context.Background(), opts)
+}
+
+// PinByHashContext is like PinByHash but carries ctx through to the underlying requests
+func (s *PrivateService) PinByHashContext(ctx context.Context, opts *PinByHashOptions) (*types.PinByHashResponse, error) {
+	if opts == nil || opts.CID == "" {
+		return nil, ErrNoCID
+	}
+	if err := types.ValidateHostNodes(opts.HostNodes); err != nil {
+		return nil, err
 	}
 
 	cfg := s.config.(*types.Config)
-	url := fmt.Sprintf("%s/files/private/swap/%s?domain=%s", cfg.APIUrl, opts.CID, url.QueryEscape(opts.Domain))
+	url := fmt.Sprintf("%s/files/private/pin_by_cid", cfg.APIUrl)
 
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+	var response *types.PinByHashResponse
+	if err := request.DoRequest(ctx, cfg, "POST", url, opts, &response); err != nil {
+		return nil, err
 	}
 
-	req.Header.Set("Authorization", "Bearer "+cfg.PinataJWT)
-	req.Header.Set("Content-Type", "application/json")
+	return response, nil
+}
 
-	// Add custom headers if any
-	for key, value := range cfg.CustomHeaders {
-		req.Header.Set(key, value)
+// Queue returns a list of pin by hash requests
+func (s *PrivateService) Queue(opts *PinQueueOptions) (*types.PinQueueResponse, error) {
+	return s.QueueContext(context.Background(), opts)
+}
+
+// QueueContext is like Queue but carries ctx through to the underlying requests
+func (s *PrivateService) QueueContext(ctx context.Context, opts *PinQueueOptions) (*types.PinQueueResponse, error) {
+	cfg := s.config.(*types.Config)
+	baseURL := fmt.Sprintf("%s/files/private/pin_by_cid", cfg.APIUrl)
+
+	// Build query parameters
+	params := url.Values{}
+
+	if opts != nil {
+		if opts.Sort != "" {
+			params.Add("order", opts.Sort)
+		}
+		if opts.Status != "" {
+			params.Add("status", opts.Status)
+		}
+		if opts.CID != "" {
+			params.Add("cid", opts.CID)
+		}
+		if opts.Limit > 0 {
+			params.Add("limit", strconv.Itoa(opts.Limit))
+		}
+		if opts.PageToken != "" {
+			params.Add("pageToken", opts.PageToken)
+		}
 	}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	// Append query parameters if any
+	requestURL := baseURL
+	if len(params) > 0 {
+		requestURL = fmt.Sprintf("%s?%s", baseURL, params.Encode())
+	}
+
+	var response *types.PinQueueResponse
+	if err := request.DoRequest(ctx, cfg, "GET", requestURL, nil, &response); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// QueueAll retrieves every pin by hash request matching opts, following
+// NextPageToken until all pages have been read. opts.Limit sets the page size.
+func (s *PrivateService) QueueAll(opts *PinQueueOptions) ([]types.PinQueueItem, error) {
+	return s.QueueAllContext(context.Background(), opts)
+}
+
+// QueueAllContext is like QueueAll but carries ctx through to the underlying requests
+func (s *PrivateService) QueueAllContext(ctx context.Context, opts *PinQueueOptions) ([]types.PinQueueItem, error) {
+	return collectPinQueue(s.QueueIterContext(ctx, opts))
+}
+
+// QueueIter returns an iterator over every pin by hash request matching opts.
+// Pages are fetched lazily.
+func (s *PrivateService) QueueIter(opts *PinQueueOptions) *PinQueueIterator {
+	return s.QueueIterContext(context.Background(), opts)
+}
+
+// QueueIterContext is like QueueIter but carries ctx through to the underlying requests
+func (s *PrivateService) QueueIterContext(ctx context.Context, opts *PinQueueOptions) *PinQueueIterator {
+	return newPinQueueIterator(ctx, s.QueueContext, opts)
+}
+
+// CancelAllPinRequests cancels every queued pin by hash request, or only those
+// with the given status when it is not empty. The whole queue is read before any
+// request is cancelled, then the cancellations run concurrently and every one is
+// attempted. It returns how many were cancelled and how many failed; the error is
+// non-nil if any failed.
+func (s *PrivateService) CancelAllPinRequests(status string) (cancelled int, failed int, err error) {
+	return s.CancelAllPinRequestsContext(context.Background(), status)
+}
+
+// CancelAllPinRequestsContext is like CancelAllPinRequests but carries ctx through to the underlying requests
+func (s *PrivateService) CancelAllPinRequestsContext(ctx context.Context, status string) (cancelled int, failed int, err error) {
+	items, err := s.QueueAllContext(ctx, &PinQueueOptions{Status: status})
 	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
+		return 0, 0, fmt.Errorf("failed to list pin requests: %w", err)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+	ids := make([]string, len(items))
+	for i, item := range items {
+		ids[i] = item.ID
+	}
+
+	errs := make([]error, len(ids))
+	attempted := make([]bool, len(ids))
+	batch.Run(ctx, len(ids), batch.DefaultConcurrency, func(ctx context.Context, i int) {
+		attempted[i] = true
+		errs[i] = s.CancelPinRequestContext(ctx, ids[i])
+	})
+
+	for i := range ids {
+		if attempted[i] && errs[i] == nil {
+			cancelled++
+		} else {
+			failed++
+		}
 	}
 
-	var response struct {
-		Data []types.SwapResponse `json:"data"`
+	if err := ctx.Err(); err != nil {
+		return cancelled, failed, err
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if failed > 0 {
+		return cancelled, failed, fmt.Errorf("%d of %d cancellations failed", failed, len(ids))
 	}
 
-	return response.Data, nil
+	return cancelled, failed, nil
 }
 
-// DeleteSwap removes a CID swap
-func (s *PrivateService) DeleteSwap(cid string) error {
-	if cid == "" {
-		return fmt.Errorf("CID is required")
+// CancelPinRequest cancels a pin by hash request
+func (s *PrivateService) CancelPinRequest(id string) error {
+	return s.CancelPinRequestContext(context.Background(), id)
+}
+
+// CancelPinRequestContext is like CancelPinRequest but carries ctx through to the underlying requests
+func (s *PrivateService) CancelPinRequestContext(ctx context.Context, id string) error {
+	if id == "" {
+		return fmt.Errorf("request ID is required")
 	}
 
 	cfg := s.config.(*types.Config)
-	url := fmt.Sprintf("%s/files/private/swap/%s", cfg.APIUrl, cid)
+	url := fmt.Sprintf("%s/files/private/pin_by_cid/%s", cfg.APIUrl, id)
 
-	req, err := http.NewRequest("DELETE", url, nil)
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+	return request.DoRequest(ctx, cfg, "DELETE", url, nil, nil)
+}
+
+// CreateAccessLinkAt generates an access link for a private IPFS file that becomes
+// valid at validFrom (now when zero) and lasts for validFor, rounded up to whole
+// seconds. It fails if the link would already have expired.
+func (s *PrivateService) CreateAccessLinkAt(cid string, validFrom time.Time, validFor time.Duration) (string, error) {
+	return s.CreateAccessLinkAtContext(context.Background(), cid, validFrom, validFor)
+}
+
+// CreateAccessLinkAtContext is like CreateAccessLinkAt but carries ctx through to the underlying requests
+func (s *PrivateService) CreateAccessLinkAtContext(ctx context.Context, cid string, validFrom time.Time, validFor time.Duration) (string, error) {
+	if validFor <= 0 {
+		return "", fmt.Errorf("validity duration must be positive")
+	}
+
+	if validFrom.IsZero() {
+		validFrom = time.Now()
+	}
+	if !validFrom.Add(validFor).After(time.Now()) {
+		return "", fmt.Errorf("access link would expire at %s, which is in the past", validFrom.Add(validFor).Format(time.RFC3339))
 	}
 
-	req.Header.Set("Authorization", "Bearer "+cfg.PinataJWT)
-	req.Header.Set("Content-Type", "application/json")
+	return s.CreateAccessLinkContext(ctx, &types.AccessLinkOptions{
+		CID:     cid,
+		Date:    validFrom.Unix(),
+		Expires: durationSeconds(validFor),
+	})
+}
 
-	// Add custom headers if any
-	for key, value := range cfg.CustomHeaders {
-		req.Header.Set(key, value)
+// durationSeconds converts d to whole seconds, rounding up so a link is never
+// shorter lived than asked for
+func durationSeconds(d time.Duration) int {
+	seconds := d / time.Second
+	if d%time.Second != 0 {
+		seconds++
 	}
+	return int(seconds)
+}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+// GetWithLink retrieves the file with the given CID together with an access link
+// to it that is valid for expires seconds
+func (s *PrivateService) GetWithLink(cid string, expires int) (*types.File, string, error) {
+	return s.GetWithLinkContext(context.Background(), cid, expires)
+}
+
+// GetWithLinkContext is like GetWithLink but carries ctx through to the underlying requests
+func (s *PrivateService) GetWithLinkContext(ctx context.Context, cid string, expires int) (*types.File, string, error) {
+	file, err := s.GetByCIDContext(ctx, cid)
 	if err != nil {
-		return fmt.Errorf("failed to send request: %w", err)
+		return nil, "", err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+	link, err := s.CreateAccessLinkContext(ctx, &types.AccessLinkOptions{
+		CID:     cid,
+		Expires: expires,
+	})
+	if err != nil {
+		return file, "", err
 	}
 
-	return nil
+	return file, link, nil
 }
 
 // CreateAccessLink generates a temporary access link for a private IPFS file
 func (s *PrivateService) CreateAccessLink(opts *types.AccessLinkOptions) (string, error) {
-	if opts == nil || opts.CID == "" || opts.Expires <= 0 {
-		return "", fmt.Errorf("CID and expiration time are required")
+	return s.CreateAccessLinkContext(context.Background(), opts)
+}
+
+// CreateAccessLinkContext is like CreateAccessLink but carries ctx through to the underlying requests
+func (s *PrivateService) CreateAccessLinkContext(ctx context.Context, opts *types.AccessLinkOptions) (string, error) {
+	if opts == nil || opts.CID == "" {
+		return "", ErrNoCID
+	}
+	if opts.Expires <= 0 {
+		return "", fmt.Errorf("expiration time is required")
 	}
 
 	cfg := s.config.(*types.Config)
@@ -408,6 +940,9 @@ func (s *PrivateService) CreateAccessLink(opts *types.AccessLinkOptions) (string
 	if gateway == "" {
 		gateway = cfg.PinataGateway
 	}
+	if gateway == "" {
+		return "", types.ErrMissingGateway
+	}
 
 	// Set current time if not provided
 	date := opts.Date
@@ -421,192 +956,286 @@ func (s *PrivateService) CreateAccessLink(opts *types.AccessLinkOptions) (string
 		Expires int    `json:"expires"`
 		Method  string `json:"method"`
 	}{
-		URL:     fmt.Sprintf("https://%s.mypinata.cloud/files/%s", gateway, opts.CID),
+		URL:     fmt.Sprintf("https://%s/files/%s", request.GatewayHost(gateway), opts.CID),
 		Date:    date,
 		Expires: opts.Expires,
 		Method:  "GET",
 	}
 
-	jsonData, err := json.Marshal(payload)
+	var response string
+	if err := request.DoRequest(ctx, cfg, "POST", url, payload, &response); err != nil {
+		return "", err
+	}
+
+	// Clean up the URL (remove escaping)
+	accessLink := strings.ReplaceAll(response, "\\u0026", "&")
+	accessLink = strings.Trim(accessLink, "\"")
+
+	// Dedicated gateways with access controls need the gateway key on the link
+	accessLink = request.AppendGatewayToken(accessLink, cfg.PinataGatewayKey)
+
+	return accessLink, nil
+}
+
+// Download fetches the content of a private file through a temporary access link
+// valid for expires seconds, returning the bytes and their content type
+func (s *PrivateService) Download(cid string, expires int) ([]byte, string, error) {
+	return s.DownloadContext(context.Background(), cid, expires)
+}
+
+// DownloadContext is like Download but carries ctx through to the underlying requests
+func (s *PrivateService) DownloadContext(ctx context.Context, cid string, expires int) ([]byte, string, error) {
+	body, contentType, err := s.DownloadStreamContext(ctx, cid, expires)
 	if err != nil {
-		return "", fmt.Errorf("failed to marshal request body: %w", err)
+		return nil, "", err
 	}
+	defer body.Close()
 
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+	data, err := io.ReadAll(body)
 	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
+		return nil, "", fmt.Errorf("failed to read response body: %w", err)
 	}
 
-	req.Header.Set("Authorization", "Bearer "+cfg.PinataJWT)
-	req.Header.Set("Content-Type", "application/json")
+	return data, contentType, nil
+}
 
-	// Add custom headers if any
-	for key, value := range cfg.CustomHeaders {
-		req.Header.Set(key, value)
-	}
+// DownloadStream is like Download but returns the content as a stream for large
+// files. The caller must close the returned reader.
+func (s *PrivateService) DownloadStream(cid string, expires int) (io.ReadCloser, string, error) {
+	return s.DownloadStreamContext(context.Background(), cid, expires)
+}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+// DownloadStreamContext is like DownloadStream but carries ctx through to the underlying requests
+func (s *PrivateService) DownloadStreamContext(ctx context.Context, cid string, expires int) (io.ReadCloser, string, error) {
+	link, err := s.CreateAccessLinkContext(ctx, &types.AccessLinkOptions{
+		CID:     cid,
+		Expires: expires,
+	})
 	if err != nil {
-		return "", fmt.Errorf("failed to send request: %w", err)
+		return nil, "", fmt.Errorf("failed to create access link: %w", err)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+	cfg := s.config.(*types.Config)
+
+	// The signed link authorizes the download, so the JWT is not sent to the gateway
+	req, err := http.NewRequestWithContext(ctx, "GET", link, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create request: %w", err)
 	}
 
-	var response struct {
-		Data string `json:"data"`
+	// Add custom headers if any
+	for key, value := range cfg.CustomHeaders {
+		req.Header.Set(key, value)
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return "", fmt.Errorf("failed to decode response: %w", err)
+	resp, err := request.Do(cfg, req)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to send request: %w", err)
 	}
 
-	// Clean up the URL (remove escaping)
-	accessLink := strings.ReplaceAll(response.Data, "\\u0026", "&")
-	accessLink = strings.Trim(accessLink, "\"")
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, "", request.Error(resp)
+	}
 
-	return accessLink, nil
+	return resp.Body, resp.Header.Get("Content-Type"), nil
 }
 
 // Vectorize adds vectors to a file for text search
 func (s *PrivateService) Vectorize(fileID string) (*types.VectorizeResponse, error) {
+	return s.VectorizeContext(context.Background(), fileID)
+}
+
+// VectorizeContext is like Vectorize but carries ctx through to the underlying requests
+func (s *PrivateService) VectorizeContext(ctx context.Context, fileID string) (*types.VectorizeResponse, error) {
 	if fileID == "" {
-		return nil, fmt.Errorf("file ID is required")
+		return nil, ErrNoFileID
 	}
 
+	// The Vectorized flag changes, so a cached Get result would be stale
+	defer s.InvalidateCache(fileID)
+
 	cfg := s.config.(*types.Config)
 	url := fmt.Sprintf("%s/vectorize/files/%s", cfg.APIUrl, fileID)
 
-	req, err := http.NewRequest("POST", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+	var response *types.VectorizeResponse
+	if err := request.DoRequest(ctx, cfg, "POST", url, nil, &response); err != nil {
+		return nil, err
 	}
 
-	req.Header.Set("Authorization", "Bearer "+cfg.PinataJWT)
-	req.Header.Set("Content-Type", "application/json")
+	return response, nil
+}
 
-	// Add custom headers if any
-	for key, value := range cfg.CustomHeaders {
-		req.Header.Set(key, value)
+// FileAndVectorize uploads a file to the private IPFS network and makes sure it
+// is vectorized. The upload asks for inline vectorizing; if the response shows
+// the file was not vectorized, including when an existing unvectorized file was
+// returned for duplicate content, Vectorize is called as well. On success the
+// response has Vectorized set. If vectorizing fails the upload response is
+// still returned with the error.
+func (s *PrivateService) FileAndVectorize(file *os.File, opts *upload.FileOptions) (*types.UploadResponse, error) {
+	return s.FileAndVectorizeContext(context.Background(), file, opts)
+}
+
+// FileAndVectorizeContext is like FileAndVectorize but carries ctx through to the underlying requests
+func (s *PrivateService) FileAndVectorizeContext(ctx context.Context, file *os.File, opts *upload.FileOptions) (*types.UploadResponse, error) {
+	vectorizeOpts := upload.FileOptions{}
+	if opts != nil {
+		vectorizeOpts = *opts
 	}
+	vectorizeOpts.Vectorize = true
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := upload.NewPrivateService(s.config).FileContext(ctx, file, &vectorizeOpts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
+		return resp, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+	if resp.Vectorized {
+		return resp, nil
 	}
 
-	var response struct {
-		Data *types.VectorizeResponse `json:"data"`
+	response, err := s.VectorizeContext(ctx, resp.ID)
+	if err != nil {
+		return resp, fmt.Errorf("failed to vectorize file %s: %w", resp.ID, err)
 	}
-
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if response == nil || !response.Status {
+		return resp, fmt.Errorf("failed to vectorize file %s: request was not accepted", resp.ID)
 	}
 
-	return response.Data, nil
+	resp.Vectorized = true
+	return resp, nil
 }
 
-// DeleteVectors removes vectors from a file
-func (s *PrivateService) DeleteVectors(fileID string) (*types.VectorizeResponse, error) {
+// VectorizeStatus reports whether a file's vectors have been created, as recorded
+// in its Vectorized flag
+func (s *PrivateService) VectorizeStatus(fileID string) (bool, error) {
+	return s.VectorizeStatusContext(context.Background(), fileID)
+}
+
+// VectorizeStatusContext is like VectorizeStatus but carries ctx through to the underlying requests
+func (s *PrivateService) VectorizeStatusContext(ctx context.Context, fileID string) (bool, error) {
 	if fileID == "" {
-		return nil, fmt.Errorf("file ID is required")
+		return false, ErrNoFileID
 	}
 
-	cfg := s.config.(*types.Config)
-	url := fmt.Sprintf("%s/vectorize/files/%s", cfg.APIUrl, fileID)
-
-	req, err := http.NewRequest("DELETE", url, nil)
+	// Read past the cache, which may predate the file being vectorized
+	file, err := s.fetch(ctx, fileID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return false, err
 	}
 
-	req.Header.Set("Authorization", "Bearer "+cfg.PinataJWT)
-	req.Header.Set("Content-Type", "application/json")
+	return file != nil && file.Vectorized, nil
+}
 
-	// Add custom headers if any
-	for key, value := range cfg.CustomHeaders {
-		req.Header.Set(key, value)
-	}
+// VectorizeBatch vectorizes multiple files. The calls run concurrently and every
+// ID is attempted: the returned slice holds one entry per ID, in order, with
+// Status "vectorized" or "failed", and the error is non-nil if any call failed.
+func (s *PrivateService) VectorizeBatch(fileIDs []string) ([]types.VectorizeBatchResponse, error) {
+	return s.VectorizeBatchContext(context.Background(), fileIDs)
+}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
+// VectorizeBatchContext is like VectorizeBatch but carries ctx through to the underlying requests
+func (s *PrivateService) VectorizeBatchContext(ctx context.Context, fileIDs []string) ([]types.VectorizeBatchResponse, error) {
+	if len(fileIDs) == 0 {
+		return nil, fmt.Errorf("%w: pass at least one", ErrNoFileID)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+	responses := make([]types.VectorizeBatchResponse, len(fileIDs))
+	errs, err := batch.RunAll(ctx, len(fileIDs), batch.DefaultConcurrency, "vectorize calls", func(ctx context.Context, i int) error {
+		_, err := s.VectorizeContext(ctx, fileIDs[i])
+		return err
+	})
+
+	for i, id := range fileIDs {
+		responses[i].ID = id
+		responses[i].Status, responses[i].Error = batch.Outcome(errs[i], "vectorized")
 	}
 
-	var response struct {
-		Data *types.VectorizeResponse `json:"data"`
+	return responses, err
+}
+
+// DeleteVectors removes vectors from a file
+func (s *PrivateService) DeleteVectors(fileID string) (*types.VectorizeResponse, error) {
+	return s.DeleteVectorsContext(context.Background(), fileID)
+}
+
+// DeleteVectorsContext is like DeleteVectors but carries ctx through to the underlying requests
+func (s *PrivateService) DeleteVectorsContext(ctx context.Context, fileID string) (*types.VectorizeResponse, error) {
+	if fileID == "" {
+		return nil, ErrNoFileID
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	// The Vectorized flag changes, so a cached Get result would be stale
+	defer s.InvalidateCache(fileID)
+
+	cfg := s.config.(*types.Config)
+	url := fmt.Sprintf("%s/vectorize/files/%s", cfg.APIUrl, fileID)
+
+	var response *types.VectorizeResponse
+	if err := request.DoRequest(ctx, cfg, "DELETE", url, nil, &response); err != nil {
+		return nil, err
 	}
 
-	return response.Data, nil
+	return response, nil
 }
 
 // QueryVectors searches for files using vector similarity
 func (s *PrivateService) QueryVectors(opts *types.VectorQueryOptions) (*types.VectorQueryResponse, error) {
+	return s.QueryVectorsContext(context.Background(), opts)
+}
+
+// QueryVectorsContext is like QueryVectors but carries ctx through to the underlying requests
+func (s *PrivateService) QueryVectorsContext(ctx context.Context, opts *types.VectorQueryOptions) (*types.VectorQueryResponse, error) {
 	if opts == nil || opts.GroupID == "" || opts.Query == "" {
 		return nil, fmt.Errorf("group ID and query text are required")
 	}
 
-	cfg := s.config.(*types.Config)
-	url := fmt.Sprintf("%s/vectorize/groups/%s/query", cfg.APIUrl, opts.GroupID)
+	return s.query(ctx, opts, "")
+}
 
-	payload := struct {
-		Text string `json:"text"`
-	}{
-		Text: opts.Query,
-	}
+// QueryByFile searches a group for files similar to an existing file instead of
+// to query text. The source file must be vectorized, otherwise ErrNotVectorized
+// is returned. opts.Query is ignored; the other options apply as in
+// QueryVectors, and opts may be nil.
+func (s *PrivateService) QueryByFile(groupID string, fileID string, opts *types.VectorQueryOptions) (*types.VectorQueryResponse, error) {
+	return s.QueryByFileContext(context.Background(), groupID, fileID, opts)
+}
 
-	jsonData, err := json.Marshal(payload)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request body: %w", err)
+// QueryByFileContext is like QueryByFile but carries ctx through to the underlying requests
+func (s *PrivateService) QueryByFileContext(ctx context.Context, groupID string, fileID string, opts *types.VectorQueryOptions) (*types.VectorQueryResponse, error) {
+	if groupID == "" {
+		return nil, fmt.Errorf("group ID is required")
+	}
+	if fileID == "" {
+		return nil, ErrNoFileID
 	}
 
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+	file, err := s.fetch(ctx, fileID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, fmt.Errorf("failed to get source file: %w", err)
+	}
+	if file == nil || !file.Vectorized {
+		return nil, fmt.Errorf("%w: %s", ErrNotVectorized, fileID)
 	}
 
-	req.Header.Set("Authorization", "Bearer "+cfg.PinataJWT)
-	req.Header.Set("Content-Type", "application/json")
-
-	// Add custom headers if any
-	for key, value := range cfg.CustomHeaders {
-		req.Header.Set(key, value)
+	queryOpts := types.VectorQueryOptions{}
+	if opts != nil {
+		queryOpts = *opts
 	}
+	queryOpts.GroupID = groupID
+	queryOpts.Query = ""
+
+	return s.query(ctx, &queryOpts, fileID)
+}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+// query runs a vector query by text, or by the source file when fileID is set,
+// and decodes the matches or the matched file as opts asks
+func (s *PrivateService) query(ctx context.Context, opts *types.VectorQueryOptions, fileID string) (*types.VectorQueryResponse, error) {
+	resp, err := s.queryVectors(ctx, opts, fileID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
+		return nil, err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
-	}
-
 	// Handle the response based on returnFile option
 	if opts.ReturnFile {
 		// Handle file response
@@ -624,13 +1253,144 @@ func (s *PrivateService) QueryVectors(opts *types.VectorQueryOptions) (*types.Ve
 	}
 
 	// Standard query response
-	var response struct {
-		Data *types.VectorQueryResponse `json:"data"`
+	var response *types.VectorQueryResponse
+	if err := request.Decode(resp, &response); err != nil {
+		return nil, err
+	}
+
+	if response != nil {
+		matches := filterMatches(response.Matches, opts)
+		if len(matches) != len(response.Matches) {
+			response.Count = len(matches)
+		}
+		response.Matches = matches
+
+		if opts.IncludeMetadata {
+			if err := s.attachMatchFiles(ctx, matches); err != nil {
+				return response, err
+			}
+		}
+	}
+
+	return response, nil
+}
+
+// attachMatchFiles fetches the file behind each match concurrently and sets
+// its File field. Every match is attempted; the error reports any that failed.
+func (s *PrivateService) attachMatchFiles(ctx context.Context, matches []types.VectorMatch) error {
+	errs := make([]error, len(matches))
+
+	batch.Run(ctx, len(matches), batch.DefaultConcurrency, func(ctx context.Context, i int) {
+		file, err := s.GetContext(ctx, matches[i].FileID)
+		if err != nil {
+			errs[i] = err
+			return
+		}
+		matches[i].File = file
+	})
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	failed := 0
+	var first error
+	for _, err := range errs {
+		if err != nil {
+			if first == nil {
+				first = err
+			}
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to fetch metadata for %d of %d matches: %w", failed, len(matches), first)
+	}
+
+	return nil
+}
+
+// QueryVectorsStream runs a vector query for the matched file, like QueryVectors
+// with ReturnFile set, but returns the content as a stream with its content type
+// instead of reading it into memory. The caller must close the returned reader.
+func (s *PrivateService) QueryVectorsStream(opts *types.VectorQueryOptions) (io.ReadCloser, string, error) {
+	return s.QueryVectorsStreamContext(context.Background(), opts)
+}
+
+// QueryVectorsStreamContext is like QueryVectorsStream but carries ctx through to the underlying requests
+func (s *PrivateService) QueryVectorsStreamContext(ctx context.Context, opts *types.VectorQueryOptions) (io.ReadCloser, string, error) {
+	if opts != nil && !opts.ReturnFile {
+		fileOpts := *opts
+		fileOpts.ReturnFile = true
+		opts = &fileOpts
+	}
+
+	if opts == nil || opts.GroupID == "" || opts.Query == "" {
+		return nil, "", fmt.Errorf("group ID and query text are required")
+	}
+
+	resp, err := s.queryVectors(ctx, opts, "")
+	if err != nil {
+		return nil, "", err
+	}
+
+	return resp.Body, resp.Header.Get("Content-Type"), nil
+}
+
+// queryVectors sends a vector query by opts.Query, or by the source file when
+// fileID is set, and returns the successful response, whose body the caller
+// must close
+func (s *PrivateService) queryVectors(ctx context.Context, opts *types.VectorQueryOptions, fileID string) (*http.Response, error) {
+	cfg := s.config.(*types.Config)
+	url := fmt.Sprintf("%s/vectorize/groups/%s/query", cfg.APIUrl, opts.GroupID)
+
+	payload := struct {
+		Text       string `json:"text,omitempty"`
+		FileID     string `json:"file_id,omitempty"`
+		Limit      int    `json:"limit,omitempty"`
+		ReturnFile bool   `json:"return_file,omitempty"`
+	}{
+		Text:       opts.Query,
+		FileID:     fileID,
+		Limit:      opts.Limit,
+		ReturnFile: opts.ReturnFile,
+	}
+
+	req, err := request.New(ctx, cfg, "POST", url, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := request.Do(cfg, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, request.Error(resp)
+	}
+
+	return resp, nil
+}
+
+// filterMatches drops matches below opts.MinScore and enforces opts.Limit, in
+// case the API returns more than was asked for
+func filterMatches(matches []types.VectorMatch, opts *types.VectorQueryOptions) []types.VectorMatch {
+	if opts.MinScore > 0 {
+		kept := matches[:0]
+		for _, match := range matches {
+			if match.Score >= opts.MinScore {
+				kept = append(kept, match)
+			}
+		}
+		matches = kept
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if opts.Limit > 0 && len(matches) > opts.Limit {
+		matches = matches[:opts.Limit]
 	}
 
-	return response.Data, nil
+	return matches
 }
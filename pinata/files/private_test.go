@@ -0,0 +1,157 @@
+package files
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/PinataCloud/pinata-go-sdk/pinata/types"
+)
+
+func TestCreateAccessLinkGatewayHost(t *testing.T) {
+	tests := []struct {
+		gateway string
+		want    string
+	}{
+		{"example", "https://example.mypinata.cloud/files/bafy"},
+		{"example.mypinata.cloud", "https://example.mypinata.cloud/files/bafy"},
+		{"https://example.mypinata.cloud/", "https://example.mypinata.cloud/files/bafy"},
+		{"files.example.com", "https://files.example.com/files/bafy"},
+	}
+
+	for _, tt := range tests {
+		var signed string
+		cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+			var payload struct {
+				URL string `json:"url"`
+			}
+			json.NewDecoder(r.Body).Decode(&payload)
+			signed = payload.URL
+			fmt.Fprintf(w, `{"data":%q}`, payload.URL+"?X-Signature=sig")
+		})
+		cfg.PinataGateway = tt.gateway
+
+		link, err := NewPrivateService(cfg).CreateAccessLink(&types.AccessLinkOptions{CID: "bafy", Expires: 30})
+		if err != nil {
+			t.Fatalf("%s: %v", tt.gateway, err)
+		}
+		if signed != tt.want || link != tt.want+"?X-Signature=sig" {
+			t.Errorf("%s: signed %s and got %s, want %s", tt.gateway, signed, link, tt.want)
+		}
+	}
+}
+
+func TestCreateAccessLinkGatewayOverride(t *testing.T) {
+	var signed string
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		var payload struct {
+			URL string `json:"url"`
+		}
+		json.NewDecoder(r.Body).Decode(&payload)
+		signed = payload.URL
+		fmt.Fprintf(w, `{"data":%q}`, payload.URL)
+	})
+	cfg.PinataGateway = "configured"
+
+	if _, err := NewPrivateService(cfg).CreateAccessLink(&types.AccessLinkOptions{CID: "bafy", Expires: 30, Gateway: "cdn.example.com"}); err != nil {
+		t.Fatal(err)
+	}
+	if signed != "https://cdn.example.com/files/bafy" {
+		t.Fatalf("signed %s", signed)
+	}
+}
+
+func TestGetWithLink(t *testing.T) {
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/files/private":
+			if r.URL.Query().Get("cid") != "bafy" {
+				fmt.Fprint(w, `{"data":{"files":[]}}`)
+				return
+			}
+			fmt.Fprint(w, `{"data":{"files":[{"id":"file-1","name":"a.txt","cid":"bafy"}]}}`)
+		case "/files/private/download_link":
+			var payload struct {
+				URL     string `json:"url"`
+				Expires int    `json:"expires"`
+			}
+			json.NewDecoder(r.Body).Decode(&payload)
+			fmt.Fprintf(w, `{"data":%q}`, fmt.Sprintf("%s?expires=%d", payload.URL, payload.Expires))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	cfg.PinataGateway = "example.mypinata.cloud"
+	service := NewPrivateService(cfg)
+
+	file, link, err := service.GetWithLink("bafy", 60)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if file.ID != "file-1" || link != "https://example.mypinata.cloud/files/bafy?expires=60" {
+		t.Fatalf("got %+v and %s", file, link)
+	}
+
+	if _, _, err := service.GetWithLink("missing", 60); !errors.Is(err, ErrFileNotFound) {
+		t.Fatalf("missing CID: got %v, want ErrFileNotFound", err)
+	}
+}
+
+func TestCreateAccessLinkAt(t *testing.T) {
+	var date int64
+	var expires, requests int
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		var payload struct {
+			URL     string `json:"url"`
+			Date    int64  `json:"date"`
+			Expires int    `json:"expires"`
+		}
+		json.NewDecoder(r.Body).Decode(&payload)
+		date, expires = payload.Date, payload.Expires
+		fmt.Fprintf(w, `{"data":%q}`, payload.URL)
+	})
+	cfg.PinataGateway = "example.mypinata.cloud"
+	service := NewPrivateService(cfg)
+
+	from := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		validFor time.Duration
+		want     int
+	}{
+		{time.Hour, 3600},
+		{90 * time.Second, 90},
+		{1500 * time.Millisecond, 2},
+	}
+	for _, tt := range tests {
+		if _, err := service.CreateAccessLinkAt("bafy", from, tt.validFor); err != nil {
+			t.Fatal(err)
+		}
+		if date != from.Unix() || expires != tt.want {
+			t.Errorf("%s: sent date %d and expires %d, want %d and %d", tt.validFor, date, expires, from.Unix(), tt.want)
+		}
+	}
+
+	// A link that started in the past is fine while it is still valid
+	past := time.Now().Add(-time.Minute)
+	if _, err := service.CreateAccessLinkAt("bafy", past, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if date != past.Unix() || expires != 3600 {
+		t.Errorf("past start: sent date %d and expires %d", date, expires)
+	}
+
+	requests = 0
+	if _, err := service.CreateAccessLinkAt("bafy", past, 30*time.Second); err == nil {
+		t.Error("expired link: got no error")
+	}
+	if _, err := service.CreateAccessLinkAt("bafy", from, 0); err == nil {
+		t.Error("zero duration: got no error")
+	}
+	if requests != 0 {
+		t.Fatalf("sent %d requests for invalid links", requests)
+	}
+}
@@ -1,72 +1,190 @@
 package files
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
-	"io"
-	"net/http"
 	"net/url"
+	"os"
 	"strconv"
+	"time"
 
+	"github.com/PinataCloud/pinata-go-sdk/pinata/gateway"
+	"github.com/PinataCloud/pinata-go-sdk/pinata/groups"
+	"github.com/PinataCloud/pinata-go-sdk/pinata/internal/batch"
+	"github.com/PinataCloud/pinata-go-sdk/pinata/internal/request"
 	types "github.com/PinataCloud/pinata-go-sdk/pinata/types"
+	"github.com/PinataCloud/pinata-go-sdk/pinata/upload"
 )
 
 // PublicService provides operations for managing files on the public IPFS network
 type PublicService struct {
 	config interface{}
+	cache  *fileCache
 }
 
 // NewPublicService creates a new PublicService with the provided configuration
 func NewPublicService(config interface{}) *PublicService {
 	return &PublicService{
 		config: config,
+		cache:  newFileCache(config),
 	}
 }
 
 // Get retrieves a file by ID from the public IPFS network
 func (s *PublicService) Get(id string) (*types.File, error) {
+	return s.GetContext(context.Background(), id)
+}
+
+// GetContext is like Get but carries ctx through to the underlying requests
+func (s *PublicService) GetContext(ctx context.Context, id string) (*types.File, error) {
+	if id == "" {
+		return nil, ErrNoFileID
+	}
+
+	key := cacheKey("public", id)
+	if file, ok := s.cache.get(key); ok {
+		return file, nil
+	}
+
+	file, err := s.fetch(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	s.cache.put(key, file)
+	return file, nil
+}
+
+// GetMany retrieves several files by ID concurrently, e.g. to hydrate a list of
+// IDs. Duplicate IDs are fetched once. Every ID ends up in exactly one of the
+// returned maps: files holds the ones that were found and errs the error for
+// each one that was not, such as an API error with a 404 status.
+func (s *PublicService) GetMany(ids []string) (files map[string]*types.File, errs map[string]error) {
+	return s.GetManyContext(context.Background(), ids)
+}
+
+// GetManyContext is like GetMany but carries ctx through to the underlying requests
+func (s *PublicService) GetManyContext(ctx context.Context, ids []string) (files map[string]*types.File, errs map[string]error) {
+	files = make(map[string]*types.File, len(ids))
+	errs = make(map[string]error)
+
+	unique := make([]string, 0, len(ids))
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		if id == "" {
+			errs[id] = ErrNoFileID
+			continue
+		}
+		unique = append(unique, id)
+	}
+
+	results := make([]*types.File, len(unique))
+	failures, _ := batch.RunAll(ctx, len(unique), batch.DefaultConcurrency, "gets", func(ctx context.Context, i int) error {
+		var err error
+		results[i], err = s.GetContext(ctx, unique[i])
+		return err
+	})
+
+	for i, id := range unique {
+		switch {
+		case errors.Is(failures[i], batch.ErrNotAttempted):
+			// Only possible once ctx is done
+			errs[id] = fmt.Errorf("%w: %w", batch.ErrNotAttempted, ctx.Err())
+		case failures[i] != nil:
+			errs[id] = failures[i]
+		default:
+			files[id] = results[i]
+		}
+	}
+
+	return files, errs
+}
+
+// InvalidateCache drops the cached Get result for id. Update and Delete do this
+// automatically; it is only needed when the file is changed by other means.
+func (s *PublicService) InvalidateCache(id string) {
+	s.cache.remove(cacheKey("public", id))
+}
+
+// fetch retrieves a file by ID, bypassing the cache
+func (s *PublicService) fetch(ctx context.Context, id string) (*types.File, error) {
 	cfg := s.config.(*types.Config)
 	url := fmt.Sprintf("%s/files/public/%s", cfg.APIUrl, id)
 
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+	var response *types.File
+	if err := request.DoRequest(ctx, cfg, "GET", url, nil, &response); err != nil {
+		return nil, err
 	}
 
-	req.Header.Set("Authorization", "Bearer "+cfg.PinataJWT)
-	req.Header.Set("Content-Type", "application/json")
+	return response, nil
+}
+
+// GetByCID retrieves the file with the given CID from the public IPFS network. It returns
+// ErrFileNotFound if no file matches and ErrMultipleFiles if more than one does.
+func (s *PublicService) GetByCID(cid string) (*types.File, error) {
+	return s.GetByCIDContext(context.Background(), cid)
+}
 
-	// Add custom headers if any
-	for key, value := range cfg.CustomHeaders {
-		req.Header.Set(key, value)
+// GetByCIDContext is like GetByCID but carries ctx through to the underlying requests
+func (s *PublicService) GetByCIDContext(ctx context.Context, cid string) (*types.File, error) {
+	if cid == "" {
+		return nil, ErrNoCID
 	}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	// Two results are enough to tell a unique match from an ambiguous one
+	list, err := s.ListContext(ctx, &ListOptions{CID: cid, Limit: 2})
 	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+	var files []types.File
+	if list != nil {
+		files = list.Files
 	}
 
-	var response struct {
-		Data *types.File `json:"data"`
+	switch len(files) {
+	case 0:
+		return nil, fmt.Errorf("%w: %s", ErrFileNotFound, cid)
+	case 1:
+		return &files[0], nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrMultipleFiles, cid)
 	}
+}
+
+// Exists reports whether at least one file on the public IPFS network has the given CID,
+// which lets callers skip uploading content that is already pinned
+func (s *PublicService) Exists(cid string) (bool, error) {
+	return s.ExistsContext(context.Background(), cid)
+}
 
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+// ExistsContext is like Exists but carries ctx through to the underlying requests
+func (s *PublicService) ExistsContext(ctx context.Context, cid string) (bool, error) {
+	if cid == "" {
+		return false, ErrNoCID
 	}
 
-	return response.Data, nil
+	list, err := s.ListContext(ctx, &ListOptions{CID: cid, Limit: 1})
+	if err != nil {
+		return false, err
+	}
+
+	return list != nil && len(list.Files) > 0, nil
 }
 
 // List retrieves a list of files from the public IPFS network
 func (s *PublicService) List(opts *ListOptions) (*types.FileListResponse, error) {
+	return s.ListContext(context.Background(), opts)
+}
+
+// ListContext is like List but carries ctx through to the underlying requests
+func (s *PublicService) ListContext(ctx context.Context, opts *ListOptions) (*types.FileListResponse, error) {
 	cfg := s.config.(*types.Config)
 	baseURL := fmt.Sprintf("%s/files/public", cfg.APIUrl)
 
@@ -74,6 +192,16 @@ func (s *PublicService) List(opts *ListOptions) (*types.FileListResponse, error)
 	params := url.Values{}
 
 	if opts != nil {
+		if !opts.CreatedAfter.IsZero() && !opts.CreatedBefore.IsZero() && opts.CreatedAfter.After(opts.CreatedBefore) {
+			return nil, fmt.Errorf("CreatedAfter must not be later than CreatedBefore")
+		}
+		if opts.MinSize > 0 && opts.MaxSize > 0 && opts.MinSize > opts.MaxSize {
+			return nil, fmt.Errorf("MinSize must not be larger than MaxSize")
+		}
+		if err := opts.checkSort(); err != nil {
+			return nil, err
+		}
+
 		if opts.Name != "" {
 			params.Add("name", opts.Name)
 		}
@@ -95,12 +223,21 @@ func (s *PublicService) List(opts *ListOptions) (*types.FileListResponse, error)
 		if opts.Order != "" {
 			params.Add("order", opts.Order)
 		}
+		if opts.SortBy != "" {
+			params.Add("sortBy", opts.SortBy)
+		}
 		if opts.Limit > 0 {
 			params.Add("limit", strconv.Itoa(opts.Limit))
 		}
 		if opts.PageToken != "" {
 			params.Add("pageToken", opts.PageToken)
 		}
+		if !opts.CreatedAfter.IsZero() {
+			params.Add("createdAfter", opts.CreatedAfter.UTC().Format(time.RFC3339))
+		}
+		if !opts.CreatedBefore.IsZero() {
+			params.Add("createdBefore", opts.CreatedBefore.UTC().Format(time.RFC3339))
+		}
 
 		// Add keyvalues if present
 		if len(opts.KeyValues) > 0 {
@@ -108,6 +245,12 @@ func (s *PublicService) List(opts *ListOptions) (*types.FileListResponse, error)
 				params.Add(fmt.Sprintf("keyvalues[%s]", key), value)
 			}
 		}
+		for key, filter := range opts.KeyValueFilters {
+			params.Add(fmt.Sprintf("keyvalues[%s][value]", key), filter.Value)
+			if filter.Op != "" {
+				params.Add(fmt.Sprintf("keyvalues[%s][op]", key), filter.Op)
+			}
+		}
 	}
 
 	// Append query parameters if any
@@ -116,334 +259,477 @@ func (s *PublicService) List(opts *ListOptions) (*types.FileListResponse, error)
 		requestURL = fmt.Sprintf("%s?%s", baseURL, params.Encode())
 	}
 
-	req, err := http.NewRequest("GET", requestURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+	var response *types.FileListResponse
+	if err := request.DoRequest(ctx, cfg, "GET", requestURL, nil, &response); err != nil {
+		return nil, err
 	}
 
-	req.Header.Set("Authorization", "Bearer "+cfg.PinataJWT)
-	req.Header.Set("Content-Type", "application/json")
-
-	// Add custom headers if any
-	for key, value := range cfg.CustomHeaders {
-		req.Header.Set(key, value)
+	// Size is filtered here since the API has no parameter for it, and the
+	// network is filled in since the list endpoint does not always echo it
+	if response != nil {
+		response.Files = opts.filterSize(response.Files)
+		for i := range response.Files {
+			response.Files[i].Network = "public"
+		}
 	}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
-	}
-	defer resp.Body.Close()
+	return response, nil
+}
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
-	}
+// ListAll retrieves every file matching opts from the public IPFS network, following
+// NextPageToken until all pages have been read. opts.Limit sets the page size.
+func (s *PublicService) ListAll(opts *ListOptions) ([]types.File, error) {
+	return s.ListAllContext(context.Background(), opts)
+}
 
-	var response struct {
-		Data *types.FileListResponse `json:"data"`
-	}
+// ListAllContext is like ListAll but carries ctx through to the underlying requests
+func (s *PublicService) ListAllContext(ctx context.Context, opts *ListOptions) ([]types.File, error) {
+	return collectFiles(s.ListIterContext(ctx, opts))
+}
 
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
-	}
+// ListIter returns an iterator over every file matching opts on the public IPFS
+// network. Pages are fetched lazily, so large result sets are never held in memory.
+func (s *PublicService) ListIter(opts *ListOptions) *FileIterator {
+	return s.ListIterContext(context.Background(), opts)
+}
 
-	return response.Data, nil
+// ListIterContext is like ListIter but carries ctx through to the underlying requests
+func (s *PublicService) ListIterContext(ctx context.Context, opts *ListOptions) *FileIterator {
+	return newFileIterator(ctx, s.ListContext, opts)
 }
 
 // Update updates file metadata
 func (s *PublicService) Update(opts *UpdateOptions) (*types.File, error) {
+	return s.UpdateContext(context.Background(), opts)
+}
+
+// UpdateContext is like Update but carries ctx through to the underlying requests
+func (s *PublicService) UpdateContext(ctx context.Context, opts *UpdateOptions) (*types.File, error) {
 	if opts == nil || opts.ID == "" {
-		return nil, fmt.Errorf("file ID is required")
+		return nil, ErrNoFileID
 	}
 
-	cfg := s.config.(*types.Config)
-	url := fmt.Sprintf("%s/files/public/%s", cfg.APIUrl, opts.ID)
+	if opts.MergeKeyValues && len(opts.KeyValues) > 0 {
+		current, err := s.fetch(ctx, opts.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get current keyvalues: %w", err)
+		}
 
-	payload, err := json.Marshal(opts)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request body: %w", err)
+		merged := make(map[string]string, len(current.KeyValues)+len(opts.KeyValues))
+		for key, value := range current.KeyValues {
+			merged[key] = value
+		}
+		for key, value := range opts.KeyValues {
+			merged[key] = value
+		}
+
+		mergedOpts := *opts
+		mergedOpts.KeyValues = merged
+		opts = &mergedOpts
 	}
 
-	req, err := http.NewRequest("PUT", url, bytes.NewBuffer(payload))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+	return s.put(ctx, opts.ID, opts)
+}
+
+// UpdateBatch applies several updates, for example to retag many files at once.
+// The updates run concurrently through Update and every one is attempted: the
+// returned slice holds one entry per update, in order, with Status "updated" or
+// "failed", and the error is non-nil if any update failed.
+func (s *PublicService) UpdateBatch(updates []UpdateOptions) ([]types.UpdateBatchResponse, error) {
+	return s.UpdateBatchContext(context.Background(), updates)
+}
+
+// UpdateBatchContext is like UpdateBatch but carries ctx through to the underlying requests
+func (s *PublicService) UpdateBatchContext(ctx context.Context, updates []UpdateOptions) ([]types.UpdateBatchResponse, error) {
+	if len(updates) == 0 {
+		return nil, fmt.Errorf("%w: pass at least one update", ErrNoFileID)
 	}
 
-	req.Header.Set("Authorization", "Bearer "+cfg.PinataJWT)
-	req.Header.Set("Content-Type", "application/json")
+	responses := make([]types.UpdateBatchResponse, len(updates))
+	errs, err := batch.RunAll(ctx, len(updates), batch.DefaultConcurrency, "updates", func(ctx context.Context, i int) error {
+		file, err := s.UpdateContext(ctx, &updates[i])
+		responses[i].File = file
+		return err
+	})
 
-	// Add custom headers if any
-	for key, value := range cfg.CustomHeaders {
-		req.Header.Set(key, value)
+	for i, update := range updates {
+		responses[i].ID = update.ID
+		responses[i].Status, responses[i].Error = batch.Outcome(errs[i], "updated")
 	}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
+	return responses, err
+}
+
+// RemoveKeyValues deletes the named keys from a file's keyvalues, leaving the
+// rest in place. Keys the file does not have are ignored.
+func (s *PublicService) RemoveKeyValues(id string, keys []string) (*types.File, error) {
+	return s.RemoveKeyValuesContext(context.Background(), id, keys)
+}
+
+// RemoveKeyValuesContext is like RemoveKeyValues but carries ctx through to the underlying requests
+func (s *PublicService) RemoveKeyValuesContext(ctx context.Context, id string, keys []string) (*types.File, error) {
+	if id == "" {
+		return nil, ErrNoFileID
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+	current, err := s.fetch(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current keyvalues: %w", err)
 	}
 
-	var response struct {
-		Data *types.File `json:"data"`
+	remaining := make(map[string]string, len(current.KeyValues))
+	for key, value := range current.KeyValues {
+		remaining[key] = value
+	}
+	for _, key := range keys {
+		delete(remaining, key)
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if len(remaining) == len(current.KeyValues) {
+		return current, nil
 	}
 
-	return response.Data, nil
+	// keyvalues is sent even when empty so removing the last key clears the map
+	payload := struct {
+		KeyValues map[string]string `json:"keyvalues"`
+	}{KeyValues: remaining}
+
+	return s.put(ctx, id, payload)
 }
 
-// Delete removes files by their IDs
-func (s *PublicService) Delete(ids []string) ([]types.DeleteResponse, error) {
-	if len(ids) == 0 {
-		return nil, fmt.Errorf("at least one file ID is required")
-	}
+// put sends body as the new metadata for the file with the given ID
+func (s *PublicService) put(ctx context.Context, id string, body interface{}) (*types.File, error) {
+	defer s.InvalidateCache(id)
 
 	cfg := s.config.(*types.Config)
+	url := fmt.Sprintf("%s/files/public/%s", cfg.APIUrl, id)
 
-	var responses []types.DeleteResponse
+	var response *types.File
+	if err := request.DoRequest(ctx, cfg, "PUT", url, body, &response); err != nil {
+		return nil, err
+	}
 
-	// Process each ID individually
-	for _, id := range ids {
-		url := fmt.Sprintf("%s/files/public/%s", cfg.APIUrl, id)
+	return response, nil
+}
 
-		req, err := http.NewRequest("DELETE", url, nil)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create request: %w", err)
-		}
+// Replace uploads new content for the file with the given ID while keeping its
+// metadata, and swaps the old CID to the new one so existing gateway links serve
+// the new content. The swap is skipped when the content is unchanged. It returns
+// the new upload and the swap record.
+func (s *PublicService) Replace(id string, file *os.File, opts *ReplaceOptions) (*types.UploadResponse, *types.SwapResponse, error) {
+	return s.ReplaceContext(context.Background(), id, file, opts)
+}
 
-		req.Header.Set("Authorization", "Bearer "+cfg.PinataJWT)
-		req.Header.Set("Content-Type", "application/json")
+// ReplaceContext is like Replace but carries ctx through to the underlying requests
+func (s *PublicService) ReplaceContext(ctx context.Context, id string, file *os.File, opts *ReplaceOptions) (*types.UploadResponse, *types.SwapResponse, error) {
+	if id == "" {
+		return nil, nil, ErrNoFileID
+	}
+	if opts == nil {
+		opts = &ReplaceOptions{}
+	}
 
-		// Add custom headers if any
-		for key, value := range cfg.CustomHeaders {
-			req.Header.Set(key, value)
+	old, err := s.fetch(ctx, id)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get file to replace: %w", err)
+	}
+
+	fileOpts := opts.FileOptions
+	if fileOpts.FileName == "" {
+		fileOpts.FileName = old.Name
+	}
+	if fileOpts.GroupID == "" && fileOpts.GroupName == "" && !fileOpts.NoGroup {
+		if old.GroupID != nil {
+			fileOpts.GroupID = *old.GroupID
+		} else {
+			// Keep the replacement out of the default group like the original
+			fileOpts.NoGroup = true
 		}
+	}
+	if fileOpts.KeyValues == nil {
+		fileOpts.KeyValues = old.KeyValues
+	}
 
-		client := &http.Client{}
-		resp, err := client.Do(req)
+	uploaded, err := upload.NewPublicService(s.config).FileContext(ctx, file, &fileOpts)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to upload replacement: %w", err)
+	}
+
+	var swap *types.SwapResponse
+	if uploaded.CID != old.CID {
+		swap, err = s.AddSwapContext(ctx, &SwapOptions{CID: old.CID, SwapCID: uploaded.CID})
 		if err != nil {
-			return nil, fmt.Errorf("failed to send request: %w", err)
+			return uploaded, nil, fmt.Errorf("failed to swap %s to %s: %w", old.CID, uploaded.CID, err)
 		}
+	}
 
-		// Check response status
-		if resp.StatusCode != http.StatusOK {
-			body, _ := io.ReadAll(resp.Body)
-			resp.Body.Close()
-			return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+	if opts.DeleteOld && uploaded.ID != id {
+		if err := s.deleteOne(ctx, id); err != nil {
+			return uploaded, swap, fmt.Errorf("failed to delete replaced file: %w", err)
 		}
-
-		// Add to successful deletions
-		responses = append(responses, types.DeleteResponse{
-			ID:     id,
-			Status: "deleted",
-		})
-
-		resp.Body.Close()
 	}
 
-	return responses, nil
+	return uploaded, swap, nil
 }
 
-// AddSwap creates a CID swap
-func (s *PublicService) AddSwap(opts *SwapOptions) (*types.SwapResponse, error) {
-	if opts == nil || opts.CID == "" || opts.SwapCID == "" {
-		return nil, fmt.Errorf("CID and swap CID are required")
-	}
-
-	cfg := s.config.(*types.Config)
-	url := fmt.Sprintf("%s/files/public/swap/%s", cfg.APIUrl, opts.CID)
+// MoveToPrivate copies a public file to the private network by downloading its content
+// and uploading it again, carrying over its name, keyvalues and group. With
+// opts.DeleteSource the source file is deleted afterwards. It returns the new
+// file's record, which has a new ID. Folders cannot be moved.
+func (s *PublicService) MoveToPrivate(id string, opts *MoveOptions) (*types.UploadResponse, error) {
+	return s.MoveToPrivateContext(context.Background(), id, opts)
+}
 
-	payload := struct {
-		SwapCID string `json:"swap_cid"`
-	}{
-		SwapCID: opts.SwapCID,
+// MoveToPrivateContext is like MoveToPrivate but carries ctx through to the underlying requests
+func (s *PublicService) MoveToPrivateContext(ctx context.Context, id string, opts *MoveOptions) (*types.UploadResponse, error) {
+	if id == "" {
+		return nil, ErrNoFileID
+	}
+	if opts == nil {
+		opts = &MoveOptions{}
 	}
 
-	jsonData, err := json.Marshal(payload)
+	source, err := s.fetch(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request body: %w", err)
+		return nil, fmt.Errorf("failed to get file to move: %w", err)
 	}
 
-	req, err := http.NewRequest("PUT", url, bytes.NewBuffer(jsonData))
+	fileOpts, err := moveFileOptions(ctx, source, opts, groups.NewPublicService(s.config))
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, err
 	}
 
-	req.Header.Set("Authorization", "Bearer "+cfg.PinataJWT)
-	req.Header.Set("Content-Type", "application/json")
-
-	// Add custom headers if any
-	for key, value := range cfg.CustomHeaders {
-		req.Header.Set(key, value)
+	body, contentType, err := gateway.NewPublicService(s.config).GetStreamContext(ctx, source.CID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download file to move: %w", err)
 	}
+	defer body.Close()
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	data := upload.NewCustomFileData(body, fileOpts.FileName, source.Size, contentType)
+	moved, err := upload.NewPrivateService(s.config).FileReaderContext(ctx, data, fileOpts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
+		return nil, fmt.Errorf("failed to upload file to the private network: %w", err)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+	if opts.DeleteSource {
+		if err := s.deleteOne(ctx, id); err != nil {
+			return moved, fmt.Errorf("failed to delete source file: %w", err)
+		}
 	}
 
-	var response struct {
-		Data *types.SwapResponse `json:"data"`
+	return moved, nil
+}
+
+// Delete removes files by their IDs. The deletes run concurrently and every ID is
+// attempted: the returned slice holds one entry per ID, in order, with Status
+// "deleted" or "failed", and the error is non-nil if any delete failed.
+func (s *PublicService) Delete(ids []string) ([]types.DeleteResponse, error) {
+	return s.DeleteContext(context.Background(), ids)
+}
+
+// DeleteContext is like Delete but carries ctx through to the underlying requests
+func (s *PublicService) DeleteContext(ctx context.Context, ids []string) ([]types.DeleteResponse, error) {
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("%w: pass at least one", ErrNoFileID)
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	responses := make([]types.DeleteResponse, len(ids))
+	errs, err := batch.RunAll(ctx, len(ids), batch.DefaultConcurrency, "deletes", func(ctx context.Context, i int) error {
+		return s.deleteOne(ctx, ids[i])
+	})
+
+	for i, id := range ids {
+		responses[i].ID = id
+		responses[i].Status, responses[i].Error = batch.Outcome(errs[i], "deleted")
 	}
 
-	return response.Data, nil
+	return responses, err
 }
 
-// GetSwapHistory retrieves the swap history for a CID
-func (s *PublicService) GetSwapHistory(opts *SwapHistoryOptions) ([]types.SwapResponse, error) {
-	if opts == nil || opts.CID == "" || opts.Domain == "" {
-		return nil, fmt.Errorf("CID and domain are required")
+// DeleteByCID deletes the files with the given CIDs from the public IPFS network,
+// looking up their IDs first. The returned slice is aligned with cids.
+func (s *PublicService) DeleteByCID(cids []string, opts *DeleteByCIDOptions) ([]types.DeleteByCIDResponse, error) {
+	return s.DeleteByCIDContext(context.Background(), cids, opts)
+}
+
+// DeleteByCIDContext is like DeleteByCID but carries ctx through to the underlying requests
+func (s *PublicService) DeleteByCIDContext(ctx context.Context, cids []string, opts *DeleteByCIDOptions) ([]types.DeleteByCIDResponse, error) {
+	if len(cids) == 0 {
+		return nil, fmt.Errorf("%w: pass at least one", ErrNoCID)
 	}
 
-	cfg := s.config.(*types.Config)
-	url := fmt.Sprintf("%s/files/public/swap/%s?domain=%s", cfg.APIUrl, opts.CID, url.QueryEscape(opts.Domain))
+	responses := make([]types.DeleteByCIDResponse, len(cids))
+	errs, err := batch.RunAll(ctx, len(cids), batch.DefaultConcurrency, "CID deletes", func(ctx context.Context, i int) error {
+		ids, err := s.deleteCID(ctx, cids[i], opts)
+		responses[i].IDs = ids
+		return err
+	})
 
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+	for i, cid := range cids {
+		responses[i].CID = cid
+		responses[i].Status, responses[i].Error = batch.Outcome(errs[i], "deleted")
 	}
 
-	req.Header.Set("Authorization", "Bearer "+cfg.PinataJWT)
-	req.Header.Set("Content-Type", "application/json")
+	return responses, err
+}
 
-	// Add custom headers if any
-	for key, value := range cfg.CustomHeaders {
-		req.Header.Set(key, value)
+// deleteCID deletes the files with cid and returns the IDs of those deleted
+func (s *PublicService) deleteCID(ctx context.Context, cid string, opts *DeleteByCIDOptions) ([]string, error) {
+	if cid == "" {
+		return nil, ErrNoCID
 	}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	files, err := s.ListAllContext(ctx, &ListOptions{CID: cid})
 	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
+		return nil, fmt.Errorf("failed to look up files: %w", err)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+	switch {
+	case len(files) == 0:
+		return nil, fmt.Errorf("%w: %s", ErrFileNotFound, cid)
+	case len(files) > 1 && (opts == nil || !opts.AllowMultiple):
+		return nil, fmt.Errorf("%w: %s has %d files", ErrMultipleFiles, cid, len(files))
 	}
 
-	var response struct {
-		Data []types.SwapResponse `json:"data"`
-	}
-
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	deleted := make([]string, 0, len(files))
+	for _, file := range files {
+		if err := s.deleteOne(ctx, file.ID); err != nil {
+			return deleted, fmt.Errorf("failed to delete file %s: %w", file.ID, err)
+		}
+		deleted = append(deleted, file.ID)
 	}
 
-	return response.Data, nil
+	return deleted, nil
 }
 
-// DeleteSwap removes a CID swap
-func (s *PublicService) DeleteSwap(cid string) error {
-	if cid == "" {
-		return fmt.Errorf("CID is required")
-	}
+// deleteOne removes a single file by ID
+func (s *PublicService) deleteOne(ctx context.Context, id string) error {
+	defer s.InvalidateCache(id)
 
 	cfg := s.config.(*types.Config)
-	url := fmt.Sprintf("%s/files/public/swap/%s", cfg.APIUrl, cid)
+	url := fmt.Sprintf("%s/files/public/%s", cfg.APIUrl, id)
 
-	req, err := http.NewRequest("DELETE", url, nil)
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+	return request.DoRequest(ctx, cfg, "DELETE", url, nil, nil)
+}
+
+// AddSwap creates a CID swap
+func (s *PublicService) AddSwap(opts *SwapOptions) (*types.SwapResponse, error) {
+	return s.AddSwapContext(context.Background(), opts)
+}
+
+// AddSwapContext is like AddSwap but carries ctx through to the underlying requests
+func (s *PublicService) AddSwapContext(ctx context.Context, opts *SwapOptions) (*types.SwapResponse, error) {
+	if opts == nil || opts.CID == "" {
+		return nil, ErrNoCID
+	}
+	if opts.SwapCID == "" {
+		return nil, ErrMissingSwapCID
 	}
 
-	req.Header.Set("Authorization", "Bearer "+cfg.PinataJWT)
-	req.Header.Set("Content-Type", "application/json")
+	cfg := s.config.(*types.Config)
+	url := fmt.Sprintf("%s/files/public/swap/%s", cfg.APIUrl, opts.CID)
 
-	// Add custom headers if any
-	for key, value := range cfg.CustomHeaders {
-		req.Header.Set(key, value)
+	payload := struct {
+		SwapCID string `json:"swap_cid"`
+	}{
+		SwapCID: opts.SwapCID,
 	}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to send request: %w", err)
+	var response *types.SwapResponse
+	if err := request.DoRequest(ctx, cfg, "PUT", url, payload, &response); err != nil {
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+	if response != nil && response.CID == "" {
+		response.CID = opts.CID
 	}
 
-	return nil
+	return response, nil
 }
 
-// PinByHash pins a CID that already exists on IPFS
-func (s *PublicService) PinByHash(opts *PinByHashOptions) (*types.PinByHashResponse, error) {
+// GetSwapHistory retrieves the swap history for a CID
+func (s *PublicService) GetSwapHistory(opts *SwapHistoryOptions) ([]types.SwapResponse, error) {
+	return s.GetSwapHistoryContext(context.Background(), opts)
+}
+
+// GetSwapHistoryContext is like GetSwapHistory but carries ctx through to the underlying requests
+func (s *PublicService) GetSwapHistoryContext(ctx context.Context, opts *SwapHistoryOptions) ([]types.SwapResponse, error) {
 	if opts == nil || opts.CID == "" {
-		return nil, fmt.Errorf("CID is required")
+		return nil, ErrNoCID
+	}
+	if opts.Domain == "" {
+		return nil, fmt.Errorf("domain is required")
 	}
 
 	cfg := s.config.(*types.Config)
-	url := fmt.Sprintf("%s/files/public/pin_by_cid", cfg.APIUrl)
+	url := fmt.Sprintf("%s/files/public/swap/%s?domain=%s", cfg.APIUrl, opts.CID, url.QueryEscape(opts.Domain))
 
-	jsonData, err := json.Marshal(opts)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request body: %w", err)
+	var response []types.SwapResponse
+	if err := request.DoRequest(ctx, cfg, "GET", url, nil, &response); err != nil {
+		return nil, err
 	}
 
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+	for i := range response {
+		if response[i].CID == "" {
+			response[i].CID = opts.CID
+		}
+		if response[i].Domain == "" {
+			response[i].Domain = opts.Domain
+		}
 	}
 
-	req.Header.Set("Authorization", "Bearer "+cfg.PinataJWT)
-	req.Header.Set("Content-Type", "application/json")
+	return response, nil
+}
 
-	// Add custom headers if any
-	for key, value := range cfg.CustomHeaders {
-		req.Header.Set(key, value)
-	}
+// DeleteSwap removes a CID swap
+func (s *PublicService) DeleteSwap(cid string) error {
+	return s.DeleteSwapContext(context.Background(), cid)
+}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
+// DeleteSwapContext is like DeleteSwap but carries ctx through to the underlying requests
+func (s *PublicService) DeleteSwapContext(ctx context.Context, cid string) error {
+	if cid == "" {
+		return ErrNoCID
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
-	}
+	cfg := s.config.(*types.Config)
+	url := fmt.Sprintf("%s/files/public/swap/%s", cfg.APIUrl, cid)
+
+	return request.DoRequest(ctx, cfg, "DELETE", url, nil, nil)
+}
+
+// PinByHash pins a CID that already exists on IPFS
+func (s *PublicService) PinByHash(opts *PinByHashOptions) (*types.PinByHashResponse, error) {
+	return s.PinByHashContext(context.Background(), opts)
+}
 
-	var response struct {
-		Data *types.PinByHashResponse `json:"data"`
+// PinByHashContext is like PinByHash but carries ctx through to the underlying requests
+func (s *PublicService) PinByHashContext(ctx context.Context, opts *PinByHashOptions) (*types.PinByHashResponse, error) {
+	if opts == nil || opts.CID == "" {
+		return nil, ErrNoCID
+	}
+	if err := types.ValidateHostNodes(opts.HostNodes); err != nil {
+		return nil, err
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	cfg := s.config.(*types.Config)
+	url := fmt.Sprintf("%s/files/public/pin_by_cid", cfg.APIUrl)
+
+	var response *types.PinByHashResponse
+	if err := request.DoRequest(ctx, cfg, "POST", url, opts, &response); err != nil {
+		return nil, err
 	}
 
-	return response.Data, nil
+	return response, nil
 }
 
 // Queue returns a list of pin by hash requests
 func (s *PublicService) Queue(opts *PinQueueOptions) (*types.PinQueueResponse, error) {
+	return s.QueueContext(context.Background(), opts)
+}
+
+// QueueContext is like Queue but carries ctx through to the underlying requests
+func (s *PublicService) QueueContext(ctx context.Context, opts *PinQueueOptions) (*types.PinQueueResponse, error) {
 	cfg := s.config.(*types.Config)
 	baseURL := fmt.Sprintf("%s/files/public/pin_by_cid", cfg.APIUrl)
 
@@ -474,44 +760,90 @@ func (s *PublicService) Queue(opts *PinQueueOptions) (*types.PinQueueResponse, e
 		requestURL = fmt.Sprintf("%s?%s", baseURL, params.Encode())
 	}
 
-	req, err := http.NewRequest("GET", requestURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+	var response *types.PinQueueResponse
+	if err := request.DoRequest(ctx, cfg, "GET", requestURL, nil, &response); err != nil {
+		return nil, err
 	}
 
-	req.Header.Set("Authorization", "Bearer "+cfg.PinataJWT)
-	req.Header.Set("Content-Type", "application/json")
+	return response, nil
+}
 
-	// Add custom headers if any
-	for key, value := range cfg.CustomHeaders {
-		req.Header.Set(key, value)
-	}
+// QueueAll retrieves every pin by hash request matching opts, following
+// NextPageToken until all pages have been read. opts.Limit sets the page size.
+func (s *PublicService) QueueAll(opts *PinQueueOptions) ([]types.PinQueueItem, error) {
+	return s.QueueAllContext(context.Background(), opts)
+}
+
+// QueueAllContext is like QueueAll but carries ctx through to the underlying requests
+func (s *PublicService) QueueAllContext(ctx context.Context, opts *PinQueueOptions) ([]types.PinQueueItem, error) {
+	return collectPinQueue(s.QueueIterContext(ctx, opts))
+}
+
+// QueueIter returns an iterator over every pin by hash request matching opts.
+// Pages are fetched lazily.
+func (s *PublicService) QueueIter(opts *PinQueueOptions) *PinQueueIterator {
+	return s.QueueIterContext(context.Background(), opts)
+}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+// QueueIterContext is like QueueIter but carries ctx through to the underlying requests
+func (s *PublicService) QueueIterContext(ctx context.Context, opts *PinQueueOptions) *PinQueueIterator {
+	return newPinQueueIterator(ctx, s.QueueContext, opts)
+}
+
+// CancelAllPinRequests cancels every queued pin by hash request, or only those
+// with the given status when it is not empty. The whole queue is read before any
+// request is cancelled, then the cancellations run concurrently and every one is
+// attempted. It returns how many were cancelled and how many failed; the error is
+// non-nil if any failed.
+func (s *PublicService) CancelAllPinRequests(status string) (cancelled int, failed int, err error) {
+	return s.CancelAllPinRequestsContext(context.Background(), status)
+}
+
+// CancelAllPinRequestsContext is like CancelAllPinRequests but carries ctx through to the underlying requests
+func (s *PublicService) CancelAllPinRequestsContext(ctx context.Context, status string) (cancelled int, failed int, err error) {
+	items, err := s.QueueAllContext(ctx, &PinQueueOptions{Status: status})
 	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
+		return 0, 0, fmt.Errorf("failed to list pin requests: %w", err)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+	ids := make([]string, len(items))
+	for i, item := range items {
+		ids[i] = item.ID
 	}
 
-	var response struct {
-		Data *types.PinQueueResponse `json:"data"`
+	errs := make([]error, len(ids))
+	attempted := make([]bool, len(ids))
+	batch.Run(ctx, len(ids), batch.DefaultConcurrency, func(ctx context.Context, i int) {
+		attempted[i] = true
+		errs[i] = s.CancelPinRequestContext(ctx, ids[i])
+	})
+
+	for i := range ids {
+		if attempted[i] && errs[i] == nil {
+			cancelled++
+		} else {
+			failed++
+		}
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := ctx.Err(); err != nil {
+		return cancelled, failed, err
 	}
 
-	return response.Data, nil
+	if failed > 0 {
+		return cancelled, failed, fmt.Errorf("%d of %d cancellations failed", failed, len(ids))
+	}
+
+	return cancelled, failed, nil
 }
 
 // CancelPinRequest cancels a pin by hash request
 func (s *PublicService) CancelPinRequest(id string) error {
+	return s.CancelPinRequestContext(context.Background(), id)
+}
+
+// CancelPinRequestContext is like CancelPinRequest but carries ctx through to the underlying requests
+func (s *PublicService) CancelPinRequestContext(ctx context.Context, id string) error {
 	if id == "" {
 		return fmt.Errorf("request ID is required")
 	}
@@ -519,30 +851,5 @@ func (s *PublicService) CancelPinRequest(id string) error {
 	cfg := s.config.(*types.Config)
 	url := fmt.Sprintf("%s/files/public/pin_by_cid/%s", cfg.APIUrl, id)
 
-	req, err := http.NewRequest("DELETE", url, nil)
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.Header.Set("Authorization", "Bearer "+cfg.PinataJWT)
-	req.Header.Set("Content-Type", "application/json")
-
-	// Add custom headers if any
-	for key, value := range cfg.CustomHeaders {
-		req.Header.Set(key, value)
-	}
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to send request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
-	}
-
-	return nil
+	return request.DoRequest(ctx, cfg, "DELETE", url, nil, nil)
 }
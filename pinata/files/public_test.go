@@ -0,0 +1,120 @@
+package files
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/PinataCloud/pinata-go-sdk/pinata/types"
+)
+
+// newTestConfig starts a server running handler and returns a config pointing
+// the API, uploads and the gateway at it. Gateway downloads use https, so the
+// server runs TLS and the config trusts its certificate.
+func newTestConfig(t *testing.T, handler http.HandlerFunc) *types.Config {
+	t.Helper()
+
+	srv := httptest.NewTLSServer(handler)
+	t.Cleanup(srv.Close)
+	return &types.Config{
+		PinataJWT:     "jwt",
+		APIUrl:        srv.URL,
+		UploadUrl:     srv.URL,
+		PinataGateway: srv.Listener.Addr().String(),
+		HTTPClient:    srv.Client(),
+	}
+}
+
+func TestGet(t *testing.T) {
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/files/public/file-1" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, `{"data":{"id":"file-1","name":"a.txt","cid":"bafy","size":5}}`)
+	})
+
+	file, err := NewPublicService(cfg).Get("file-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if file.ID != "file-1" || file.Name != "a.txt" || file.Size != 5 {
+		t.Fatalf("got %+v", file)
+	}
+}
+
+func TestGetAPIError(t *testing.T) {
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "req-1")
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"error":{"reason":"NOT_FOUND","details":"file not found"}}`)
+	})
+
+	_, err := NewPublicService(cfg).Get("missing")
+
+	var apiErr *types.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("got %v, want a *types.APIError", err)
+	}
+	if apiErr.StatusCode != http.StatusNotFound || apiErr.Code != "NOT_FOUND" || apiErr.Message != "file not found" {
+		t.Fatalf("got %+v", apiErr)
+	}
+	if !types.IsNotFound(err) || !errors.Is(err, &types.APIError{StatusCode: http.StatusNotFound}) {
+		t.Fatal("error does not match a 404")
+	}
+}
+
+func TestCreateAccessLinkAppendsGatewayToken(t *testing.T) {
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data":"https://example.mypinata.cloud/files/bafy?X-Signature=sig&X-Expires=30"}`)
+	})
+	cfg.PinataGateway = "example"
+	cfg.PinataGatewayKey = "gateway-key"
+
+	link, err := NewPrivateService(cfg).CreateAccessLink(&types.AccessLinkOptions{CID: "bafy", Expires: 30})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "https://example.mypinata.cloud/files/bafy?X-Signature=sig&X-Expires=30&pinataGatewayToken=gateway-key"
+	if link != want {
+		t.Fatalf("got %s, want %s", link, want)
+	}
+}
+
+func TestCreateAccessLinkGatewayTokenCases(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		key  string
+		want string
+	}{
+		{"escaped ampersand", `{"data":"https://gw.example/files/bafy?X-Signature=sig\\u0026X-Expires=30"}`, "key",
+			"https://gw.example/files/bafy?X-Signature=sig&X-Expires=30&pinataGatewayToken=key"},
+		{"no query", `{"data":"https://gw.example/files/bafy"}`, "key",
+			"https://gw.example/files/bafy?pinataGatewayToken=key"},
+		{"key escaped", `{"data":"https://gw.example/files/bafy?X-Signature=sig"}`, "a+b/c",
+			"https://gw.example/files/bafy?X-Signature=sig&pinataGatewayToken=a%2Bb%2Fc"},
+		{"token already present", `{"data":"https://gw.example/files/bafy?pinataGatewayToken=api"}`, "key",
+			"https://gw.example/files/bafy?pinataGatewayToken=api"},
+		{"no key", `{"data":"https://gw.example/files/bafy?X-Signature=sig"}`, "",
+			"https://gw.example/files/bafy?X-Signature=sig"},
+	}
+
+	for _, tt := range tests {
+		cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, tt.body)
+		})
+		cfg.PinataGateway = "gw.example"
+		cfg.PinataGatewayKey = tt.key
+
+		link, err := NewPrivateService(cfg).CreateAccessLink(&types.AccessLinkOptions{CID: "bafy", Expires: 30})
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if link != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.name, link, tt.want)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package files
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/PinataCloud/pinata-go-sdk/pinata/types"
+)
+
+// queryPayload is the body of a vector query request
+type queryPayload struct {
+	Text       string `json:"text"`
+	Limit      int    `json:"limit"`
+	ReturnFile bool   `json:"return_file"`
+}
+
+// newQueryTest serves vector queries on group-1 with three matches of falling
+// score, or a matched file when return_file is set, and file records for
+// file-1 to file-3
+func newQueryTest(t *testing.T) (*types.Config, *[]queryPayload, *[]string) {
+	t.Helper()
+
+	var mu sync.Mutex
+	var payloads []queryPayload
+	var lookups []string
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/vectorize/groups/group-1/query":
+			var payload queryPayload
+			json.NewDecoder(r.Body).Decode(&payload)
+			payloads = append(payloads, payload)
+			if payload.ReturnFile {
+				w.Header().Set("Content-Type", "text/plain")
+				fmt.Fprint(w, "best match content")
+				return
+			}
+			fmt.Fprint(w, `{"data":{"count":3,"matches":[
+				{"file_id":"file-1","cid":"bafy1","score":0.9},
+				{"file_id":"file-2","cid":"bafy2","score":0.6},
+				{"file_id":"file-3","cid":"bafy3","score":0.2}]}}`)
+		case r.Method == "GET" && len(r.URL.Path) > len("/files/private/"):
+			id := r.URL.Path[len("/files/private/"):]
+			lookups = append(lookups, id)
+			fmt.Fprintf(w, `{"data":{"id":%q,"name":"%s.txt","keyvalues":{"source":"test"}}}`, id, id)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	return cfg, &payloads, &lookups
+}
+
+func matchIDs(matches []types.VectorMatch) []string {
+	ids := make([]string, len(matches))
+	for i, match := range matches {
+		ids[i] = match.FileID
+	}
+	return ids
+}
+
+func TestQueryVectorsLimitAndMinScore(t *testing.T) {
+	cfg, payloads, _ := newQueryTest(t)
+	service := NewPrivateService(cfg)
+
+	resp, err := service.QueryVectors(&types.VectorQueryOptions{GroupID: "group-1", Query: "cats", Limit: 5, MinScore: 0.5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(matchIDs(resp.Matches)) != "[file-1 file-2]" || resp.Count != 2 {
+		t.Fatalf("got %d matches %v, want the 2 scoring at least 0.5", resp.Count, matchIDs(resp.Matches))
+	}
+	if (*payloads)[0] != (queryPayload{Text: "cats", Limit: 5}) {
+		t.Fatalf("sent %+v", (*payloads)[0])
+	}
+
+	// A server returning more than the limit is cut down to it
+	resp, err = service.QueryVectors(&types.VectorQueryOptions{GroupID: "group-1", Query: "cats", Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(matchIDs(resp.Matches)) != "[file-1]" {
+		t.Fatalf("got matches %v, want only the best", matchIDs(resp.Matches))
+	}
+}
+
+func TestQueryVectorsReturnFile(t *testing.T) {
+	cfg, payloads, _ := newQueryTest(t)
+
+	resp, err := NewPrivateService(cfg).QueryVectors(&types.VectorQueryOptions{GroupID: "group-1", Query: "cats", ReturnFile: true, Limit: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(resp.Data) != "best match content" || resp.ContentType != "text/plain" {
+		t.Fatalf("got %q (%s)", resp.Data, resp.ContentType)
+	}
+	if !(*payloads)[0].ReturnFile || (*payloads)[0].Limit != 3 {
+		t.Fatalf("sent %+v", (*payloads)[0])
+	}
+}
+
+func TestQueryVectorsIncludeMetadata(t *testing.T) {
+	cfg, _, lookups := newQueryTest(t)
+
+	resp, err := NewPrivateService(cfg).QueryVectors(&types.VectorQueryOptions{GroupID: "group-1", Query: "cats", MinScore: 0.5, IncludeMetadata: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, match := range resp.Matches {
+		if match.File == nil || match.File.ID != match.FileID || match.File.Name != match.FileID+".txt" || match.File.KeyValues["source"] != "test" {
+			t.Fatalf("match %s has metadata %+v", match.FileID, match.File)
+		}
+	}
+	// Only the matches kept after filtering are looked up
+	if len(*lookups) != 2 {
+		t.Fatalf("looked up %v, want the 2 kept matches", *lookups)
+	}
+}
+
+func TestQueryVectorsWithoutMetadata(t *testing.T) {
+	cfg, _, lookups := newQueryTest(t)
+
+	resp, err := NewPrivateService(cfg).QueryVectors(&types.VectorQueryOptions{GroupID: "group-1", Query: "cats"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Matches[0].File != nil || len(*lookups) != 0 {
+		t.Fatalf("metadata fetched without IncludeMetadata: %v", *lookups)
+	}
+}
+
+func TestQueryVectorsStream(t *testing.T) {
+	cfg, payloads, _ := newQueryTest(t)
+	service := NewPrivateService(cfg)
+
+	// ReturnFile is implied, so the option need not be set
+	body, contentType, err := service.QueryVectorsStream(&types.VectorQueryOptions{GroupID: "group-1", Query: "match"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer body.Close()
+	data, err := io.ReadAll(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "best match content" || contentType != "text/plain" {
+		t.Fatalf("streamed %q as %q", data, contentType)
+	}
+	if len(*payloads) != 1 || !(*payloads)[0].ReturnFile {
+		t.Fatalf("sent %+v", *payloads)
+	}
+
+	if _, _, err := service.QueryVectorsStream(&types.VectorQueryOptions{GroupID: "missing", Query: "match"}); !types.IsNotFound(err) {
+		t.Fatalf("unknown group: got %v", err)
+	}
+	if _, _, err := service.QueryVectorsStream(nil); err == nil {
+		t.Fatal("nil options: got no error")
+	}
+}
+
+func TestQueryByFile(t *testing.T) {
+	var bodies []string
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/files/private/file-v":
+			fmt.Fprint(w, `{"data":{"id":"file-v","vectorized":true}}`)
+		case "/files/private/file-n":
+			fmt.Fprint(w, `{"data":{"id":"file-n","vectorized":false}}`)
+		case "/vectorize/groups/group-1/query":
+			body, _ := io.ReadAll(r.Body)
+			bodies = append(bodies, string(body))
+			fmt.Fprint(w, `{"data":{"count":1,"matches":[{"file_id":"file-2","cid":"bafy2","score":0.8}]}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	service := NewPrivateService(cfg)
+
+	resp, err := service.QueryByFile("group-1", "file-v", &types.VectorQueryOptions{Query: "ignored", Limit: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(matchIDs(resp.Matches)) != "[file-2]" {
+		t.Fatalf("got %+v", resp)
+	}
+	if len(bodies) != 1 || bodies[0] != `{"file_id":"file-v","limit":3}` {
+		t.Fatalf("sent %q", bodies)
+	}
+
+	if _, err := service.QueryByFile("group-1", "file-n", nil); !errors.Is(err, ErrNotVectorized) {
+		t.Fatalf("not vectorized: got %v", err)
+	}
+	if _, err := service.QueryByFile("group-1", "file-x", nil); !types.IsNotFound(err) {
+		t.Fatalf("missing file: got %v", err)
+	}
+	if _, err := service.QueryByFile("group-1", "", nil); !errors.Is(err, ErrNoFileID) {
+		t.Fatalf("no file ID: got %v", err)
+	}
+	if len(bodies) != 1 {
+		t.Fatalf("sent %d queries, want 1", len(bodies))
+	}
+}
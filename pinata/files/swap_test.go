@@ -0,0 +1,112 @@
+package files
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/PinataCloud/pinata-go-sdk/pinata/types"
+)
+
+func TestGetSwapHistory(t *testing.T) {
+	var requested string
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		requested = r.URL.RequestURI()
+		fmt.Fprint(w, `{"data":[
+			{"mapped_cid":"bafy-v2","created_at":"2024-05-01T10:00:00Z"},
+			{"mapped_cid":"bafy-v3","created_at":"2024-06-01T10:00:00Z","cid":"bafy-echoed","domain":"cdn.example.com"}]}`)
+	})
+
+	history, err := NewPublicService(cfg).GetSwapHistory(&SwapHistoryOptions{CID: "bafy-v1", Domain: "example.mypinata.cloud"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if requested != "/files/public/swap/bafy-v1?domain=example.mypinata.cloud" {
+		t.Fatalf("requested %s", requested)
+	}
+
+	want := []types.SwapResponse{
+		{MappedCID: "bafy-v2", CreatedAt: "2024-05-01T10:00:00Z", CID: "bafy-v1", Domain: "example.mypinata.cloud"},
+		{MappedCID: "bafy-v3", CreatedAt: "2024-06-01T10:00:00Z", CID: "bafy-echoed", Domain: "cdn.example.com"},
+	}
+	if fmt.Sprint(history) != fmt.Sprint(want) {
+		t.Fatalf("got %+v, want %+v", history, want)
+	}
+}
+
+func TestGetSwapHistoryPrivate(t *testing.T) {
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/files/private/swap/bafy-v1" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, `{"data":[{"mapped_cid":"bafy-v2","created_at":"2024-05-01T10:00:00Z"}]}`)
+	})
+
+	history, err := NewPrivateService(cfg).GetSwapHistory(&SwapHistoryOptions{CID: "bafy-v1", Domain: "example"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 1 || history[0].MappedCID != "bafy-v2" || history[0].CID != "bafy-v1" || history[0].Domain != "example" {
+		t.Fatalf("got %+v", history)
+	}
+}
+
+func TestReplace(t *testing.T) {
+	var calls []string
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/files/public/file-1":
+			calls = append(calls, "get")
+			fmt.Fprint(w, `{"data":{"id":"file-1","name":"report.txt","cid":"bafy-v1","group_id":"group-1","keyvalues":{"env":"prod"}}}`)
+		case r.Method == "POST" && r.URL.Path == "/files":
+			calls = append(calls, fmt.Sprintf("upload name=%s group=%s keyvalues=%s",
+				r.FormValue("name"), r.FormValue("group_id"), r.FormValue("keyvalues")))
+			fmt.Fprint(w, `{"data":{"id":"file-2","name":"report.txt","cid":"bafy-v2"}}`)
+		case r.Method == "PUT" && r.URL.Path == "/files/public/swap/bafy-v1":
+			var payload struct {
+				SwapCID string `json:"swap_cid"`
+			}
+			json.NewDecoder(r.Body).Decode(&payload)
+			calls = append(calls, "swap to "+payload.SwapCID)
+			fmt.Fprint(w, `{"data":{"mapped_cid":"bafy-v2","created_at":"2024-05-01T10:00:00Z"}}`)
+		case r.Method == "DELETE" && r.URL.Path == "/files/public/file-1":
+			calls = append(calls, "delete")
+			fmt.Fprint(w, `{"data":null}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	path := filepath.Join(t.TempDir(), "new.txt")
+	if err := os.WriteFile(path, []byte("v2"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	uploaded, swap, err := NewPublicService(cfg).Replace("file-1", file, &ReplaceOptions{DeleteOld: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uploaded.ID != "file-2" || swap == nil || swap.MappedCID != "bafy-v2" {
+		t.Fatalf("got %+v and %+v", uploaded, swap)
+	}
+
+	want := []string{
+		"get",
+		`upload name=report.txt group=group-1 keyvalues={"env":"prod"}`,
+		"swap to bafy-v2",
+		"delete",
+	}
+	if strings.Join(calls, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("calls:\n%s\nwant:\n%s", strings.Join(calls, "\n"), strings.Join(want, "\n"))
+	}
+}
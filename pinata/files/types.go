@@ -1,5 +1,14 @@
 package files
 
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/PinataCloud/pinata-go-sdk/pinata/types"
+	"github.com/PinataCloud/pinata-go-sdk/pinata/upload"
+)
+
 // ListOptions represents options for the List method
 type ListOptions struct {
 	Name       string
@@ -9,9 +18,105 @@ type ListOptions struct {
 	CIDPending bool
 	MimeType   string
 	KeyValues  map[string]string
-	Order      string
-	Limit      int
-	PageToken  string
+	// Order is the sort direction, asc or desc
+	Order string
+	// SortBy is the field the results are sorted by, one of the SortBy
+	// constants. Empty leaves the API's default order.
+	SortBy    string
+	Limit     int
+	PageToken string
+	// CreatedAfter and CreatedBefore limit the results to files created in that
+	// range. Either may be left zero to leave that end open.
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	// KeyValueFilters matches keyvalues with an operator instead of the exact
+	// match used by KeyValues. Both may be set.
+	KeyValueFilters map[string]KeyValueFilter
+	// MinSize and MaxSize limit the results to files of that many bytes, inclusive.
+	// Zero leaves that end open. The API cannot filter by size, so each page is
+	// filtered after it is fetched and may hold fewer than Limit files, or none,
+	// while NextPageToken still points at further pages. ListAll and ListIter
+	// follow those pages.
+	MinSize int64
+	MaxSize int64
+}
+
+// NextPage copies resp.NextPageToken into PageToken and reports whether there
+// is another page to fetch
+func (o *ListOptions) NextPage(resp *types.FileListResponse) bool {
+	if !resp.HasMore() {
+		return false
+	}
+	o.PageToken = resp.NextPageToken
+	return true
+}
+
+// filtersSize reports whether MinSize or MaxSize is set
+func (o *ListOptions) filtersSize() bool {
+	return o != nil && (o.MinSize > 0 || o.MaxSize > 0)
+}
+
+// filterSize returns the files within MinSize and MaxSize, reusing the slice
+func (o *ListOptions) filterSize(files []types.File) []types.File {
+	if !o.filtersSize() {
+		return files
+	}
+
+	kept := files[:0]
+	for _, file := range files {
+		if o.MinSize > 0 && file.Size < o.MinSize {
+			continue
+		}
+		if o.MaxSize > 0 && file.Size > o.MaxSize {
+			continue
+		}
+		kept = append(kept, file)
+	}
+	return kept
+}
+
+// Fields for ListOptions.SortBy
+const (
+	SortByName      = "name"
+	SortBySize      = "size"
+	SortByCreatedAt = "created_at"
+)
+
+// checkSort rejects a SortBy outside the SortBy constants and an Order other
+// than asc or desc
+func (o *ListOptions) checkSort() error {
+	switch o.SortBy {
+	case "", SortByName, SortBySize, SortByCreatedAt:
+	default:
+		return fmt.Errorf("SortBy %q is not one of %s, %s, %s", o.SortBy, SortByName, SortBySize, SortByCreatedAt)
+	}
+
+	switch strings.ToLower(o.Order) {
+	case "", "asc", "desc":
+	default:
+		return fmt.Errorf("Order %q is not asc or desc", o.Order)
+	}
+
+	return nil
+}
+
+// Operators for KeyValueFilter.Op
+const (
+	OpEqual              = "eq"
+	OpNotEqual           = "ne"
+	OpGreaterThan        = "gt"
+	OpGreaterThanOrEqual = "gte"
+	OpLessThan           = "lt"
+	OpLessThanOrEqual    = "lte"
+	OpLike               = "like"
+	OpNotLike            = "notLike"
+)
+
+// KeyValueFilter matches a keyvalue using Op, one of the Op constants. An empty
+// Op is an equality match.
+type KeyValueFilter struct {
+	Value string
+	Op    string
 }
 
 // UpdateOptions represents options for the Update method
@@ -19,6 +124,36 @@ type UpdateOptions struct {
 	ID        string            `json:"-"`
 	Name      string            `json:"name,omitempty"`
 	KeyValues map[string]string `json:"keyvalues,omitempty"`
+	// MergeKeyValues fetches the file first and merges KeyValues into its existing
+	// keyvalues, so single tags can be added or changed without resending the rest.
+	// By default KeyValues is sent as given and replaces the stored keyvalues.
+	MergeKeyValues bool `json:"-"`
+}
+
+// DeleteByCIDOptions represents options for the DeleteByCID method
+type DeleteByCIDOptions struct {
+	// AllowMultiple deletes every file with a CID. By default a CID held by more
+	// than one file is left alone and reported with ErrMultipleFiles.
+	AllowMultiple bool
+}
+
+// ReplaceOptions represents options for the Replace method. FileName, GroupID and
+// KeyValues default to those of the file being replaced.
+type ReplaceOptions struct {
+	upload.FileOptions
+	// DeleteOld deletes the replaced file once the swap is in place
+	DeleteOld bool
+}
+
+// MoveOptions represents options for the MoveToPublic and MoveToPrivate methods.
+// FileName and KeyValues default to those of the file being moved. Groups belong
+// to one network, so unless GroupID, GroupName or NoGroup is set the moved file
+// goes into the group with the same name as the source file's group on the
+// other network, which is created if needed.
+type MoveOptions struct {
+	upload.FileOptions
+	// DeleteSource deletes the source file once the copy has been uploaded
+	DeleteSource bool
 }
 
 // SwapOptions represents options for AddSwap method
@@ -44,7 +179,21 @@ type PinByHashOptions struct {
 	Name      string            `json:"name,omitempty"`
 	GroupID   string            `json:"group_id,omitempty"`
 	KeyValues map[string]string `json:"keyvalues,omitempty"`
-	HostNodes []string          `json:"host_nodes,omitempty"`
+	// HostNodes are multiaddrs of peers that already have the content, which
+	// are checked with types.ValidateHostNodes before the request is sent
+	HostNodes []string `json:"host_nodes,omitempty"`
+}
+
+// WithHostNodes validates nodes as multiaddrs and sets them as the host nodes.
+// On error the options are left unchanged and the error is an
+// *types.InvalidHostNodesError listing every invalid entry.
+func (o *PinByHashOptions) WithHostNodes(nodes ...string) (*PinByHashOptions, error) {
+	if err := types.ValidateHostNodes(nodes); err != nil {
+		return o, err
+	}
+
+	o.HostNodes = nodes
+	return o, nil
 }
 
 // PinQueueOptions represents options for querying the pin queue
@@ -55,3 +204,13 @@ type PinQueueOptions struct {
 	Limit     int
 	PageToken string
 }
+
+// NextPage copies resp.NextPageToken into PageToken and reports whether there
+// is another page to fetch
+func (o *PinQueueOptions) NextPage(resp *types.PinQueueResponse) bool {
+	if !resp.HasMore() {
+		return false
+	}
+	o.PageToken = resp.NextPageToken
+	return true
+}
@@ -0,0 +1,32 @@
+package files
+
+import (
+	"testing"
+
+	"github.com/PinataCloud/pinata-go-sdk/pinata/types"
+)
+
+func TestListOptionsNextPage(t *testing.T) {
+	opts := ListOptions{Name: "a", PageToken: "p1"}
+
+	if opts.NextPage(&types.FileListResponse{}) || opts.PageToken != "p1" {
+		t.Fatalf("empty token: got more pages or token %q changed", opts.PageToken)
+	}
+	if opts.NextPage(nil) {
+		t.Fatal("nil response reported more pages")
+	}
+	if !opts.NextPage(&types.FileListResponse{NextPageToken: "p2"}) || opts.PageToken != "p2" || opts.Name != "a" {
+		t.Fatalf("got %+v, want the token copied and the rest kept", opts)
+	}
+}
+
+func TestPinQueueOptionsNextPage(t *testing.T) {
+	opts := PinQueueOptions{Status: "retrieving"}
+
+	if opts.NextPage(&types.PinQueueResponse{}) || opts.PageToken != "" {
+		t.Fatalf("empty token: got more pages or token %q", opts.PageToken)
+	}
+	if !opts.NextPage(&types.PinQueueResponse{NextPageToken: "q2"}) || opts.PageToken != "q2" {
+		t.Fatalf("got %+v, want the token copied", opts)
+	}
+}
@@ -0,0 +1,142 @@
+package files
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/PinataCloud/pinata-go-sdk/pinata/types"
+)
+
+// fileStore fakes GET and PUT of a single file's metadata at
+// /files/{network}/file-1. A PUT replaces the keyvalues when it sends them.
+type fileStore struct {
+	mu        sync.Mutex
+	file      types.File
+	requests  []string
+	lastWrite map[string]json.RawMessage
+}
+
+func newFileStore(t *testing.T, keyvalues map[string]string) (*fileStore, *types.Config) {
+	t.Helper()
+
+	store := &fileStore{file: types.File{ID: "file-1", Name: "report.pdf", KeyValues: keyvalues}}
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		store.mu.Lock()
+		defer store.mu.Unlock()
+
+		store.requests = append(store.requests, r.Method+" "+r.URL.Path)
+		if r.Method == "PUT" {
+			json.NewDecoder(r.Body).Decode(&store.lastWrite)
+			if name, ok := store.lastWrite["name"]; ok {
+				json.Unmarshal(name, &store.file.Name)
+			}
+			if keyvalues, ok := store.lastWrite["keyvalues"]; ok {
+				store.file.KeyValues = nil
+				json.Unmarshal(keyvalues, &store.file.KeyValues)
+			}
+		}
+		body, _ := json.Marshal(map[string]types.File{"data": store.file})
+		w.Write(body)
+	})
+	return store, cfg
+}
+
+func TestUpdateReplacesKeyValuesByDefault(t *testing.T) {
+	store, cfg := newFileStore(t, map[string]string{"env": "prod", "team": "data"})
+
+	file, err := NewPublicService(cfg).Update(&UpdateOptions{ID: "file-1", KeyValues: map[string]string{"tag": "new"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(file.KeyValues) != "map[tag:new]" || file.Name != "report.pdf" {
+		t.Fatalf("got %+v", file)
+	}
+	if fmt.Sprint(store.requests) != "[PUT /files/public/file-1]" {
+		t.Fatalf("requests %v, want a single PUT", store.requests)
+	}
+	if _, ok := store.lastWrite["name"]; ok {
+		t.Fatal("an empty name was sent")
+	}
+}
+
+func TestUpdateMergesKeyValues(t *testing.T) {
+	store, cfg := newFileStore(t, map[string]string{"env": "prod", "team": "data"})
+
+	file, err := NewPrivateService(cfg).Update(&UpdateOptions{
+		ID:             "file-1",
+		KeyValues:      map[string]string{"env": "staging", "tag": "new"},
+		MergeKeyValues: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(file.KeyValues) != "map[env:staging tag:new team:data]" {
+		t.Fatalf("got keyvalues %v", file.KeyValues)
+	}
+	if fmt.Sprint(store.requests) != "[GET /files/private/file-1 PUT /files/private/file-1]" {
+		t.Fatalf("requests %v, want GET then PUT", store.requests)
+	}
+}
+
+func TestUpdateMergeWithoutKeyValuesOnlyRenames(t *testing.T) {
+	store, cfg := newFileStore(t, map[string]string{"env": "prod"})
+
+	file, err := NewPublicService(cfg).Update(&UpdateOptions{ID: "file-1", Name: "renamed.pdf", MergeKeyValues: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if file.Name != "renamed.pdf" || fmt.Sprint(file.KeyValues) != "map[env:prod]" {
+		t.Fatalf("got %+v", file)
+	}
+	if len(store.requests) != 1 {
+		t.Fatalf("requests %v, want a single PUT", store.requests)
+	}
+}
+
+func TestRemoveKeyValues(t *testing.T) {
+	store, cfg := newFileStore(t, map[string]string{"env": "prod", "team": "data", "tmp": "1"})
+
+	file, err := NewPublicService(cfg).RemoveKeyValues("file-1", []string{"tmp", "env", "missing"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(file.KeyValues) != "map[team:data]" {
+		t.Fatalf("got keyvalues %v", file.KeyValues)
+	}
+	if _, ok := store.lastWrite["name"]; ok {
+		t.Fatal("the name was resent")
+	}
+}
+
+func TestRemoveKeyValuesLastKey(t *testing.T) {
+	store, cfg := newFileStore(t, map[string]string{"env": "prod"})
+
+	file, err := NewPrivateService(cfg).RemoveKeyValues("file-1", []string{"env"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(file.KeyValues) != 0 {
+		t.Fatalf("got keyvalues %v", file.KeyValues)
+	}
+	if string(store.lastWrite["keyvalues"]) != "{}" {
+		t.Fatalf("sent keyvalues %s, want an empty object", store.lastWrite["keyvalues"])
+	}
+}
+
+func TestRemoveKeyValuesMissingKeysIsNoop(t *testing.T) {
+	store, cfg := newFileStore(t, map[string]string{"env": "prod"})
+
+	file, err := NewPublicService(cfg).RemoveKeyValues("file-1", []string{"missing"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(file.KeyValues) != "map[env:prod]" {
+		t.Fatalf("got keyvalues %v", file.KeyValues)
+	}
+	if fmt.Sprint(store.requests) != "[GET /files/public/file-1]" {
+		t.Fatalf("requests %v, want only the GET", store.requests)
+	}
+}
@@ -0,0 +1,216 @@
+package files
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/PinataCloud/pinata-go-sdk/pinata/types"
+	"github.com/PinataCloud/pinata-go-sdk/pinata/upload"
+)
+
+func TestFileAndVectorize(t *testing.T) {
+	tests := []struct {
+		name          string
+		inline        bool
+		wantVectorize int
+	}{
+		{"vectorized inline", true, 0},
+		{"vectorized afterwards", false, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vectorizeCalls := 0
+			cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.Method == "POST" && r.URL.Path == "/files":
+					if r.FormValue("vectorize") != "true" {
+						t.Errorf("upload did not ask for vectorizing")
+					}
+					fmt.Fprintf(w, `{"data":{"id":"file-1","cid":"bafy","vectorized":%t}}`, tt.inline)
+				case r.Method == "POST" && r.URL.Path == "/vectorize/files/file-1":
+					vectorizeCalls++
+					fmt.Fprint(w, `{"data":{"status":true}}`)
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			})
+
+			resp, err := NewPrivateService(cfg).FileAndVectorize(openTestFile(t), &upload.FileOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.ID != "file-1" || !resp.Vectorized {
+				t.Fatalf("got %+v, want file-1 vectorized", resp)
+			}
+			if vectorizeCalls != tt.wantVectorize {
+				t.Fatalf("called vectorize %d times, want %d", vectorizeCalls, tt.wantVectorize)
+			}
+		})
+	}
+}
+
+func TestFileAndVectorizeFailureKeepsUpload(t *testing.T) {
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/files" {
+			fmt.Fprint(w, `{"data":{"id":"file-1","cid":"bafy"}}`)
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	resp, err := NewPrivateService(cfg).FileAndVectorize(openTestFile(t), nil)
+	if err == nil {
+		t.Fatal("expected an error when vectorizing fails")
+	}
+	if resp == nil || resp.ID != "file-1" || resp.Vectorized {
+		t.Fatalf("got %+v, want the unvectorized upload response", resp)
+	}
+}
+
+func openTestFile(t *testing.T) *os.File {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "doc.txt")
+	if err := os.WriteFile(path, []byte("some text"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { file.Close() })
+	return file
+}
+
+func TestVectorizeBatchPartialFailure(t *testing.T) {
+	var mu sync.Mutex
+	vectorized := map[string]bool{}
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimPrefix(r.URL.Path, "/vectorize/files/")
+		if r.Method != "POST" || strings.HasPrefix(id, "bad") {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"error":"unsupported file type"}`)
+			return
+		}
+		mu.Lock()
+		vectorized[id] = true
+		mu.Unlock()
+		fmt.Fprint(w, `{"data":{"status":true}}`)
+	})
+
+	ids := []string{"a", "bad-1", "b", "c"}
+	responses, err := NewPrivateService(cfg).VectorizeBatch(ids)
+	if err == nil || err.Error() != "1 of 4 vectorize calls failed" {
+		t.Fatalf("got error %v, want 1 of 4 vectorize calls failed", err)
+	}
+
+	for i, response := range responses {
+		if response.ID != ids[i] {
+			t.Fatalf("response %d is for %s, want %s", i, response.ID, ids[i])
+		}
+		if response.ID == "bad-1" {
+			if response.Status != "failed" || !strings.Contains(response.Error, "unsupported file type") {
+				t.Fatalf("%s: got %+v, want a failure", response.ID, response)
+			}
+			continue
+		}
+		if response.Status != "vectorized" || response.Error != "" || !vectorized[response.ID] {
+			t.Fatalf("%s: got %+v, want vectorized", response.ID, response)
+		}
+	}
+}
+
+func TestVectorizeBatchRequiresIDs(t *testing.T) {
+	if _, err := NewPrivateService(&types.Config{PinataJWT: "jwt"}).VectorizeBatch(nil); !errors.Is(err, ErrNoFileID) {
+		t.Fatalf("got %v, want ErrNoFileID", err)
+	}
+}
+
+func TestVectorizeStatus(t *testing.T) {
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/files/private/ready":
+			fmt.Fprint(w, `{"data":{"id":"ready","vectorized":true}}`)
+		case "/files/private/pending":
+			fmt.Fprint(w, `{"data":{"id":"pending","vectorized":false}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	service := NewPrivateService(cfg)
+
+	for id, want := range map[string]bool{"ready": true, "pending": false} {
+		vectorized, err := service.VectorizeStatus(id)
+		if err != nil {
+			t.Fatalf("%s: %v", id, err)
+		}
+		if vectorized != want {
+			t.Errorf("VectorizeStatus(%s) = %t, want %t", id, vectorized, want)
+		}
+	}
+
+	if _, err := service.VectorizeStatus("missing"); !types.IsNotFound(err) {
+		t.Fatalf("got %v, want a not found error", err)
+	}
+	if _, err := service.VectorizeStatus(""); !errors.Is(err, ErrNoFileID) {
+		t.Fatalf("got %v, want ErrNoFileID", err)
+	}
+}
+
+func TestVectorizeWithCache(t *testing.T) {
+	var mu sync.Mutex
+	vectorized := false
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/files/private/file-1":
+			fmt.Fprintf(w, `{"data":{"id":"file-1","vectorized":%t}}`, vectorized)
+		case r.URL.Path == "/vectorize/files/file-1":
+			vectorized = r.Method == "POST"
+			fmt.Fprint(w, `{"data":{"status":true}}`)
+		case r.URL.Path == "/vectorize/groups/group-1/query":
+			fmt.Fprint(w, `{"data":{"count":0,"matches":[]}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	cfg.CacheSize = 10
+	service := NewPrivateService(cfg)
+
+	// Cache the unvectorized file, then vectorize it behind the SDK's back
+	if file, err := service.Get("file-1"); err != nil || file.Vectorized {
+		t.Fatalf("got %+v, %v, want an unvectorized file", file, err)
+	}
+	mu.Lock()
+	vectorized = true
+	mu.Unlock()
+
+	if ok, err := service.VectorizeStatus("file-1"); err != nil || !ok {
+		t.Fatalf("VectorizeStatus = %t, %v, want true from a fresh read", ok, err)
+	}
+	if _, err := service.QueryByFile("group-1", "file-1", nil); err != nil {
+		t.Fatalf("QueryByFile: %v", err)
+	}
+
+	if _, err := service.DeleteVectors("file-1"); err != nil {
+		t.Fatal(err)
+	}
+	if file, _ := service.Get("file-1"); file.Vectorized {
+		t.Fatal("Get returned a stale vectorized file after DeleteVectors")
+	}
+	if _, err := service.Vectorize("file-1"); err != nil {
+		t.Fatal(err)
+	}
+	if file, _ := service.Get("file-1"); !file.Vectorized {
+		t.Fatal("Get returned a stale unvectorized file after Vectorize")
+	}
+}
@@ -0,0 +1,125 @@
+// Package gateway provides functionality for retrieving content through a Pinata gateway
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/PinataCloud/pinata-go-sdk/pinata/internal/request"
+	"github.com/PinataCloud/pinata-go-sdk/pinata/types"
+)
+
+// ErrNotFound is returned when the gateway has no content for a CID
+var ErrNotFound = errors.New("content not found on gateway")
+
+// Service provides gateway operations for Pinata
+type Service struct {
+	config  interface{}
+	Public  *PublicService
+	Private *PrivateService
+}
+
+// New creates a new gateway service with the provided configuration
+func New(config interface{}) *Service {
+	service := &Service{
+		config: config,
+	}
+
+	// Initialize public and private services
+	service.Public = NewPublicService(config)
+	service.Private = NewPrivateService(config)
+
+	return service
+}
+
+// Config returns the service configuration
+func (s *Service) Config() interface{} {
+	return s.config
+}
+
+// decodeJSON unmarshals the JSON content of cid from body into v
+func decodeJSON(cid string, body io.Reader, v interface{}) error {
+	if err := json.NewDecoder(body).Decode(v); err != nil {
+		return fmt.Errorf("content of %s is not valid JSON: %w", cid, err)
+	}
+	return nil
+}
+
+// getContent downloads cid under prefix ("ipfs" or "files") from the configured
+// gateway, falling back to each of cfg.FallbackGateways in order when a gateway
+// fails or does not have the content. The first OK response is returned and its
+// body must be closed by the caller. The gateway key is only sent to the
+// configured gateway, since it is not valid on any other.
+func getContent(ctx context.Context, cfg *types.Config, prefix string, cid string) (*http.Response, error) {
+	if cid == "" {
+		return nil, fmt.Errorf("CID is required")
+	}
+	if cfg.PinataGateway == "" {
+		return nil, types.ErrMissingGateway
+	}
+
+	gateways := append([]string{cfg.PinataGateway}, cfg.FallbackGateways...)
+	errs := make([]error, 0, len(gateways))
+
+	for i, gateway := range gateways {
+		resp, err := getFromGateway(ctx, cfg, gateway, i == 0, prefix, cid)
+		if err == nil {
+			return resp, nil
+		}
+
+		// Stop trying further gateways once the caller has given up
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		errs = append(errs, err)
+	}
+
+	if len(errs) == 1 {
+		return nil, errs[0]
+	}
+	for i, err := range errs {
+		errs[i] = fmt.Errorf("%s: %w", request.GatewayHost(gateways[i]), err)
+	}
+	return nil, fmt.Errorf("all %d gateways failed: %w", len(errs), errors.Join(errs...))
+}
+
+// getFromGateway downloads cid under prefix from one gateway
+func getFromGateway(ctx context.Context, cfg *types.Config, gateway string, primary bool, prefix string, cid string) (*http.Response, error) {
+	url := fmt.Sprintf("https://%s/%s/%s", request.GatewayHost(gateway), prefix, cid)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// The token and custom headers are meant for the configured gateway, so
+	// fallback gateways, which may be run by third parties, get neither
+	if primary {
+		request.SetGatewayToken(cfg, req)
+
+		for key, value := range cfg.CustomHeaders {
+			req.Header.Set(key, value)
+		}
+	}
+
+	resp, err := request.Do(cfg, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		defer resp.Body.Close()
+		return nil, fmt.Errorf("%w: %s: %w", ErrNotFound, cid, request.Error(resp))
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, request.Error(resp)
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,243 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/PinataCloud/pinata-go-sdk/pinata/types"
+)
+
+// newTestGateway starts a TLS server running handler and returns a config
+// using it as the dedicated gateway
+func newTestGateway(t *testing.T, handler http.HandlerFunc) *types.Config {
+	t.Helper()
+
+	srv := httptest.NewTLSServer(handler)
+	t.Cleanup(srv.Close)
+	return &types.Config{
+		PinataJWT:     "jwt",
+		PinataGateway: srv.Listener.Addr().String(),
+		HTTPClient:    srv.Client(),
+	}
+}
+
+func TestGetSendsGatewayToken(t *testing.T) {
+	var token string
+	cfg := newTestGateway(t, func(w http.ResponseWriter, r *http.Request) {
+		token = r.Header.Get("x-pinata-gateway-token")
+		fmt.Fprint(w, "hello")
+	})
+	cfg.PinataGatewayKey = "gateway-key"
+
+	data, _, err := NewPublicService(cfg).Get("bafy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "hello" {
+		t.Fatalf("got %q", data)
+	}
+	if token != "gateway-key" {
+		t.Fatalf("gateway token header %q, want %q", token, "gateway-key")
+	}
+}
+
+func TestGetWithoutGatewayKey(t *testing.T) {
+	var sent bool
+	cfg := newTestGateway(t, func(w http.ResponseWriter, r *http.Request) {
+		_, sent = r.Header["X-Pinata-Gateway-Token"]
+	})
+
+	if _, _, err := NewPublicService(cfg).Get("bafy"); err != nil {
+		t.Fatal(err)
+	}
+	if sent {
+		t.Fatal("gateway token header sent without a gateway key")
+	}
+}
+
+func TestURLAppendsGatewayToken(t *testing.T) {
+	cfg := &types.Config{PinataGateway: "example", PinataGatewayKey: "a key"}
+
+	link, err := url.Parse(NewPublicService(cfg).URL("bafy", "dir/a.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if link.Host != "example.mypinata.cloud" || !strings.HasSuffix(link.Path, "/ipfs/bafy/dir/a.txt") {
+		t.Fatalf("got %s", link)
+	}
+	if got := link.Query().Get("pinataGatewayToken"); got != "a key" {
+		t.Fatalf("pinataGatewayToken %q, want %q", got, "a key")
+	}
+}
+
+func TestImageURL(t *testing.T) {
+	service := NewPublicService(&types.Config{PinataGateway: "example"})
+	base := "https://example.mypinata.cloud/ipfs/bafy"
+
+	tests := []struct {
+		opts ImageOptions
+		want string
+	}{
+		{ImageOptions{}, base},
+		{ImageOptions{Width: 200}, base + "?img-width=200"},
+		{ImageOptions{Height: 100}, base + "?img-height=100"},
+		{ImageOptions{Format: "webp"}, base + "?img-format=webp"},
+		{ImageOptions{Quality: 1}, base + "?img-quality=1"},
+		{ImageOptions{Quality: 100}, base + "?img-quality=100"},
+		{ImageOptions{Fit: "cover"}, base + "?img-fit=cover"},
+		{ImageOptions{Width: 200, Height: 100, Fit: "contain"}, base + "?img-fit=contain&img-height=100&img-width=200"},
+		{ImageOptions{Width: 64, Height: 64, Format: "png", Quality: 80, Fit: "scale-down"}, base + "?img-fit=scale-down&img-format=png&img-height=64&img-quality=80&img-width=64"},
+	}
+
+	for _, tt := range tests {
+		got, err := service.ImageURL("bafy", tt.opts)
+		if err != nil {
+			t.Fatalf("%+v: %v", tt.opts, err)
+		}
+		if got != tt.want {
+			t.Errorf("%+v: got %s, want %s", tt.opts, got, tt.want)
+		}
+	}
+}
+
+func TestImageURLWithGatewayKey(t *testing.T) {
+	service := NewPublicService(&types.Config{PinataGateway: "example", PinataGatewayKey: "key"})
+
+	got, err := service.ImageURL("bafy", ImageOptions{Width: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "https://example.mypinata.cloud/ipfs/bafy?pinataGatewayToken=key&img-width=10"
+	if got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
+
+func TestImageURLRejectsInvalidOptions(t *testing.T) {
+	service := NewPublicService(&types.Config{PinataGateway: "example"})
+
+	for _, opts := range []ImageOptions{{Quality: -1}, {Quality: 101}, {Width: -1}, {Height: -5}} {
+		if _, err := service.ImageURL("bafy", opts); err == nil {
+			t.Errorf("%+v accepted", opts)
+		}
+	}
+}
+
+func TestGetJSONRoundTrip(t *testing.T) {
+	type metadata struct {
+		Name       string            `json:"name"`
+		Edition    int               `json:"edition"`
+		Attributes map[string]string `json:"attributes"`
+	}
+	stored := metadata{Name: "token", Edition: 3, Attributes: map[string]string{"color": "blue"}}
+
+	var paths []string
+	cfg := newTestGateway(t, func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		if strings.HasSuffix(r.URL.Path, "/bad") {
+			fmt.Fprint(w, "<html>not json</html>")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(stored)
+	})
+
+	for _, service := range []interface {
+		GetJSON(cid string, v interface{}) error
+	}{NewPublicService(cfg), NewPrivateService(cfg)} {
+		var got metadata
+		if err := service.GetJSON("bafy", &got); err != nil {
+			t.Fatal(err)
+		}
+		if fmt.Sprint(got) != fmt.Sprint(stored) {
+			t.Fatalf("got %+v, want %+v", got, stored)
+		}
+
+		err := service.GetJSON("bad", &got)
+		if err == nil || !strings.Contains(err.Error(), "content of bad is not valid JSON") {
+			t.Fatalf("invalid content: got %v", err)
+		}
+	}
+
+	if fmt.Sprint(paths) != "[/ipfs/bafy /ipfs/bad /files/bafy /files/bad]" {
+		t.Fatalf("fetched %v", paths)
+	}
+}
+
+func TestGetFallsBackToNextGateway(t *testing.T) {
+	var primaryCalls int
+	cfg := newTestGateway(t, func(w http.ResponseWriter, r *http.Request) {
+		primaryCalls++
+		w.WriteHeader(http.StatusBadGateway)
+	})
+	cfg.PinataGatewayKey = "gateway-key"
+	cfg.CustomHeaders = map[string]string{"X-Custom": "secret"}
+
+	var fallbackToken, fallbackCustom string
+	fallback := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fallbackToken = r.Header.Get("x-pinata-gateway-token")
+		fallbackCustom = r.Header.Get("X-Custom")
+		fmt.Fprint(w, "from fallback")
+	}))
+	defer fallback.Close()
+	cfg.FallbackGateways = []string{fallback.Listener.Addr().String()}
+
+	data, _, err := NewPublicService(cfg).Get("bafy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "from fallback" || primaryCalls == 0 {
+		t.Fatalf("got %q after %d primary calls", data, primaryCalls)
+	}
+	if fallbackToken != "" {
+		t.Fatalf("sent the gateway key %q to the fallback gateway", fallbackToken)
+	}
+	if fallbackCustom != "" {
+		t.Fatalf("sent the custom header %q to the fallback gateway", fallbackCustom)
+	}
+}
+
+func TestGetReportsEveryFailedGateway(t *testing.T) {
+	cfg := newTestGateway(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	})
+	missing := httptest.NewTLSServer(http.NotFoundHandler())
+	defer missing.Close()
+	cfg.FallbackGateways = []string{missing.Listener.Addr().String()}
+
+	_, _, err := NewPrivateService(cfg).Get("bafy")
+	if err == nil || !strings.Contains(err.Error(), "all 2 gateways failed") {
+		t.Fatalf("got %v", err)
+	}
+	if types.StatusCode(err) != http.StatusBadGateway || !errors.Is(err, ErrNotFound) {
+		t.Fatalf("error %v does not wrap both failures", err)
+	}
+}
+
+func TestGetStopsFallingBackWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cfg := newTestGateway(t, func(w http.ResponseWriter, r *http.Request) {
+		cancel()
+		w.WriteHeader(http.StatusBadGateway)
+	})
+	var fallbackCalls int
+	fallback := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fallbackCalls++
+	}))
+	defer fallback.Close()
+	cfg.FallbackGateways = []string{fallback.Listener.Addr().String()}
+
+	if _, _, err := NewPublicService(cfg).GetContext(ctx, "bafy"); err == nil {
+		t.Fatal("got no error")
+	}
+	if fallbackCalls != 0 {
+		t.Fatalf("tried the fallback gateway %d times after cancellation", fallbackCalls)
+	}
+}
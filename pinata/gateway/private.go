@@ -0,0 +1,83 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/PinataCloud/pinata-go-sdk/pinata/internal/request"
+	"github.com/PinataCloud/pinata-go-sdk/pinata/types"
+)
+
+// PrivateService provides gateway access to files on the private IPFS network
+type PrivateService struct {
+	config interface{}
+}
+
+// NewPrivateService creates a new PrivateService with the provided configuration
+func NewPrivateService(config interface{}) *PrivateService {
+	return &PrivateService{
+		config: config,
+	}
+}
+
+// URL returns the gateway URL of a CID, optionally followed by path segments for
+// files inside a directory CID. The gateway key is appended when configured.
+func (s *PrivateService) URL(cid string, paths ...string) string {
+	return request.GatewayURL(s.config.(*types.Config), "files", cid, paths...)
+}
+
+// Get downloads the content of a CID and returns it with its content type
+func (s *PrivateService) Get(cid string) ([]byte, string, error) {
+	return s.GetContext(context.Background(), cid)
+}
+
+// GetContext is like Get but carries ctx through to the underlying requests
+func (s *PrivateService) GetContext(ctx context.Context, cid string) ([]byte, string, error) {
+	body, contentType, err := s.GetStreamContext(ctx, cid)
+	if err != nil {
+		return nil, "", err
+	}
+	defer body.Close()
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	return data, contentType, nil
+}
+
+// GetJSON downloads the content of a CID and unmarshals it into v, which must be
+// a pointer, e.g. to read back metadata stored with Upload JSON
+func (s *PrivateService) GetJSON(cid string, v interface{}) error {
+	return s.GetJSONContext(context.Background(), cid, v)
+}
+
+// GetJSONContext is like GetJSON but carries ctx through to the underlying requests
+func (s *PrivateService) GetJSONContext(ctx context.Context, cid string, v interface{}) error {
+	body, _, err := s.GetStreamContext(ctx, cid)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	return decodeJSON(cid, body, v)
+}
+
+// GetStream opens the content of a CID for streaming. The caller must close the
+// returned reader. Config.FallbackGateways are tried in order if the configured
+// gateway fails.
+func (s *PrivateService) GetStream(cid string) (io.ReadCloser, string, error) {
+	return s.GetStreamContext(context.Background(), cid)
+}
+
+// GetStreamContext is like GetStream but carries ctx through to the underlying requests
+func (s *PrivateService) GetStreamContext(ctx context.Context, cid string) (io.ReadCloser, string, error) {
+	resp, err := getContent(ctx, s.config.(*types.Config), "files", cid)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return resp.Body, resp.Header.Get("Content-Type"), nil
+}
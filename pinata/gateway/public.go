@@ -0,0 +1,126 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/PinataCloud/pinata-go-sdk/pinata/internal/request"
+	"github.com/PinataCloud/pinata-go-sdk/pinata/types"
+)
+
+// PublicService provides gateway access to files on the public IPFS network
+type PublicService struct {
+	config interface{}
+}
+
+// NewPublicService creates a new PublicService with the provided configuration
+func NewPublicService(config interface{}) *PublicService {
+	return &PublicService{
+		config: config,
+	}
+}
+
+// URL returns the gateway URL of a CID, optionally followed by path segments for
+// files inside a directory CID. The gateway key is appended when configured.
+func (s *PublicService) URL(cid string, paths ...string) string {
+	return request.GatewayURL(s.config.(*types.Config), "ipfs", cid, paths...)
+}
+
+// ImageURL returns the gateway URL of an image CID with the transforms in opts
+// applied by the gateway, e.g. to serve a resized thumbnail
+func (s *PublicService) ImageURL(cid string, opts ImageOptions) (string, error) {
+	if opts.Quality != 0 && (opts.Quality < 1 || opts.Quality > 100) {
+		return "", fmt.Errorf("image quality must be between 1 and 100, got %d", opts.Quality)
+	}
+	if opts.Width < 0 || opts.Height < 0 {
+		return "", fmt.Errorf("image width and height must not be negative")
+	}
+
+	params := url.Values{}
+	if opts.Width > 0 {
+		params.Add("img-width", strconv.Itoa(opts.Width))
+	}
+	if opts.Height > 0 {
+		params.Add("img-height", strconv.Itoa(opts.Height))
+	}
+	if opts.Format != "" {
+		params.Add("img-format", opts.Format)
+	}
+	if opts.Quality > 0 {
+		params.Add("img-quality", strconv.Itoa(opts.Quality))
+	}
+	if opts.Fit != "" {
+		params.Add("img-fit", opts.Fit)
+	}
+
+	link := s.URL(cid)
+	if len(params) == 0 {
+		return link, nil
+	}
+
+	separator := "?"
+	if strings.Contains(link, "?") {
+		separator = "&"
+	}
+
+	return link + separator + params.Encode(), nil
+}
+
+// Get downloads the content of a CID and returns it with its content type
+func (s *PublicService) Get(cid string) ([]byte, string, error) {
+	return s.GetContext(context.Background(), cid)
+}
+
+// GetContext is like Get but carries ctx through to the underlying requests
+func (s *PublicService) GetContext(ctx context.Context, cid string) ([]byte, string, error) {
+	body, contentType, err := s.GetStreamContext(ctx, cid)
+	if err != nil {
+		return nil, "", err
+	}
+	defer body.Close()
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	return data, contentType, nil
+}
+
+// GetJSON downloads the content of a CID and unmarshals it into v, which must be
+// a pointer, e.g. to read back metadata stored with Upload JSON
+func (s *PublicService) GetJSON(cid string, v interface{}) error {
+	return s.GetJSONContext(context.Background(), cid, v)
+}
+
+// GetJSONContext is like GetJSON but carries ctx through to the underlying requests
+func (s *PublicService) GetJSONContext(ctx context.Context, cid string, v interface{}) error {
+	body, _, err := s.GetStreamContext(ctx, cid)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	return decodeJSON(cid, body, v)
+}
+
+// GetStream opens the content of a CID for streaming. The caller must close the
+// returned reader. Config.FallbackGateways are tried in order if the configured
+// gateway fails.
+func (s *PublicService) GetStream(cid string) (io.ReadCloser, string, error) {
+	return s.GetStreamContext(context.Background(), cid)
+}
+
+// GetStreamContext is like GetStream but carries ctx through to the underlying requests
+func (s *PublicService) GetStreamContext(ctx context.Context, cid string) (io.ReadCloser, string, error) {
+	resp, err := getContent(ctx, s.config.(*types.Config), "ipfs", cid)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return resp.Body, resp.Header.Get("Content-Type"), nil
+}
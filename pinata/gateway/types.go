@@ -0,0 +1,11 @@
+package gateway
+
+// ImageOptions represents the image transforms applied by the gateway. Zero
+// values are left out of the URL.
+type ImageOptions struct {
+	Width   int
+	Height  int
+	Format  string // e.g. "webp", "png", "jpeg"
+	Quality int    // 1-100
+	Fit     string // e.g. "contain", "cover", "scale-down"
+}
@@ -0,0 +1,33 @@
+// Package groups provides functionality for managing file groups on Pinata
+package groups
+
+import "errors"
+
+// ErrVisibilityChangeNotAllowed is returned, wrapping the API error, when the API
+// refuses to switch a group between public and private
+var ErrVisibilityChangeNotAllowed = errors.New("group visibility cannot be changed")
+
+// Service provides group-related operations for Pinata
+type Service struct {
+	config  interface{}
+	Public  *PublicService
+	Private *PrivateService
+}
+
+// New creates a new groups service with the provided configuration
+func New(config interface{}) *Service {
+	service := &Service{
+		config: config,
+	}
+
+	// Initialize public and private services
+	service.Public = NewPublicService(config)
+	service.Private = NewPrivateService(config)
+
+	return service
+}
+
+// Config returns the service configuration
+func (s *Service) Config() interface{} {
+	return s.config
+}
@@ -0,0 +1,61 @@
+package groups
+
+import (
+	"context"
+
+	"github.com/PinataCloud/pinata-go-sdk/pinata/internal/pager"
+	"github.com/PinataCloud/pinata-go-sdk/pinata/types"
+)
+
+// GroupIterator walks through the groups of a List query, fetching pages as needed
+//
+//	it := client.Groups.Public.ListIter(nil)
+//	for it.Next() {
+//		group := it.Group()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type GroupIterator struct {
+	pager *pager.Pager[types.Group]
+}
+
+func newGroupIterator(ctx context.Context, list func(context.Context, *ListOptions) (*types.GroupListResponse, error), opts *ListOptions) *GroupIterator {
+	var query ListOptions
+	if opts != nil {
+		query = *opts
+	}
+
+	fetch := func(ctx context.Context, token string) (*pager.Page[types.Group], error) {
+		query.PageToken = token
+		resp, err := list(ctx, &query)
+		if err != nil || resp == nil {
+			return nil, err
+		}
+		return &pager.Page[types.Group]{Items: resp.Groups, NextPageToken: resp.NextPageToken}, nil
+	}
+
+	return &GroupIterator{pager: pager.New(ctx, query.PageToken, false, fetch)}
+}
+
+// Next advances to the next group, fetching the next page when the current one is
+// exhausted. It returns false when there are no more groups or an error occurred.
+func (it *GroupIterator) Next() bool {
+	return it.pager.Next()
+}
+
+// Group returns the current group
+func (it *GroupIterator) Group() types.Group {
+	return it.pager.Item()
+}
+
+// Err returns the error that stopped the iteration, if any
+func (it *GroupIterator) Err() error {
+	return it.pager.Err()
+}
+
+// collectGroups drains an iterator into a slice
+func collectGroups(it *GroupIterator) ([]types.Group, error) {
+	return it.pager.Collect()
+}
@@ -0,0 +1,65 @@
+package groups
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+// groupPages serves three pages of groups keyed by page token
+func groupPages(w http.ResponseWriter, r *http.Request) {
+	pages := map[string]string{
+		"":   `{"data":{"groups":[{"id":"group-1"},{"id":"group-2"}],"next_page_token":"p2"}}`,
+		"p2": `{"data":{"groups":[{"id":"group-3"}],"next_page_token":"p3"}}`,
+		"p3": `{"data":{"groups":[{"id":"group-4"}],"next_page_token":""}}`,
+	}
+	fmt.Fprint(w, pages[r.URL.Query().Get("pageToken")])
+}
+
+func TestListAllFollowsPageTokens(t *testing.T) {
+	cfg, requests := newTestConfig(t, groupPages)
+	isPublic := true
+
+	groups, err := NewPublicService(cfg).ListAll(&ListOptions{Name: "photos", Limit: 2, IsPublic: &isPublic})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, group := range groups {
+		ids = append(ids, group.ID)
+	}
+	if fmt.Sprint(ids) != "[group-1 group-2 group-3 group-4]" {
+		t.Fatalf("got groups %v", ids)
+	}
+
+	var queries []string
+	for _, req := range *requests {
+		queries = append(queries, req.Query)
+	}
+	want := "[isPublic=true&limit=2&name=photos isPublic=true&limit=2&name=photos&pageToken=p2 isPublic=true&limit=2&name=photos&pageToken=p3]"
+	if fmt.Sprint(queries) != want {
+		t.Fatalf("sent queries %v, want %s", queries, want)
+	}
+}
+
+func TestListIterStopsOnError(t *testing.T) {
+	cfg, requests := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("pageToken") == "p2" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		groupPages(w, r)
+	})
+
+	it := NewPrivateService(cfg).ListIter(nil)
+	count := 0
+	for it.Next() {
+		count++
+	}
+	if count != 2 || it.Err() == nil {
+		t.Fatalf("iterated %d groups with error %v, want 2 and an error", count, it.Err())
+	}
+	if len(*requests) != 2 || (*requests)[0].Path != "/groups/private" {
+		t.Fatalf("got requests %+v", *requests)
+	}
+}
@@ -0,0 +1,282 @@
+package groups
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/PinataCloud/pinata-go-sdk/pinata/internal/request"
+	"github.com/PinataCloud/pinata-go-sdk/pinata/types"
+)
+
+// PublicService provides operations for managing groups on the public IPFS network
+type PublicService struct {
+	config interface{}
+}
+
+// NewPublicService creates a new PublicService with the provided configuration
+func NewPublicService(config interface{}) *PublicService {
+	return &PublicService{
+		config: config,
+	}
+}
+
+// Create creates a new group on the public IPFS network
+func (s *PublicService) Create(name string, isPublic bool) (*types.Group, error) {
+	return s.CreateContext(context.Background(), name, isPublic)
+}
+
+// CreateContext is like Create but carries ctx through to the underlying requests
+func (s *PublicService) CreateContext(ctx context.Context, name string, isPublic bool) (*types.Group, error) {
+	if name == "" {
+		return nil, fmt.Errorf("group name is required")
+	}
+
+	cfg := s.config.(*types.Config)
+	url := fmt.Sprintf("%s/groups/public", cfg.APIUrl)
+
+	payload := struct {
+		Name     string `json:"name"`
+		IsPublic bool   `json:"is_public"`
+	}{
+		Name:     name,
+		IsPublic: isPublic,
+	}
+
+	var response *types.Group
+	if err := request.DoRequest(ctx, cfg, "POST", url, payload, &response); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// Get retrieves a group by ID from the public IPFS network
+func (s *PublicService) Get(id string) (*types.Group, error) {
+	return s.GetContext(context.Background(), id)
+}
+
+// GetContext is like Get but carries ctx through to the underlying requests
+func (s *PublicService) GetContext(ctx context.Context, id string) (*types.Group, error) {
+	if id == "" {
+		return nil, fmt.Errorf("group ID is required")
+	}
+
+	cfg := s.config.(*types.Config)
+	url := fmt.Sprintf("%s/groups/public/%s", cfg.APIUrl, id)
+
+	var response *types.Group
+	if err := request.DoRequest(ctx, cfg, "GET", url, nil, &response); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// List retrieves a list of groups from the public IPFS network
+func (s *PublicService) List(opts *ListOptions) (*types.GroupListResponse, error) {
+	return s.ListContext(context.Background(), opts)
+}
+
+// ListContext is like List but carries ctx through to the underlying requests
+func (s *PublicService) ListContext(ctx context.Context, opts *ListOptions) (*types.GroupListResponse, error) {
+	cfg := s.config.(*types.Config)
+	baseURL := fmt.Sprintf("%s/groups/public", cfg.APIUrl)
+
+	// Build query parameters
+	params := url.Values{}
+
+	if opts != nil {
+		if opts.Name != "" {
+			params.Add("name", opts.Name)
+		}
+		if opts.Limit > 0 {
+			params.Add("limit", strconv.Itoa(opts.Limit))
+		}
+		if opts.PageToken != "" {
+			params.Add("pageToken", opts.PageToken)
+		}
+		if opts.IsPublic != nil {
+			params.Add("isPublic", strconv.FormatBool(*opts.IsPublic))
+		}
+	}
+
+	// Append query parameters if any
+	requestURL := baseURL
+	if len(params) > 0 {
+		requestURL = fmt.Sprintf("%s?%s", baseURL, params.Encode())
+	}
+
+	var response *types.GroupListResponse
+	if err := request.DoRequest(ctx, cfg, "GET", requestURL, nil, &response); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// ListAll retrieves every group matching opts from the public IPFS network,
+// following NextPageToken until all pages have been read. opts.Limit sets the page size.
+func (s *PublicService) ListAll(opts *ListOptions) ([]types.Group, error) {
+	return s.ListAllContext(context.Background(), opts)
+}
+
+// ListAllContext is like ListAll but carries ctx through to the underlying requests
+func (s *PublicService) ListAllContext(ctx context.Context, opts *ListOptions) ([]types.Group, error) {
+	return collectGroups(s.ListIterContext(ctx, opts))
+}
+
+// ListIter returns an iterator over every group matching opts on the public IPFS
+// network. Pages are fetched lazily.
+func (s *PublicService) ListIter(opts *ListOptions) *GroupIterator {
+	return s.ListIterContext(context.Background(), opts)
+}
+
+// ListIterContext is like ListIter but carries ctx through to the underlying requests
+func (s *PublicService) ListIterContext(ctx context.Context, opts *ListOptions) *GroupIterator {
+	return newGroupIterator(ctx, s.ListContext, opts)
+}
+
+// Update renames a group on the public IPFS network
+func (s *PublicService) Update(id string, name string) (*types.Group, error) {
+	return s.UpdateContext(context.Background(), id, name)
+}
+
+// UpdateContext is like Update but carries ctx through to the underlying requests
+func (s *PublicService) UpdateContext(ctx context.Context, id string, name string) (*types.Group, error) {
+	if id == "" || name == "" {
+		return nil, fmt.Errorf("group ID and name are required")
+	}
+
+	return s.UpdateWithOptionsContext(ctx, id, &UpdateOptions{Name: name})
+}
+
+// UpdateWithOptions changes a group's name and/or visibility on the public IPFS
+// network. If the API refuses to change IsPublic the error wraps
+// ErrVisibilityChangeNotAllowed.
+func (s *PublicService) UpdateWithOptions(id string, opts *UpdateOptions) (*types.Group, error) {
+	return s.UpdateWithOptionsContext(context.Background(), id, opts)
+}
+
+// UpdateWithOptionsContext is like UpdateWithOptions but carries ctx through to the underlying requests
+func (s *PublicService) UpdateWithOptionsContext(ctx context.Context, id string, opts *UpdateOptions) (*types.Group, error) {
+	if id == "" {
+		return nil, fmt.Errorf("group ID is required")
+	}
+	if opts == nil || (opts.Name == "" && opts.IsPublic == nil) {
+		return nil, fmt.Errorf("name or IsPublic is required")
+	}
+
+	cfg := s.config.(*types.Config)
+	url := fmt.Sprintf("%s/groups/public/%s", cfg.APIUrl, id)
+
+	payload := struct {
+		Name     string `json:"name,omitempty"`
+		IsPublic *bool  `json:"is_public,omitempty"`
+	}{
+		Name:     opts.Name,
+		IsPublic: opts.IsPublic,
+	}
+
+	var response *types.Group
+	if err := request.DoRequest(ctx, cfg, "PUT", url, payload, &response); err != nil {
+		if opts.IsPublic != nil {
+			switch types.StatusCode(err) {
+			case http.StatusBadRequest, http.StatusForbidden, http.StatusConflict:
+				return nil, fmt.Errorf("%w: %w", ErrVisibilityChangeNotAllowed, err)
+			}
+		}
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// Delete removes a group from the public IPFS network. Files in the group are not deleted.
+func (s *PublicService) Delete(id string) error {
+	return s.DeleteContext(context.Background(), id)
+}
+
+// DeleteContext is like Delete but carries ctx through to the underlying requests
+func (s *PublicService) DeleteContext(ctx context.Context, id string) error {
+	if id == "" {
+		return fmt.Errorf("group ID is required")
+	}
+
+	cfg := s.config.(*types.Config)
+	url := fmt.Sprintf("%s/groups/public/%s", cfg.APIUrl, id)
+
+	return request.DoRequest(ctx, cfg, "DELETE", url, nil, nil)
+}
+
+// AddFiles adds existing files to a group on the public IPFS network. Every ID is
+// attempted; the returned slice reports the outcome for each one and the error is
+// non-nil if any of them failed.
+func (s *PublicService) AddFiles(groupID string, fileIDs []string) ([]types.GroupFileResponse, error) {
+	return s.AddFilesContext(context.Background(), groupID, fileIDs)
+}
+
+// AddFilesContext is like AddFiles but carries ctx through to the underlying requests
+func (s *PublicService) AddFilesContext(ctx context.Context, groupID string, fileIDs []string) ([]types.GroupFileResponse, error) {
+	return s.updateMembership(ctx, "PUT", groupID, fileIDs, "added")
+}
+
+// RemoveFiles removes files from a group on the public IPFS network. The files
+// themselves are not deleted. Results are reported the same way as AddFiles.
+func (s *PublicService) RemoveFiles(groupID string, fileIDs []string) ([]types.GroupFileResponse, error) {
+	return s.RemoveFilesContext(context.Background(), groupID, fileIDs)
+}
+
+// RemoveFilesContext is like RemoveFiles but carries ctx through to the underlying requests
+func (s *PublicService) RemoveFilesContext(ctx context.Context, groupID string, fileIDs []string) ([]types.GroupFileResponse, error) {
+	return s.updateMembership(ctx, "DELETE", groupID, fileIDs, "removed")
+}
+
+// updateMembership sends one request per file ID to add it to or remove it from a group
+func (s *PublicService) updateMembership(ctx context.Context, method string, groupID string, fileIDs []string, status string) ([]types.GroupFileResponse, error) {
+	if groupID == "" {
+		return nil, fmt.Errorf("group ID is required")
+	}
+	if len(fileIDs) == 0 {
+		return nil, fmt.Errorf("at least one file ID is required")
+	}
+
+	cfg := s.config.(*types.Config)
+
+	responses := make([]types.GroupFileResponse, 0, len(fileIDs))
+	failed := 0
+
+	// Process each ID individually
+	for _, id := range fileIDs {
+		if err := ctx.Err(); err != nil {
+			return responses, err
+		}
+
+		url := fmt.Sprintf("%s/groups/public/%s/ids/%s", cfg.APIUrl, groupID, id)
+
+		err := request.DoRequest(ctx, cfg, method, url, nil, nil)
+
+		if err != nil {
+			failed++
+			responses = append(responses, types.GroupFileResponse{
+				ID:     id,
+				Status: "failed",
+				Error:  err.Error(),
+			})
+			continue
+		}
+
+		responses = append(responses, types.GroupFileResponse{
+			ID:     id,
+			Status: status,
+		})
+	}
+
+	if failed > 0 {
+		return responses, fmt.Errorf("%d of %d group updates failed", failed, len(fileIDs))
+	}
+
+	return responses, nil
+}
@@ -0,0 +1,223 @@
+package groups
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/PinataCloud/pinata-go-sdk/pinata/types"
+)
+
+// recordedRequest is one request received by a test server
+type recordedRequest struct {
+	Method string
+	Path   string
+	Query  string
+	Body   string
+}
+
+// newTestConfig starts a server that records each request and answers with
+// handler, returning a config pointing the API at it
+func newTestConfig(t *testing.T, handler http.HandlerFunc) (*types.Config, *[]recordedRequest) {
+	t.Helper()
+
+	var requests []recordedRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, recordedRequest{Method: r.Method, Path: r.URL.Path, Query: r.URL.RawQuery, Body: string(body)})
+		handler(w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	return &types.Config{PinataJWT: "jwt", APIUrl: srv.URL}, &requests
+}
+
+const sampleGroup = `{"data":{"id":"group-1","name":"photos","is_public":true,"created_at":"2024-05-01T10:00:00Z"}}`
+
+func TestCreate(t *testing.T) {
+	cfg, requests := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, sampleGroup)
+	})
+
+	group, err := NewPublicService(cfg).Create("photos", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if group.ID != "group-1" || group.Name != "photos" || !group.IsPublic || group.CreatedAt == "" {
+		t.Fatalf("got %+v", group)
+	}
+
+	req := (*requests)[0]
+	if req.Method != "POST" || req.Path != "/groups/public" {
+		t.Fatalf("sent %s %s", req.Method, req.Path)
+	}
+	var payload map[string]interface{}
+	if err := json.Unmarshal([]byte(req.Body), &payload); err != nil {
+		t.Fatal(err)
+	}
+	if payload["name"] != "photos" || payload["is_public"] != true {
+		t.Fatalf("sent payload %v", payload)
+	}
+}
+
+func TestGetUpdateDelete(t *testing.T) {
+	cfg, requests := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "DELETE" {
+			return
+		}
+		fmt.Fprint(w, sampleGroup)
+	})
+	service := NewPrivateService(cfg)
+
+	if group, err := service.Get("group-1"); err != nil || group.Name != "photos" {
+		t.Fatalf("Get: got %+v, %v", group, err)
+	}
+	if group, err := service.Update("group-1", "photos"); err != nil || group.ID != "group-1" {
+		t.Fatalf("Update: got %+v, %v", group, err)
+	}
+	if err := service.Delete("group-1"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	want := []string{"GET /groups/private/group-1", "PUT /groups/private/group-1", "DELETE /groups/private/group-1"}
+	for i, req := range *requests {
+		if got := req.Method + " " + req.Path; got != want[i] {
+			t.Fatalf("request %d: got %s, want %s", i, got, want[i])
+		}
+	}
+}
+
+func TestList(t *testing.T) {
+	cfg, requests := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data":{"groups":[{"id":"group-1","name":"photos"},{"id":"group-2","name":"photos-old"}],"next_page_token":"next"}}`)
+	})
+
+	resp, err := NewPublicService(cfg).List(&ListOptions{Name: "photos", Limit: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Groups) != 2 || resp.Groups[1].ID != "group-2" || resp.NextPageToken != "next" {
+		t.Fatalf("got %+v", resp)
+	}
+	if query := (*requests)[0].Query; query != "limit=2&name=photos" {
+		t.Fatalf("sent query %q", query)
+	}
+}
+
+func TestRequiredInputs(t *testing.T) {
+	service := NewPublicService(&types.Config{PinataJWT: "jwt"})
+
+	if _, err := service.Create("", false); err == nil {
+		t.Error("Create without a name succeeded")
+	}
+	if _, err := service.Get(""); err == nil {
+		t.Error("Get without an ID succeeded")
+	}
+	if err := service.Delete(""); err == nil {
+		t.Error("Delete without an ID succeeded")
+	}
+}
+
+func TestAddFilesPartialFailure(t *testing.T) {
+	cfg, requests := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/groups/public/group-1/ids/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+	})
+
+	responses, err := NewPublicService(cfg).AddFiles("group-1", []string{"file-1", "missing", "file-2"})
+	if err == nil || err.Error() != "1 of 3 group updates failed" {
+		t.Fatalf("got error %v", err)
+	}
+
+	wantStatus := []string{"added", "failed", "added"}
+	for i, response := range responses {
+		if response.Status != wantStatus[i] {
+			t.Fatalf("response %d: got status %q, want %q", i, response.Status, wantStatus[i])
+		}
+	}
+	if responses[1].Error == "" {
+		t.Fatal("failed response has no error")
+	}
+	for _, req := range *requests {
+		if req.Method != "PUT" {
+			t.Fatalf("AddFiles sent %s", req.Method)
+		}
+	}
+}
+
+func TestRemoveFiles(t *testing.T) {
+	cfg, requests := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {})
+
+	responses, err := NewPrivateService(cfg).RemoveFiles("group-1", []string{"file-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(responses) != 1 || responses[0].Status != "removed" {
+		t.Fatalf("got %+v", responses)
+	}
+	if req := (*requests)[0]; req.Method != "DELETE" || req.Path != "/groups/private/group-1/ids/file-1" {
+		t.Fatalf("sent %s %s", req.Method, req.Path)
+	}
+}
+
+func TestListOptionsNextPage(t *testing.T) {
+	opts := ListOptions{Name: "photos"}
+
+	if opts.NextPage(&types.GroupListResponse{}) || opts.PageToken != "" {
+		t.Fatalf("empty token: got more pages or token %q", opts.PageToken)
+	}
+	if !opts.NextPage(&types.GroupListResponse{NextPageToken: "g2"}) || opts.PageToken != "g2" || opts.Name != "photos" {
+		t.Fatalf("got %+v, want the token copied and the rest kept", opts)
+	}
+}
+
+func TestUpdateVisibility(t *testing.T) {
+	cfg, requests := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, sampleGroup)
+	})
+	isPublic := false
+
+	if _, err := NewPublicService(cfg).UpdateWithOptions("group-1", &UpdateOptions{IsPublic: &isPublic}); err != nil {
+		t.Fatal(err)
+	}
+	if body := (*requests)[0].Body; body != `{"is_public":false}` {
+		t.Fatalf("sent %s", body)
+	}
+
+	isPublic = true
+	if _, err := NewPrivateService(cfg).UpdateWithOptions("group-1", &UpdateOptions{Name: "shared", IsPublic: &isPublic}); err != nil {
+		t.Fatal(err)
+	}
+	if req := (*requests)[1]; req.Path != "/groups/private/group-1" || req.Body != `{"name":"shared","is_public":true}` {
+		t.Fatalf("sent %s %s", req.Path, req.Body)
+	}
+
+	if _, err := NewPublicService(cfg).UpdateWithOptions("group-1", &UpdateOptions{}); err == nil {
+		t.Fatal("empty update: got no error")
+	}
+}
+
+func TestUpdateVisibilityNotAllowed(t *testing.T) {
+	cfg, _ := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"error":"cannot change network"}`)
+	})
+	isPublic := true
+
+	_, err := NewPrivateService(cfg).UpdateWithOptions("group-1", &UpdateOptions{IsPublic: &isPublic})
+	if !errors.Is(err, ErrVisibilityChangeNotAllowed) || types.StatusCode(err) != http.StatusBadRequest {
+		t.Fatalf("got %v, want ErrVisibilityChangeNotAllowed wrapping the 400", err)
+	}
+
+	// A rename that fails is not a visibility problem
+	_, err = NewPrivateService(cfg).UpdateWithOptions("group-1", &UpdateOptions{Name: "other"})
+	if err == nil || errors.Is(err, ErrVisibilityChangeNotAllowed) {
+		t.Fatalf("rename: got %v", err)
+	}
+}
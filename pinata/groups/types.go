@@ -0,0 +1,29 @@
+package groups
+
+import "github.com/PinataCloud/pinata-go-sdk/pinata/types"
+
+// ListOptions represents options for the List method
+type ListOptions struct {
+	Name      string
+	Limit     int
+	PageToken string
+	// IsPublic, when set, only returns groups whose visibility matches it
+	IsPublic *bool
+}
+
+// UpdateOptions represents the fields changed by UpdateWithOptions. Unset fields
+// are left as they are.
+type UpdateOptions struct {
+	Name     string
+	IsPublic *bool
+}
+
+// NextPage copies resp.NextPageToken into PageToken and reports whether there
+// is another page to fetch
+func (o *ListOptions) NextPage(resp *types.GroupListResponse) bool {
+	if !resp.HasMore() {
+		return false
+	}
+	o.PageToken = resp.NextPageToken
+	return true
+}
@@ -0,0 +1,92 @@
+// Package batch runs independent operations with bounded concurrency
+package batch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// DefaultConcurrency is the number of operations run at once when no limit is given
+const DefaultConcurrency = 8
+
+// ErrNotAttempted is recorded by RunAll for calls skipped because ctx was done
+var ErrNotAttempted = errors.New("not attempted")
+
+// Run calls fn for every index in [0, n) using at most concurrency goroutines
+// and waits for all calls to return. Indexes that have not started when ctx is
+// done are skipped; fn is expected to record its own results.
+func Run(ctx context.Context, n int, concurrency int, fn func(ctx context.Context, i int)) {
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+	if concurrency > n {
+		concurrency = n
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				fn(ctx, i)
+			}
+		}()
+	}
+
+feed:
+	for i := 0; i < n; i++ {
+		select {
+		case <-ctx.Done():
+			break feed
+		case indexes <- i:
+		}
+	}
+
+	close(indexes)
+	wg.Wait()
+}
+
+// RunAll calls fn for every index in [0, n) like Run and returns the error of
+// each call, or ErrNotAttempted for those skipped. The returned error is ctx's
+// error when ctx is done, and otherwise counts the failed calls, as in
+// "2 of 5 deletes failed" for what "deletes".
+func RunAll(ctx context.Context, n int, concurrency int, what string, fn func(ctx context.Context, i int) error) ([]error, error) {
+	errs := make([]error, n)
+	for i := range errs {
+		errs[i] = ErrNotAttempted
+	}
+
+	Run(ctx, n, concurrency, func(ctx context.Context, i int) {
+		errs[i] = fn(ctx, i)
+	})
+
+	if err := ctx.Err(); err != nil {
+		return errs, err
+	}
+
+	failed := 0
+	for _, err := range errs {
+		if err != nil {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return errs, fmt.Errorf("%d of %d %s failed", failed, n, what)
+	}
+
+	return errs, nil
+}
+
+// Outcome returns the Status and Error fields of a batch response entry: ok and
+// an empty message when err is nil, and "failed" with err's message otherwise
+func Outcome(err error, ok string) (status string, message string) {
+	if err != nil {
+		return "failed", err.Error()
+	}
+	return ok, ""
+}
@@ -0,0 +1,81 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+)
+
+func TestRunCallsEveryIndex(t *testing.T) {
+	seen := make([]int32, 20)
+	var running, peak int32
+
+	Run(context.Background(), len(seen), 3, func(ctx context.Context, i int) {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		atomic.AddInt32(&seen[i], 1)
+		atomic.AddInt32(&running, -1)
+	})
+
+	for i, n := range seen {
+		if n != 1 {
+			t.Fatalf("index %d called %d times", i, n)
+		}
+	}
+	if peak > 3 {
+		t.Fatalf("ran %d calls at once, want at most 3", peak)
+	}
+}
+
+func TestRunAllCountsFailures(t *testing.T) {
+	boom := errors.New("boom")
+
+	errs, err := RunAll(context.Background(), 5, 2, "deletes", func(ctx context.Context, i int) error {
+		if i%2 == 0 {
+			return boom
+		}
+		return nil
+	})
+
+	if err == nil || err.Error() != "3 of 5 deletes failed" {
+		t.Fatalf("got error %v, want 3 of 5 deletes failed", err)
+	}
+	for i, e := range errs {
+		if want := i%2 == 0; (e != nil) != want {
+			t.Fatalf("index %d: error %v", i, e)
+		}
+	}
+}
+
+func TestRunAllCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	errs, err := RunAll(ctx, 10, 1, "updates", func(ctx context.Context, i int) error {
+		if i == 2 {
+			cancel()
+		}
+		return nil
+	})
+
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("got %v, want context.Canceled", err)
+	}
+	if !errors.Is(errs[len(errs)-1], ErrNotAttempted) {
+		t.Fatalf("last index: got %v, want ErrNotAttempted", errs[len(errs)-1])
+	}
+}
+
+func TestOutcome(t *testing.T) {
+	if status, message := Outcome(nil, "deleted"); status != "deleted" || message != "" {
+		t.Fatalf("success: got %q %q", status, message)
+	}
+	if status, message := Outcome(ErrNotAttempted, "deleted"); status != "failed" || message != "not attempted" {
+		t.Fatalf("failure: got %q %q", status, message)
+	}
+}
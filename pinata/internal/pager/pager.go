@@ -0,0 +1,92 @@
+// Package pager walks through paged API listings one item at a time
+package pager
+
+import "context"
+
+// Page is one page of a listing and the token of the page after it
+type Page[T any] struct {
+	Items         []T
+	NextPageToken string
+}
+
+// Pager walks through the items of a listing, fetching pages as needed
+type Pager[T any] struct {
+	ctx       context.Context
+	fetch     func(ctx context.Context, token string) (*Page[T], error)
+	token     string
+	keepEmpty bool
+	page      []T
+	index     int
+	item      T
+	done      bool
+	err       error
+}
+
+// New returns a Pager that starts at the page with token and fetches each page
+// with fetch. A nil page ends the listing, and so does an empty one unless
+// keepEmpty is set, for listings filtered in a way that can empty a page that
+// is not the last.
+func New[T any](ctx context.Context, token string, keepEmpty bool, fetch func(ctx context.Context, token string) (*Page[T], error)) *Pager[T] {
+	return &Pager[T]{
+		ctx:       ctx,
+		fetch:     fetch,
+		token:     token,
+		keepEmpty: keepEmpty,
+	}
+}
+
+// Next advances to the next item, fetching the next page when the current one is
+// exhausted. It returns false when there are no more items or an error occurred.
+func (p *Pager[T]) Next() bool {
+	for p.index >= len(p.page) {
+		if p.done || p.err != nil {
+			return false
+		}
+
+		page, err := p.fetch(p.ctx, p.token)
+		if err != nil {
+			p.err = err
+			return false
+		}
+		if page == nil {
+			p.done = true
+			return false
+		}
+
+		p.page = page.Items
+		p.index = 0
+
+		// Stop on an empty page or a token that would fetch the same page again
+		empty := len(page.Items) == 0 && !p.keepEmpty
+		if page.NextPageToken == "" || page.NextPageToken == p.token || empty {
+			p.done = true
+		}
+		p.token = page.NextPageToken
+	}
+
+	p.item = p.page[p.index]
+	p.index++
+	return true
+}
+
+// Item returns the current item
+func (p *Pager[T]) Item() T {
+	return p.item
+}
+
+// Err returns the error that stopped the iteration, if any
+func (p *Pager[T]) Err() error {
+	return p.err
+}
+
+// Collect drains the remaining items into a slice
+func (p *Pager[T]) Collect() ([]T, error) {
+	var items []T
+	for p.Next() {
+		items = append(items, p.Item())
+	}
+	if err := p.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,90 @@
+package pager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// pages serves the given pages in order, each token being the index of its page
+func pages(t *testing.T, contents ...[]int) (func(context.Context, string) (*Page[int], error), *[]string) {
+	t.Helper()
+
+	var tokens []string
+	return func(ctx context.Context, token string) (*Page[int], error) {
+		tokens = append(tokens, token)
+		i := 0
+		if token != "" {
+			fmt.Sscan(token, &i)
+		}
+		if i >= len(contents) {
+			t.Fatalf("fetched page %d of %d", i, len(contents))
+		}
+		page := &Page[int]{Items: contents[i]}
+		if i+1 < len(contents) {
+			page.NextPageToken = fmt.Sprint(i + 1)
+		}
+		return page, nil
+	}, &tokens
+}
+
+func TestPagerWalksEveryPage(t *testing.T) {
+	fetch, tokens := pages(t, []int{1, 2}, []int{3}, []int{4, 5})
+
+	items, err := New(context.Background(), "", false, fetch).Collect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(items) != "[1 2 3 4 5]" || fmt.Sprint(*tokens) != "[ 1 2]" {
+		t.Fatalf("got %v after fetching %q", items, *tokens)
+	}
+}
+
+func TestPagerEmptyPage(t *testing.T) {
+	fetch, _ := pages(t, []int{1}, []int{}, []int{2})
+	if items, _ := New(context.Background(), "", false, fetch).Collect(); fmt.Sprint(items) != "[1]" {
+		t.Fatalf("got %v, want the listing to end at the empty page", items)
+	}
+
+	fetch, _ = pages(t, []int{1}, []int{}, []int{2})
+	if items, _ := New(context.Background(), "", true, fetch).Collect(); fmt.Sprint(items) != "[1 2]" {
+		t.Fatalf("got %v, want the empty page skipped", items)
+	}
+}
+
+func TestPagerStopsOnRepeatedToken(t *testing.T) {
+	calls := 0
+	fetch := func(ctx context.Context, token string) (*Page[int], error) {
+		calls++
+		return &Page[int]{Items: []int{calls}, NextPageToken: "same"}, nil
+	}
+
+	items, err := New(context.Background(), "", false, fetch).Collect()
+	if err != nil || fmt.Sprint(items) != "[1 2]" {
+		t.Fatalf("got %v, %v, want two pages", items, err)
+	}
+}
+
+func TestPagerError(t *testing.T) {
+	boom := errors.New("boom")
+	calls := 0
+	fetch := func(ctx context.Context, token string) (*Page[int], error) {
+		calls++
+		if token != "" {
+			return nil, boom
+		}
+		return &Page[int]{Items: []int{1}, NextPageToken: "next"}, nil
+	}
+
+	p := New(context.Background(), "", false, fetch)
+	if !p.Next() || p.Item() != 1 {
+		t.Fatal("first item missing")
+	}
+	if p.Next() || !errors.Is(p.Err(), boom) {
+		t.Fatalf("got %v, want the page error", p.Err())
+	}
+	if p.Next() || calls != 2 {
+		t.Fatalf("fetched %d pages, want no retry after an error", calls)
+	}
+}
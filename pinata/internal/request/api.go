@@ -0,0 +1,107 @@
+package request
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/PinataCloud/pinata-go-sdk/pinata/types"
+)
+
+// New creates an API request carrying the JWT, a JSON content type and the custom
+// headers from cfg. A non-nil body is marshaled to JSON and can be resent on retry.
+func New(ctx context.Context, cfg *types.Config, method string, url string, body interface{}) (*http.Request, error) {
+	var reader io.Reader
+	if body != nil {
+		jsonData, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal request body: %w", err)
+		}
+		reader = bytes.NewReader(jsonData)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	SetHeaders(cfg, req)
+
+	return req, nil
+}
+
+// SetHeaders sets the Authorization header and the custom headers from cfg on req.
+// Custom headers are set last so they can override the others.
+func SetHeaders(cfg *types.Config, req *http.Request) {
+	req.Header.Set("Authorization", "Bearer "+cfg.PinataJWT)
+
+	// Add custom headers if any
+	for key, value := range cfg.CustomHeaders {
+		req.Header.Set(key, value)
+	}
+}
+
+// IdempotencyKeyHeader is the header carrying an upload's idempotency key
+const IdempotencyKeyHeader = "x-idempotency-key"
+
+// SetIdempotencyKey sets key as the idempotency key of req, unless it is empty.
+// The header goes out with every attempt, so the server can drop an upload it
+// already received.
+func SetIdempotencyKey(req *http.Request, key string) {
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+}
+
+// DoRequest sends an API request built by New and decodes the data field of the
+// response into out, which may be nil when the response carries nothing of use.
+// A non-OK status is returned as the error from Error.
+func DoRequest(ctx context.Context, cfg *types.Config, method string, url string, body interface{}, out interface{}) error {
+	req, err := New(ctx, cfg, method, url, body)
+	if err != nil {
+		return err
+	}
+
+	resp, err := Do(cfg, req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+
+	return Decode(resp, out)
+}
+
+// Decode checks that resp has an OK status and decodes the data field of its JSON
+// body into out. A nil out discards the body. The body is closed.
+func Decode(resp *http.Response, out interface{}) error {
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Error(resp)
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	var response struct {
+		Data json.RawMessage `json:"data"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if len(response.Data) == 0 {
+		return nil
+	}
+
+	if err := json.Unmarshal(response.Data, out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return nil
+}
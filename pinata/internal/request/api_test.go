@@ -0,0 +1,94 @@
+package request
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/PinataCloud/pinata-go-sdk/pinata/types"
+)
+
+func TestDoRequestSuccess(t *testing.T) {
+	var header http.Header
+	var body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+		content, _ := io.ReadAll(r.Body)
+		body = string(content)
+		w.Write([]byte(`{"data":{"id":"file-1","name":"a.txt"}}`))
+	}))
+	defer srv.Close()
+
+	cfg := &types.Config{PinataJWT: "jwt", CustomHeaders: map[string]string{"X-Source": "test"}}
+	var out struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+	if err := DoRequest(context.Background(), cfg, "PUT", srv.URL, map[string]string{"name": "a.txt"}, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.ID != "file-1" || out.Name != "a.txt" {
+		t.Fatalf("decoded %+v", out)
+	}
+	if body != `{"name":"a.txt"}` {
+		t.Fatalf("sent body %s", body)
+	}
+	if header.Get("Authorization") != "Bearer jwt" || header.Get("Content-Type") != "application/json" || header.Get("X-Source") != "test" {
+		t.Fatalf("sent headers %v", header)
+	}
+}
+
+func TestDoRequestAPIError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error":{"message":"file not found","code":"NOT_FOUND"}}`))
+	}))
+	defer srv.Close()
+
+	var out struct{}
+	err := DoRequest(context.Background(), &types.Config{PinataJWT: "jwt"}, "GET", srv.URL, nil, &out)
+	var apiErr *types.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound || !types.IsNotFound(err) {
+		t.Fatalf("got %v, want a 404 APIError", err)
+	}
+	if !strings.Contains(apiErr.Body, "file not found") {
+		t.Fatalf("error body %q", apiErr.Body)
+	}
+}
+
+func TestDoRequestDecodeError(t *testing.T) {
+	responses := map[string]string{
+		"malformed body": `{"data":`,
+		"wrong type":     `{"data":{"id":42}}`,
+	}
+	for name, response := range responses {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(response))
+		}))
+
+		var out struct {
+			ID string `json:"id"`
+		}
+		err := DoRequest(context.Background(), &types.Config{PinataJWT: "jwt"}, "GET", srv.URL, nil, &out)
+		srv.Close()
+		if err == nil || !strings.Contains(err.Error(), "failed to decode response") {
+			t.Errorf("%s: got %v", name, err)
+		}
+		if types.StatusCode(err) != 0 {
+			t.Errorf("%s: decode error reported as an API error", name)
+		}
+	}
+
+	// A nil out discards whatever came back
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("not json"))
+	}))
+	defer srv.Close()
+	if err := DoRequest(context.Background(), &types.Config{PinataJWT: "jwt"}, "DELETE", srv.URL, nil, nil); err != nil {
+		t.Fatalf("nil out: %v", err)
+	}
+}
@@ -0,0 +1,74 @@
+package request
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/PinataCloud/pinata-go-sdk/pinata/types"
+)
+
+// GatewayTokenParam is the query parameter dedicated gateways read the access key from
+const GatewayTokenParam = "pinataGatewayToken"
+
+// GatewayTokenHeader is the header dedicated gateways read the access key from
+const GatewayTokenHeader = "x-pinata-gateway-token"
+
+// GatewayHost returns the host name for a configured gateway. A value containing
+// a dot, such as "example.mypinata.cloud" or a custom domain, is used as is;
+// a bare name is treated as a subdomain of mypinata.cloud. A scheme or trailing
+// slash is ignored.
+func GatewayHost(gateway string) string {
+	gateway = strings.TrimPrefix(gateway, "https://")
+	gateway = strings.TrimPrefix(gateway, "http://")
+	gateway = strings.TrimSuffix(gateway, "/")
+	if strings.Contains(gateway, ".") {
+		return gateway
+	}
+	return gateway + ".mypinata.cloud"
+}
+
+// GatewayURL builds the URL of a CID under prefix ("ipfs" or "files") on the
+// configured gateway. Path segments address files inside a directory CID. The
+// gateway key, when configured, is appended as a query parameter.
+func GatewayURL(cfg *types.Config, prefix string, cid string, paths ...string) string {
+	link := fmt.Sprintf("https://%s/%s/%s", GatewayHost(cfg.PinataGateway), prefix, cid)
+
+	for _, p := range paths {
+		for _, segment := range strings.Split(strings.Trim(p, "/"), "/") {
+			if segment != "" {
+				link += "/" + url.PathEscape(segment)
+			}
+		}
+	}
+
+	return AppendGatewayToken(link, cfg.PinataGatewayKey)
+}
+
+// SetGatewayToken adds the configured gateway key, if any, to a gateway request
+func SetGatewayToken(cfg *types.Config, req *http.Request) {
+	if cfg.PinataGatewayKey != "" {
+		req.Header.Set(GatewayTokenHeader, cfg.PinataGatewayKey)
+	}
+}
+
+// AppendGatewayToken appends the gateway key to link as a query parameter. The
+// existing query is left untouched so signed parameters stay valid.
+func AppendGatewayToken(link string, key string) string {
+	if key == "" {
+		return link
+	}
+
+	// Leave links that already carry a token, e.g. from the API, unchanged
+	if parsed, err := url.Parse(link); err == nil && parsed.Query().Has(GatewayTokenParam) {
+		return link
+	}
+
+	separator := "?"
+	if strings.Contains(link, "?") {
+		separator = "&"
+	}
+
+	return link + separator + GatewayTokenParam + "=" + url.QueryEscape(key)
+}
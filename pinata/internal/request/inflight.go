@@ -0,0 +1,109 @@
+package request
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"runtime"
+	"sync"
+	"weak"
+
+	"github.com/PinataCloud/pinata-go-sdk/pinata/types"
+)
+
+// trackers holds the in-flight request tracker for each configuration
+var trackers sync.Map // weak.Pointer[types.Config] -> *tracker
+
+// tracker counts the requests in flight for a configuration and refuses new
+// ones once it is closed
+type tracker struct {
+	mu     sync.Mutex
+	wg     sync.WaitGroup
+	closed bool
+}
+
+// trackerFor returns the tracker shared by every service using cfg
+func trackerFor(cfg *types.Config) *tracker {
+	return loadForConfig(&trackers, cfg, func() *tracker { return &tracker{} })
+}
+
+// loadForConfig returns the value m holds for cfg, storing one made by create
+// if there is none. Keys are weak and entries are deleted once cfg is garbage
+// collected, so the state of closed and discarded clients does not accumulate.
+func loadForConfig[T any](m *sync.Map, cfg *types.Config, create func() T) T {
+	key := weak.Make(cfg)
+	if value, ok := m.Load(key); ok {
+		return value.(T)
+	}
+
+	value, loaded := m.LoadOrStore(key, create())
+	if !loaded {
+		runtime.AddCleanup(cfg, func(key weak.Pointer[types.Config]) {
+			m.Delete(key)
+		}, key)
+	}
+	return value.(T)
+}
+
+// begin registers a new request, failing with types.ErrClientClosed after Shutdown.
+// The returned function must be called exactly once when the request is over.
+func (t *tracker) begin() (func(), error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.closed {
+		return nil, types.ErrClientClosed
+	}
+
+	t.wg.Add(1)
+	var once sync.Once
+	return func() { once.Do(t.wg.Done) }, nil
+}
+
+// Shutdown stops cfg from sending new requests and waits until the requests in
+// flight have finished, including reading their response bodies, or ctx is
+// done. Idle connections are closed on the transport the SDK created for cfg; a
+// custom HTTPClient's transport is left alone since it may be shared.
+func Shutdown(ctx context.Context, cfg *types.Config) error {
+	t := trackerFor(cfg)
+
+	t.mu.Lock()
+	t.closed = true
+	t.mu.Unlock()
+
+	transport, owned := transports.Load(weak.Make(cfg))
+	if owned {
+		transport.(*http.Transport).CloseIdleConnections()
+	}
+
+	finished := make(chan struct{})
+	go func() {
+		t.wg.Wait()
+		close(finished)
+	}()
+
+	select {
+	case <-finished:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	// Connections released by the requests that just finished are idle now
+	if owned {
+		transport.(*http.Transport).CloseIdleConnections()
+	}
+	return nil
+}
+
+// trackedBody ends the request's tracking when the response body is closed
+type trackedBody struct {
+	io.ReadCloser
+	done func()
+}
+
+// Close implements io.Closer
+func (b *trackedBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.done()
+	return err
+}
@@ -0,0 +1,103 @@
+package request
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+	"time"
+	"weak"
+
+	"github.com/PinataCloud/pinata-go-sdk/pinata/types"
+)
+
+func TestShutdownWaitsForInFlightRequest(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	cfg := &types.Config{PinataJWT: "jwt"}
+
+	sent := make(chan error, 1)
+	go func() {
+		req, _ := http.NewRequest("POST", srv.URL, nil)
+		resp, err := Do(cfg, req)
+		if err == nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		sent <- err
+	}()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := Shutdown(ctx, cfg); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Shutdown with a request in flight: got %v, want DeadlineExceeded", err)
+	}
+
+	req, _ := http.NewRequest("GET", srv.URL, nil)
+	if _, err := Do(cfg, req); !errors.Is(err, types.ErrClientClosed) {
+		t.Fatalf("request after Shutdown: got %v, want ErrClientClosed", err)
+	}
+
+	close(release)
+	if err := Shutdown(context.Background(), cfg); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-sent; err != nil {
+		t.Fatalf("in-flight request: %v", err)
+	}
+}
+
+func TestClientOwnsTransportPerConfig(t *testing.T) {
+	a := &types.Config{}
+	b := &types.Config{}
+
+	transportA := Client(a).Transport
+	if transportA == http.DefaultTransport {
+		t.Fatal("client uses http.DefaultTransport")
+	}
+	if Client(a).Transport != transportA {
+		t.Fatal("services of one config do not share a transport")
+	}
+	if Client(b).Transport == transportA {
+		t.Fatal("two configs share a transport")
+	}
+
+	custom := &http.Client{Transport: &http.Transport{}}
+	if Client(&types.Config{HTTPClient: custom}).Transport != custom.Transport {
+		t.Fatal("custom client transport replaced")
+	}
+}
+
+func TestConfigStateReleasedAfterCollection(t *testing.T) {
+	cfg := &types.Config{RateLimit: 1000}
+	Client(cfg)
+	trackerFor(cfg)
+	RateLimitStatus(cfg)
+	waitRateLimit(context.Background(), cfg)
+	key := weak.Make(cfg)
+	cfg = nil
+
+	for i := 0; i < 50; i++ {
+		runtime.GC()
+		_, tracked := trackers.Load(key)
+		_, owned := transports.Load(key)
+		_, limited := rateLimitStatuses.Load(key)
+		_, throttled := limiters.Load(key)
+		if !tracked && !owned && !limited && !throttled {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("state of a collected config was not released")
+}
@@ -0,0 +1,82 @@
+package request
+
+import (
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/PinataCloud/pinata-go-sdk/pinata/types"
+)
+
+// redactedValue replaces secrets in log output
+const redactedValue = "REDACTED"
+
+// stderrLogger is used when Config.Debug is set without a Logger
+var stderrLogger = log.New(os.Stderr, "pinata: ", log.LstdFlags)
+
+// logger returns the logger configured for cfg, or nil when logging is off
+func logger(cfg *types.Config) types.Logger {
+	if cfg.Logger != nil {
+		return cfg.Logger
+	}
+	if cfg.Debug {
+		return stderrLogger
+	}
+	return nil
+}
+
+// logRequest logs the method, URL and headers of an outgoing request
+func logRequest(l types.Logger, req *http.Request, attempt int) {
+	l.Printf("--> %s %s (attempt %d)", req.Method, logURL(req.URL), attempt+1)
+
+	keys := make([]string, 0, len(req.Header))
+	for key := range req.Header {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := strings.Join(req.Header[key], ", ")
+		if isSecretHeader(key) {
+			value = redactedValue
+		}
+		value = redact(value, secrets(nil, req)...)
+		l.Printf("    %s: %s", key, value)
+	}
+}
+
+// logResponse logs the outcome of a request sent at start
+func logResponse(l types.Logger, req *http.Request, resp *http.Response, err error, start time.Time) {
+	elapsed := time.Since(start).Round(time.Millisecond)
+	if err != nil {
+		l.Printf("<-- %s %s failed after %s: %s", req.Method, logURL(req.URL), elapsed, redact(err.Error(), secrets(nil, req)...))
+		return
+	}
+	l.Printf("<-- %s %s %s (%s)", req.Method, logURL(req.URL), resp.Status, elapsed)
+}
+
+// isSecretHeader reports whether a header carries credentials
+func isSecretHeader(key string) bool {
+	return strings.EqualFold(key, "Authorization") || strings.EqualFold(key, GatewayTokenHeader)
+}
+
+// logURL returns u with the gateway token and signed access link parameters hidden
+func logURL(u *url.URL) string {
+	query := u.Query()
+	if len(query) == 0 {
+		return u.String()
+	}
+
+	redacted := *u
+	for _, key := range []string{GatewayTokenParam, "X-Signature"} {
+		if query.Has(key) {
+			query.Set(key, redactedValue)
+		}
+	}
+	redacted.RawQuery = query.Encode()
+	return redacted.String()
+}
@@ -0,0 +1,61 @@
+package request
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/PinataCloud/pinata-go-sdk/pinata/types"
+)
+
+const testJWT = "eyJhbGciOiJIUzI1NiJ9.secret-payload.signature"
+
+func TestLoggerRedactsCredentials(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	var out bytes.Buffer
+	cfg := &types.Config{
+		PinataJWT:        testJWT,
+		PinataGatewayKey: "gateway-secret-key",
+		Logger:           log.New(&out, "", 0),
+	}
+
+	req, err := New(context.Background(), cfg, "GET", srv.URL+"/files/public/file-1?"+GatewayTokenParam+"=gateway-secret-key", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	SetGatewayToken(cfg, req)
+	resp, err := Do(cfg, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	logged := out.String()
+	for _, want := range []string{"--> GET " + srv.URL + "/files/public/file-1", "Authorization: REDACTED", "<-- GET", "404 Not Found"} {
+		if !strings.Contains(logged, want) {
+			t.Errorf("log does not contain %q:\n%s", want, logged)
+		}
+	}
+	for _, secret := range []string{testJWT, "secret-payload", "gateway-secret-key"} {
+		if strings.Contains(logged, secret) {
+			t.Errorf("log leaks %q:\n%s", secret, logged)
+		}
+	}
+}
+
+func TestLoggingOffByDefault(t *testing.T) {
+	if logger(&types.Config{}) != nil {
+		t.Fatal("logging enabled without a Logger or Debug")
+	}
+	if logger(&types.Config{Debug: true}) != stderrLogger {
+		t.Fatal("Debug does not log to stderr")
+	}
+}
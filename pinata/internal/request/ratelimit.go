@@ -0,0 +1,118 @@
+package request
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/PinataCloud/pinata-go-sdk/pinata/types"
+)
+
+// limiters holds the rate limiter for each configuration, so every service
+// built from the same Config shares one budget
+var limiters sync.Map // weak.Pointer[types.Config] -> *limiter
+
+// limiter spaces requests evenly so no more than rate are started per second
+type limiter struct {
+	mu   sync.Mutex
+	rate float64
+	next time.Time
+}
+
+// waitRateLimit blocks until cfg's rate limit allows another request, or ctx is done
+func waitRateLimit(ctx context.Context, cfg *types.Config) error {
+	if cfg.RateLimit <= 0 {
+		return nil
+	}
+
+	l := loadForConfig(&limiters, cfg, func() *limiter { return &limiter{rate: cfg.RateLimit} })
+	return l.wait(ctx, cfg.RateLimit)
+}
+
+// wait reserves the next free slot and sleeps until it arrives
+func (l *limiter) wait(ctx context.Context, rate float64) error {
+	l.mu.Lock()
+	// Pick up changes to Config.RateLimit
+	l.rate = rate
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	delay := l.next.Sub(now)
+	l.next = l.next.Add(time.Duration(float64(time.Second) / l.rate))
+	l.mu.Unlock()
+
+	if delay <= 0 {
+		return ctx.Err()
+	}
+	return sleep(ctx, delay)
+}
+
+// rateLimitStatuses holds the latest rate limit reported by the API for each
+// configuration
+var rateLimitStatuses sync.Map // weak.Pointer[types.Config] -> *rateLimitStatus
+
+// rateLimitStatus guards the latest status of one configuration
+type rateLimitStatus struct {
+	mu     sync.Mutex
+	status types.RateLimitStatus
+	seen   bool
+}
+
+// RateLimitStatus returns the rate limit from the latest API response sent with
+// cfg that carried rate limit headers, and false if there has been none yet
+func RateLimitStatus(cfg *types.Config) (types.RateLimitStatus, bool) {
+	state := loadForConfig(&rateLimitStatuses, cfg, func() *rateLimitStatus { return &rateLimitStatus{} })
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	return state.status, state.seen
+}
+
+// recordRateLimit stores the rate limit headers of resp, if it has any. Only
+// responses to requests sent with the JWT are from the API; those from gateways
+// or third-party URLs would overwrite its status with their own limits.
+func recordRateLimit(cfg *types.Config, req *http.Request, resp *http.Response) {
+	if _, ok := req.Header["Authorization"]; !ok {
+		return
+	}
+
+	limit, hasLimit := headerInt(resp, "x-ratelimit-limit")
+	remaining, hasRemaining := headerInt(resp, "x-ratelimit-remaining")
+	reset, hasReset := headerInt(resp, "x-ratelimit-reset")
+	if !hasLimit && !hasRemaining && !hasReset {
+		return
+	}
+
+	now := time.Now()
+	status := types.RateLimitStatus{
+		Limit:     limit,
+		Remaining: remaining,
+		UpdatedAt: now,
+	}
+
+	// The reset is either a Unix time or a number of seconds from now
+	if hasReset {
+		if reset > 1_000_000_000 {
+			status.Reset = time.Unix(int64(reset), 0)
+		} else {
+			status.Reset = now.Add(time.Duration(reset) * time.Second)
+		}
+	}
+
+	state := loadForConfig(&rateLimitStatuses, cfg, func() *rateLimitStatus { return &rateLimitStatus{} })
+	state.mu.Lock()
+	state.status = status
+	state.seen = true
+	state.mu.Unlock()
+}
+
+// headerInt parses a non-negative integer header
+func headerInt(resp *http.Response, name string) (int, bool) {
+	n, err := strconv.Atoi(resp.Header.Get(name))
+	if err != nil || n < 0 {
+		return 0, false
+	}
+	return n, true
+}
@@ -0,0 +1,112 @@
+package request
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/PinataCloud/pinata-go-sdk/pinata/types"
+)
+
+func okServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":{}}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestRateLimitSpacesRequests(t *testing.T) {
+	srv := okServer(t)
+	cfg := &types.Config{PinataJWT: "jwt", RateLimit: 20}
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		if err := DoRequest(context.Background(), cfg, "GET", srv.URL, nil, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The first request goes at once and each of the other 4 waits 50ms
+	if elapsed := time.Since(start); elapsed < 200*time.Millisecond {
+		t.Fatalf("5 requests at 20/s took %s, want at least 200ms", elapsed)
+	}
+}
+
+func TestRateLimitZeroIsUnlimited(t *testing.T) {
+	srv := okServer(t)
+	cfg := &types.Config{PinataJWT: "jwt"}
+
+	start := time.Now()
+	for i := 0; i < 20; i++ {
+		if err := DoRequest(context.Background(), cfg, "GET", srv.URL, nil, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("20 unlimited requests took %s", elapsed)
+	}
+}
+
+func TestRateLimitWaitHonorsContext(t *testing.T) {
+	srv := okServer(t)
+	cfg := &types.Config{PinataJWT: "jwt", RateLimit: 0.5}
+
+	if err := DoRequest(context.Background(), cfg, "GET", srv.URL, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// The next slot is 2s away, far past the deadline
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := DoRequest(ctx, cfg, "GET", srv.URL, nil, nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("cancelled wait took %s", elapsed)
+	}
+}
+
+func TestRateLimitStatusOnlyFromAPIRequests(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		remaining := "50"
+		if r.Header.Get("Authorization") == "" {
+			remaining = "1"
+		}
+		w.Header().Set("x-ratelimit-remaining", remaining)
+		w.Write([]byte(`{"data":{}}`))
+	}))
+	t.Cleanup(srv.Close)
+	cfg := &types.Config{PinataJWT: "jwt"}
+
+	// A gateway or third-party fetch carries no JWT and is not recorded
+	fetch := func() {
+		req, _ := http.NewRequest("GET", srv.URL, nil)
+		resp, err := DoWithClient(cfg, srv.Client(), req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+
+	fetch()
+	if status, ok := RateLimitStatus(cfg); ok {
+		t.Fatalf("recorded %+v from a request without the JWT", status)
+	}
+
+	if err := DoRequest(context.Background(), cfg, "GET", srv.URL, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	fetch()
+	if status, ok := RateLimitStatus(cfg); !ok || status.Remaining != 50 {
+		t.Fatalf("got %+v, %t, want the API's remaining 50", status, ok)
+	}
+}
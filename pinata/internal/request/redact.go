@@ -0,0 +1,69 @@
+package request
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/PinataCloud/pinata-go-sdk/pinata/types"
+)
+
+// minSecretLen guards against redacting short values that would mangle
+// unrelated text, such as a one character placeholder key
+const minSecretLen = 8
+
+// redact replaces every occurrence of the given secrets in s
+func redact(s string, secrets ...string) string {
+	for _, secret := range secrets {
+		if len(secret) >= minSecretLen {
+			s = strings.ReplaceAll(s, secret, redactedValue)
+		}
+	}
+	return s
+}
+
+// secrets returns the credentials that must never appear in errors or logs:
+// the configured JWT and gateway key, and whatever credentials req carries
+func secrets(cfg *types.Config, req *http.Request) []string {
+	var list []string
+	if cfg != nil {
+		list = append(list, cfg.PinataJWT, cfg.PinataGatewayKey)
+	}
+	if req != nil {
+		list = append(list,
+			strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer "),
+			req.Header.Get(GatewayTokenHeader),
+			req.URL.Query().Get(GatewayTokenParam),
+		)
+	}
+	return list
+}
+
+// redactedError hides secrets in the message of an underlying error while
+// keeping it available to errors.Is and errors.As
+type redactedError struct {
+	msg string
+	err error
+}
+
+// Error implements the error interface
+func (e *redactedError) Error() string {
+	return e.msg
+}
+
+// Unwrap returns the underlying error
+func (e *redactedError) Unwrap() error {
+	return e.err
+}
+
+// redactError returns err unchanged unless its message contains one of the secrets
+func redactError(err error, secrets ...string) error {
+	if err == nil {
+		return nil
+	}
+
+	msg := err.Error()
+	if redacted := redact(msg, secrets...); redacted != msg {
+		return &redactedError{msg: redacted, err: err}
+	}
+	return err
+}
@@ -0,0 +1,69 @@
+package request
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/PinataCloud/pinata-go-sdk/pinata/types"
+)
+
+// echoTransport fails every request with an error quoting its Authorization header
+type echoTransport struct{}
+
+var errEcho = errors.New("connection refused")
+
+func (echoTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return nil, fmt.Errorf("sending with %q: %w", req.Header.Get("Authorization"), errEcho)
+}
+
+func TestErrorFromServerOmitsJWT(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprintf(w, `{"error":"invalid token %s"}`, r.Header.Get("Authorization"))
+	}))
+	defer srv.Close()
+
+	cfg := &types.Config{PinataJWT: testJWT}
+	err := DoRequest(context.Background(), cfg, "GET", srv.URL, nil, nil)
+	if types.StatusCode(err) != http.StatusUnauthorized {
+		t.Fatalf("got %v, want a 401", err)
+	}
+	if strings.Contains(err.Error(), testJWT) {
+		t.Fatalf("error leaks the JWT: %v", err)
+	}
+
+	var apiErr *types.APIError
+	if !errors.As(err, &apiErr) || strings.Contains(apiErr.Body, testJWT) || strings.Contains(apiErr.Message, testJWT) {
+		t.Fatalf("API error body or message leaks the JWT: %+v", apiErr)
+	}
+}
+
+func TestTransportErrorOmitsJWT(t *testing.T) {
+	cfg := &types.Config{PinataJWT: testJWT, HTTPClient: &http.Client{Transport: echoTransport{}}}
+
+	err := DoRequest(context.Background(), cfg, "GET", "http://example.invalid/files", nil, nil)
+	if err == nil {
+		t.Fatal("request succeeded")
+	}
+	if strings.Contains(err.Error(), testJWT) || !strings.Contains(err.Error(), "REDACTED") {
+		t.Fatalf("error does not redact the JWT: %v", err)
+	}
+	if !errors.Is(err, errEcho) {
+		t.Fatalf("redacted error %v no longer wraps the cause", err)
+	}
+}
+
+func TestRedact(t *testing.T) {
+	if got := redact("token "+testJWT+" twice "+testJWT, testJWT); got != "token REDACTED twice REDACTED" {
+		t.Fatalf("got %q", got)
+	}
+	// Short values would mangle unrelated text, so they are left alone
+	if got := redact("a b c", "a"); got != "a b c" {
+		t.Fatalf("got %q", got)
+	}
+}
@@ -0,0 +1,316 @@
+// Package request holds the HTTP plumbing shared by the Pinata services
+package request
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/PinataCloud/pinata-go-sdk/pinata/types"
+)
+
+// DefaultRetryBaseDelay is the backoff base used when retries are enabled
+// without an explicit RetryBaseDelay
+const DefaultRetryBaseDelay = types.DefaultRetryBaseDelay
+
+// Client returns the HTTP client used to send requests for the given configuration.
+// Unless a custom client is configured, every service built from cfg shares a
+// transport the SDK owns for cfg, so connections are pooled across services and
+// Shutdown can close them. With DisableKeepAlives that transport closes every
+// connection after use.
+func Client(cfg *types.Config) *http.Client {
+	if cfg.HTTPClient != nil {
+		client := *cfg.HTTPClient
+		if cfg.Timeout > 0 {
+			client.Timeout = cfg.Timeout
+		}
+		return &client
+	}
+
+	return &http.Client{
+		Timeout:   cfg.Timeout,
+		Transport: transportFor(cfg),
+	}
+}
+
+// transports holds the transport the SDK created for each configuration
+var transports sync.Map // weak.Pointer[types.Config] -> *http.Transport
+
+// transportFor returns cfg's own copy of http.DefaultTransport
+func transportFor(cfg *types.Config) *http.Transport {
+	return loadForConfig(&transports, cfg, func() *http.Transport {
+		transport := &http.Transport{Proxy: http.ProxyFromEnvironment}
+		if defaultTransport, ok := http.DefaultTransport.(*http.Transport); ok {
+			transport = defaultTransport.Clone()
+		}
+		transport.DisableKeepAlives = cfg.DisableKeepAlives
+		return transport
+	})
+}
+
+// Do sends req with the configured client. Requests using an idempotent method
+// (GET, HEAD, PUT, DELETE) are retried on transient failures according to the
+// retry settings in cfg.
+func Do(cfg *types.Config, req *http.Request) (*http.Response, error) {
+	return track(cfg, Client(cfg), req, isIdempotent(req.Method))
+}
+
+// DoWithClient is like Do but sends req with client instead of the configured one.
+// The rest of cfg, such as retries and the rate limit, still applies.
+func DoWithClient(cfg *types.Config, client *http.Client, req *http.Request) (*http.Response, error) {
+	return track(cfg, client, req, isIdempotent(req.Method))
+}
+
+// DoIdempotent is like Do but retries regardless of the request method. It is
+// meant for uploads, which are safe to resend as long as the body can be rebuilt
+// through req.GetBody.
+func DoIdempotent(cfg *types.Config, req *http.Request) (*http.Response, error) {
+	return track(cfg, Client(cfg), req, true)
+}
+
+// track sends req while counting it as in flight until its body is closed, so
+// Shutdown can wait for it
+func track(cfg *types.Config, client *http.Client, req *http.Request, retryable bool) (*http.Response, error) {
+	done, err := trackerFor(cfg).begin()
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := send(cfg, client, req, retryable)
+	if err != nil {
+		done()
+		return nil, redactError(err, secrets(cfg, req)...)
+	}
+
+	resp.Body = &trackedBody{ReadCloser: resp.Body, done: done}
+	return resp, nil
+}
+
+func send(cfg *types.Config, client *http.Client, req *http.Request, retryable bool) (*http.Response, error) {
+	// Fail early instead of letting the API answer with a confusing 401
+	if _, ok := req.Header["Authorization"]; ok && strings.TrimSpace(cfg.PinataJWT) == "" {
+		return nil, types.ErrMissingJWT
+	}
+
+	policy := retryPolicy(cfg)
+	maxRetries := 0
+	if retryable && (req.Body == nil || req.GetBody != nil) {
+		maxRetries = policy.MaxRetries
+	}
+
+	// Identify the SDK unless a custom header already set a User-Agent
+	if req.Header.Get("User-Agent") == "" {
+		userAgent := cfg.UserAgent
+		if userAgent == "" {
+			userAgent = types.DefaultUserAgent
+		}
+		req.Header.Set("User-Agent", userAgent)
+	}
+
+	// Also covers a custom HTTPClient, whose transport is left alone
+	if cfg.DisableKeepAlives {
+		req.Close = true
+	}
+
+	// Apply per-request options carried by the context
+	for _, opt := range types.RequestOptionsFromContext(req.Context()) {
+		opt(req)
+	}
+
+	log := logger(cfg)
+
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, fmt.Errorf("failed to rewind request body: %w", err)
+			}
+			req.Body = body
+		}
+
+		if err := waitRateLimit(req.Context(), cfg); err != nil {
+			return nil, err
+		}
+
+		if log != nil {
+			logRequest(log, req, attempt)
+		}
+
+		start := time.Now()
+		resp, err := client.Do(req)
+		if log != nil {
+			logResponse(log, req, resp, err, start)
+		}
+		if resp != nil {
+			recordRateLimit(cfg, req, resp)
+		}
+		if attempt >= maxRetries || !shouldRetry(req.Context(), resp, err) {
+			if resp != nil {
+				for _, hook := range types.ResponseHooksFromContext(req.Context()) {
+					hook(resp)
+				}
+			}
+			return resp, err
+		}
+
+		delay := policy.Delay(attempt, rand.Float64())
+		if resp != nil {
+			// Honor the server's Retry-After, but never wait past the policy's cap
+			if wait, ok := RetryAfter(resp); ok {
+				delay = wait
+				if policy.MaxDelay > 0 && delay > policy.MaxDelay {
+					delay = policy.MaxDelay
+				}
+			}
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		if err := sleep(req.Context(), delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// shouldRetry reports whether a response or transport error is transient
+func shouldRetry(ctx context.Context, resp *http.Response, err error) bool {
+	if err != nil {
+		var permanent *permanentError
+		if errors.As(err, &permanent) {
+			return false
+		}
+
+		// Errors caused by the caller's context are final
+		return ctx.Err() == nil
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+
+	return false
+}
+
+// permanentError marks an error that sending the request again cannot fix
+type permanentError struct {
+	err error
+}
+
+// Error implements the error interface
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the marked error
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// Permanent marks err as not worth retrying, e.g. an error raised while writing
+// a streamed request body that a retry would raise again. It returns nil for nil.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// Error builds the *types.APIError returned for a non-OK response, consuming its body.
+// Rate-limited responses are reported as *types.RateLimitError.
+func Error(resp *http.Response) error {
+	body, _ := io.ReadAll(resp.Body)
+	// The API may echo the request back, so strip the credentials it carried
+	body = []byte(redact(string(body), secrets(nil, resp.Request)...))
+	apiErr := types.NewAPIError(resp.StatusCode, body)
+	apiErr.RequestID = RequestID(resp)
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		retryAfter, _ := RetryAfter(resp)
+		return &types.RateLimitError{
+			APIError:   apiErr,
+			RetryAfter: retryAfter,
+		}
+	}
+
+	return apiErr
+}
+
+// RequestID returns the identifier the API or its edge assigned to the request
+// that produced resp, or an empty string if there is none
+func RequestID(resp *http.Response) string {
+	if id := resp.Header.Get("X-Request-Id"); id != "" {
+		return id
+	}
+	return resp.Header.Get("Cf-Ray")
+}
+
+// RetryAfter parses the Retry-After header of resp, which holds either a number
+// of seconds or an HTTP date
+func RetryAfter(resp *http.Response) (time.Duration, bool) {
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		wait := time.Until(date)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+
+	return 0, false
+}
+
+// retryPolicy returns cfg.Retry, or the policy described by MaxRetries and
+// RetryBaseDelay when it is not set
+func retryPolicy(cfg *types.Config) types.RetryPolicy {
+	if cfg.Retry != nil {
+		return *cfg.Retry
+	}
+
+	return types.RetryPolicy{
+		MaxRetries: cfg.MaxRetries,
+		BaseDelay:  cfg.RetryBaseDelay,
+		Multiplier: types.DefaultRetryMultiplier,
+		Jitter:     0.5,
+	}
+}
+
+// sleep waits for d or until ctx is done
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		return true
+	}
+
+	return false
+}
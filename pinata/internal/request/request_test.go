@@ -0,0 +1,265 @@
+package request
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/PinataCloud/pinata-go-sdk/pinata/types"
+)
+
+// flakyServer fails the first failures requests with status, then answers 200
+func flakyServer(t *testing.T, failures int32, status int) (*httptest.Server, *int32) {
+	t.Helper()
+
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) <= failures {
+			w.WriteHeader(status)
+			return
+		}
+		w.Write([]byte(`{"data":{}}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+func fastRetries(n int) *types.RetryPolicy {
+	return &types.RetryPolicy{MaxRetries: n, BaseDelay: time.Millisecond}
+}
+
+func TestRetryFlakyServer(t *testing.T) {
+	srv, calls := flakyServer(t, 2, http.StatusServiceUnavailable)
+	cfg := &types.Config{PinataJWT: "jwt", Retry: fastRetries(3)}
+
+	if err := DoRequest(context.Background(), cfg, "GET", srv.URL, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if *calls != 3 {
+		t.Fatalf("got %d calls, want 3", *calls)
+	}
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	srv, calls := flakyServer(t, 10, http.StatusBadGateway)
+	cfg := &types.Config{PinataJWT: "jwt", Retry: fastRetries(2)}
+
+	err := DoRequest(context.Background(), cfg, "GET", srv.URL, nil, nil)
+	if types.StatusCode(err) != http.StatusBadGateway {
+		t.Fatalf("got %v, want a 502 API error", err)
+	}
+	if *calls != 3 {
+		t.Fatalf("got %d calls, want 3", *calls)
+	}
+}
+
+func TestNoRetryForClientErrors(t *testing.T) {
+	srv, calls := flakyServer(t, 1, http.StatusBadRequest)
+	cfg := &types.Config{PinataJWT: "jwt", Retry: fastRetries(3)}
+
+	if err := DoRequest(context.Background(), cfg, "GET", srv.URL, nil, nil); types.StatusCode(err) != http.StatusBadRequest {
+		t.Fatalf("got %v, want a 400 API error", err)
+	}
+	if *calls != 1 {
+		t.Fatalf("got %d calls, want 1", *calls)
+	}
+}
+
+func TestNoRetryForNonIdempotentMethods(t *testing.T) {
+	srv, calls := flakyServer(t, 1, http.StatusServiceUnavailable)
+	cfg := &types.Config{PinataJWT: "jwt", Retry: fastRetries(3)}
+
+	DoRequest(context.Background(), cfg, "POST", srv.URL, map[string]string{"a": "b"}, nil)
+	if *calls != 1 {
+		t.Fatalf("got %d calls, want 1", *calls)
+	}
+}
+
+func TestRetryResendsBody(t *testing.T) {
+	var bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if len(bodies) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"data":{}}`))
+	}))
+	defer srv.Close()
+
+	cfg := &types.Config{PinataJWT: "jwt", Retry: fastRetries(1)}
+	req, err := New(context.Background(), cfg, "PUT", srv.URL, map[string]string{"name": "x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := Do(cfg, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if len(bodies) != 2 || bodies[0] != bodies[1] || bodies[0] == "" {
+		t.Fatalf("got bodies %q, want the same body twice", bodies)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Duration
+		ok    bool
+	}{
+		{"", 0, false},
+		{"3", 3 * time.Second, true},
+		{"-1", 0, false},
+		{"soon", 0, false},
+		{time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat), 0, true},
+	}
+
+	for _, tt := range tests {
+		resp := &http.Response{Header: http.Header{}}
+		resp.Header.Set("Retry-After", tt.value)
+		got, ok := RetryAfter(resp)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("%q: got %s %t, want %s %t", tt.value, got, ok, tt.want, tt.ok)
+		}
+	}
+
+	resp := &http.Response{Header: http.Header{}}
+	resp.Header.Set("Retry-After", time.Now().Add(time.Minute).UTC().Format(http.TimeFormat))
+	if got, ok := RetryAfter(resp); !ok || got <= 50*time.Second || got > time.Minute {
+		t.Errorf("HTTP date a minute ahead: got %s %t", got, ok)
+	}
+}
+
+func TestRateLimitErrorWithoutRetries(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "7")
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"error":"slow down"}`))
+	}))
+	defer srv.Close()
+
+	cfg := &types.Config{PinataJWT: "jwt"}
+	err := DoRequest(context.Background(), cfg, "GET", srv.URL, nil, nil)
+
+	var rateErr *types.RateLimitError
+	if !errors.As(err, &rateErr) {
+		t.Fatalf("got %v, want a *types.RateLimitError", err)
+	}
+	if rateErr.RetryAfter != 7*time.Second {
+		t.Fatalf("got RetryAfter %s, want 7s", rateErr.RetryAfter)
+	}
+}
+
+func TestRetryHonorsRetryAfter(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{"data":{}}`))
+	}))
+	defer srv.Close()
+
+	// A base delay far above the test timeout shows Retry-After was used instead
+	cfg := &types.Config{PinataJWT: "jwt", Retry: &types.RetryPolicy{MaxRetries: 1, BaseDelay: time.Hour}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := DoRequest(ctx, cfg, "GET", srv.URL, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 2 {
+		t.Fatalf("got %d calls, want 2", calls)
+	}
+}
+
+func TestRetryAfterCappedByMaxDelay(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("Retry-After", "3600")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"data":{}}`))
+	}))
+	defer srv.Close()
+
+	cfg := &types.Config{PinataJWT: "jwt", Retry: &types.RetryPolicy{MaxRetries: 1, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := DoRequest(ctx, cfg, "GET", srv.URL, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 2 {
+		t.Fatalf("got %d calls, want 2", calls)
+	}
+}
+
+func TestUserAgent(t *testing.T) {
+	var userAgents []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgents = append(userAgents, r.Header.Get("User-Agent"))
+		w.Write([]byte(`{"data":{}}`))
+	}))
+	defer srv.Close()
+
+	configs := []*types.Config{
+		{PinataJWT: "jwt"},
+		{PinataJWT: "jwt", UserAgent: "my-app/2.0"},
+		{PinataJWT: "jwt", UserAgent: "my-app/2.0", CustomHeaders: map[string]string{"User-Agent": "from-header"}},
+	}
+	for _, cfg := range configs {
+		if err := DoRequest(context.Background(), cfg, "GET", srv.URL, nil, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := []string{"pinata-go-sdk/" + types.Version, "my-app/2.0", "from-header"}
+	for i := range want {
+		if userAgents[i] != want[i] {
+			t.Errorf("request %d sent User-Agent %q, want %q", i, userAgents[i], want[i])
+		}
+	}
+}
+
+func TestDisableKeepAlives(t *testing.T) {
+	if Client(&types.Config{}).Transport.(*http.Transport).DisableKeepAlives {
+		t.Fatal("keep-alives disabled by default")
+	}
+	if !Client(&types.Config{DisableKeepAlives: true}).Transport.(*http.Transport).DisableKeepAlives {
+		t.Fatal("transport keeps connections alive")
+	}
+
+	var closes []bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		closes = append(closes, r.Close)
+		w.Write([]byte(`{"data":{}}`))
+	}))
+	defer srv.Close()
+
+	configs := []*types.Config{
+		{PinataJWT: "jwt"},
+		{PinataJWT: "jwt", DisableKeepAlives: true},
+		{PinataJWT: "jwt", DisableKeepAlives: true, HTTPClient: srv.Client()},
+	}
+	for _, cfg := range configs {
+		if err := DoRequest(context.Background(), cfg, "GET", srv.URL, nil, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if closes[0] || !closes[1] || !closes[2] {
+		t.Fatalf("Connection: close sent %v, want [false true true]", closes)
+	}
+}
@@ -0,0 +1,182 @@
+// Package keys provides functionality for managing Pinata API keys
+package keys
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/PinataCloud/pinata-go-sdk/pinata/internal/request"
+	"github.com/PinataCloud/pinata-go-sdk/pinata/types"
+)
+
+// Service provides API key operations for Pinata
+type Service struct {
+	config interface{}
+}
+
+// New creates a new keys service with the provided configuration
+func New(config interface{}) *Service {
+	return &Service{
+		config: config,
+	}
+}
+
+// Config returns the service configuration
+func (s *Service) Config() interface{} {
+	return s.config
+}
+
+// Create creates a new API key with the given name, usage limit and scopes
+func (s *Service) Create(opts *CreateOptions) (*types.CreateKeyResponse, error) {
+	return s.CreateContext(context.Background(), opts)
+}
+
+// CreateContext is like Create but carries ctx through to the underlying requests
+func (s *Service) CreateContext(ctx context.Context, opts *CreateOptions) (*types.CreateKeyResponse, error) {
+	if opts == nil || opts.Name == "" {
+		return nil, fmt.Errorf("key name is required")
+	}
+
+	cfg := s.config.(*types.Config)
+	url := fmt.Sprintf("%s/pinata/keys", cfg.APIUrl)
+
+	req, err := request.New(ctx, cfg, "POST", url, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := request.Do(cfg, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, request.Error(resp)
+	}
+
+	var response types.CreateKeyResponse
+
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &response, nil
+}
+
+// List retrieves the API keys on the account
+func (s *Service) List(opts *ListOptions) (*types.KeyListResponse, error) {
+	return s.ListContext(context.Background(), opts)
+}
+
+// ListContext is like List but carries ctx through to the underlying requests
+func (s *Service) ListContext(ctx context.Context, opts *ListOptions) (*types.KeyListResponse, error) {
+	cfg := s.config.(*types.Config)
+	baseURL := fmt.Sprintf("%s/pinata/keys", cfg.APIUrl)
+
+	// Build query parameters
+	params := url.Values{}
+
+	if opts != nil {
+		if opts.Name != "" {
+			params.Add("name", opts.Name)
+		}
+		if opts.Revoked != nil {
+			params.Add("revoked", strconv.FormatBool(*opts.Revoked))
+		}
+		if opts.LimitedUse != nil {
+			params.Add("limitedUse", strconv.FormatBool(*opts.LimitedUse))
+		}
+		if opts.Exhausted != nil {
+			params.Add("exhausted", strconv.FormatBool(*opts.Exhausted))
+		}
+		if opts.Offset > 0 {
+			params.Add("offset", strconv.Itoa(opts.Offset))
+		}
+	}
+
+	// Append query parameters if any
+	requestURL := baseURL
+	if len(params) > 0 {
+		requestURL = fmt.Sprintf("%s?%s", baseURL, params.Encode())
+	}
+
+	req, err := request.New(ctx, cfg, "GET", requestURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := request.Do(cfg, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, request.Error(resp)
+	}
+
+	var response types.KeyListResponse
+
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &response, nil
+}
+
+// Revoke revokes an API key. Revoking a key that is already revoked is not an error.
+func (s *Service) Revoke(keyID string) error {
+	return s.RevokeContext(context.Background(), keyID)
+}
+
+// RevokeContext is like Revoke but carries ctx through to the underlying requests
+func (s *Service) RevokeContext(ctx context.Context, keyID string) error {
+	if keyID == "" {
+		return fmt.Errorf("key ID is required")
+	}
+
+	cfg := s.config.(*types.Config)
+	url := fmt.Sprintf("%s/pinata/keys/%s", cfg.APIUrl, keyID)
+
+	req, err := request.New(ctx, cfg, "PUT", url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := request.Do(cfg, req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		err := request.Error(resp)
+		if isAlreadyRevoked(err) {
+			return nil
+		}
+		return err
+	}
+
+	return nil
+}
+
+// isAlreadyRevoked reports whether err is the API's rejection of revoking a revoked key
+func isAlreadyRevoked(err error) bool {
+	var apiErr *types.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+
+	if apiErr.StatusCode == http.StatusConflict {
+		return true
+	}
+
+	return strings.Contains(strings.ToLower(apiErr.Body), "already revoked")
+}
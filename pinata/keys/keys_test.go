@@ -0,0 +1,74 @@
+package keys
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/PinataCloud/pinata-go-sdk/pinata/types"
+)
+
+func newTestConfig(t *testing.T, handler http.HandlerFunc) *types.Config {
+	t.Helper()
+
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	return &types.Config{PinataJWT: "jwt", APIUrl: srv.URL}
+}
+
+func TestCreate(t *testing.T) {
+	var payload map[string]interface{}
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/pinata/keys" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&payload)
+		fmt.Fprint(w, `{"JWT":"new-jwt","pinata_api_key":"key","pinata_api_secret":"secret"}`)
+	})
+
+	opts := &CreateOptions{Name: "ci", MaxUses: 5}
+	opts.Scopes.Admin = true
+
+	resp, err := New(cfg).Create(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.JWT != "new-jwt" || resp.PinataAPIKey != "key" || resp.PinataAPISecret != "secret" {
+		t.Fatalf("got %+v", resp)
+	}
+	if payload["keyName"] != "ci" || payload["maxUses"] != float64(5) {
+		t.Fatalf("sent payload %v", payload)
+	}
+	if permissions, _ := payload["permissions"].(map[string]interface{}); permissions["admin"] != true {
+		t.Fatalf("sent permissions %v", payload["permissions"])
+	}
+}
+
+func TestCreateRequiresName(t *testing.T) {
+	if _, err := New(&types.Config{PinataJWT: "jwt"}).Create(&CreateOptions{}); err == nil {
+		t.Fatal("Create without a name succeeded")
+	}
+}
+
+func TestList(t *testing.T) {
+	var query string
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		fmt.Fprint(w, `{"keys":[{"id":"k1","name":"ci","max_uses":5,"uses":2,"revoked":false},{"id":"k2","name":"old","revoked":true}],"count":2}`)
+	})
+
+	revoked := false
+	resp, err := New(cfg).List(&ListOptions{Name: "ci", Revoked: &revoked, Offset: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Count != 2 || len(resp.Keys) != 2 || resp.Keys[0].MaxUses != 5 || !resp.Keys[1].Revoked {
+		t.Fatalf("got %+v", resp)
+	}
+	if query != "name=ci&offset=10&revoked=false" {
+		t.Fatalf("sent query %q", query)
+	}
+}
@@ -0,0 +1,19 @@
+package keys
+
+import "github.com/PinataCloud/pinata-go-sdk/pinata/types"
+
+// CreateOptions represents options for the Create method
+type CreateOptions struct {
+	Name    string          `json:"keyName"`
+	MaxUses int             `json:"maxUses,omitempty"`
+	Scopes  types.KeyScopes `json:"permissions"`
+}
+
+// ListOptions represents options for the List method
+type ListOptions struct {
+	Name       string
+	Revoked    *bool
+	LimitedUse *bool
+	Exhausted  *bool
+	Offset     int
+}
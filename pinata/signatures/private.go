@@ -0,0 +1,96 @@
+package signatures
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/PinataCloud/pinata-go-sdk/pinata/internal/request"
+	"github.com/PinataCloud/pinata-go-sdk/pinata/types"
+)
+
+// PrivateService provides signature operations for files on the private IPFS network
+type PrivateService struct {
+	config interface{}
+}
+
+// NewPrivateService creates a new PrivateService with the provided configuration
+func NewPrivateService(config interface{}) *PrivateService {
+	return &PrivateService{
+		config: config,
+	}
+}
+
+// Add attaches a signature to a CID on the private IPFS network
+func (s *PrivateService) Add(cid string, signature string) (*types.SignatureResponse, error) {
+	return s.AddContext(context.Background(), cid, signature)
+}
+
+// AddContext is like Add but carries ctx through to the underlying requests
+func (s *PrivateService) AddContext(ctx context.Context, cid string, signature string) (*types.SignatureResponse, error) {
+	if cid == "" || signature == "" {
+		return nil, fmt.Errorf("CID and signature are required")
+	}
+
+	cfg := s.config.(*types.Config)
+	url := fmt.Sprintf("%s/files/private/signature/%s", cfg.APIUrl, cid)
+
+	payload := struct {
+		Signature string `json:"signature"`
+	}{
+		Signature: signature,
+	}
+
+	var response *types.SignatureResponse
+	if err := request.DoRequest(ctx, cfg, "POST", url, payload, &response); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// Get retrieves the signature for a CID on the private IPFS network. It returns an
+// error wrapping ErrNoSignature when the CID has not been signed.
+func (s *PrivateService) Get(cid string) (*types.SignatureResponse, error) {
+	return s.GetContext(context.Background(), cid)
+}
+
+// GetContext is like Get but carries ctx through to the underlying requests
+func (s *PrivateService) GetContext(ctx context.Context, cid string) (*types.SignatureResponse, error) {
+	if cid == "" {
+		return nil, fmt.Errorf("CID is required")
+	}
+
+	cfg := s.config.(*types.Config)
+	url := fmt.Sprintf("%s/files/private/signature/%s", cfg.APIUrl, cid)
+
+	var response *types.SignatureResponse
+	if err := request.DoRequest(ctx, cfg, "GET", url, nil, &response); err != nil {
+		if types.IsNotFound(err) {
+			return nil, fmt.Errorf("%w %s: %w", ErrNoSignature, cid, err)
+		}
+		return nil, err
+	}
+
+	if response == nil || response.Signature == "" {
+		return nil, fmt.Errorf("%w %s", ErrNoSignature, cid)
+	}
+
+	return response, nil
+}
+
+// Remove deletes the signature for a CID on the private IPFS network
+func (s *PrivateService) Remove(cid string) error {
+	return s.RemoveContext(context.Background(), cid)
+}
+
+// RemoveContext is like Remove but carries ctx through to the underlying requests
+func (s *PrivateService) RemoveContext(ctx context.Context, cid string) error {
+	if cid == "" {
+		return fmt.Errorf("CID is required")
+	}
+
+	cfg := s.config.(*types.Config)
+	url := fmt.Sprintf("%s/files/private/signature/%s", cfg.APIUrl, cid)
+
+	return request.DoRequest(ctx, cfg, "DELETE", url, nil, nil)
+}
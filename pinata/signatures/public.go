@@ -0,0 +1,96 @@
+package signatures
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/PinataCloud/pinata-go-sdk/pinata/internal/request"
+	"github.com/PinataCloud/pinata-go-sdk/pinata/types"
+)
+
+// PublicService provides signature operations for files on the public IPFS network
+type PublicService struct {
+	config interface{}
+}
+
+// NewPublicService creates a new PublicService with the provided configuration
+func NewPublicService(config interface{}) *PublicService {
+	return &PublicService{
+		config: config,
+	}
+}
+
+// Add attaches a signature to a CID on the public IPFS network
+func (s *PublicService) Add(cid string, signature string) (*types.SignatureResponse, error) {
+	return s.AddContext(context.Background(), cid, signature)
+}
+
+// AddContext is like Add but carries ctx through to the underlying requests
+func (s *PublicService) AddContext(ctx context.Context, cid string, signature string) (*types.SignatureResponse, error) {
+	if cid == "" || signature == "" {
+		return nil, fmt.Errorf("CID and signature are required")
+	}
+
+	cfg := s.config.(*types.Config)
+	url := fmt.Sprintf("%s/files/public/signature/%s", cfg.APIUrl, cid)
+
+	payload := struct {
+		Signature string `json:"signature"`
+	}{
+		Signature: signature,
+	}
+
+	var response *types.SignatureResponse
+	if err := request.DoRequest(ctx, cfg, "POST", url, payload, &response); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// Get retrieves the signature for a CID on the public IPFS network. It returns an
+// error wrapping ErrNoSignature when the CID has not been signed.
+func (s *PublicService) Get(cid string) (*types.SignatureResponse, error) {
+	return s.GetContext(context.Background(), cid)
+}
+
+// GetContext is like Get but carries ctx through to the underlying requests
+func (s *PublicService) GetContext(ctx context.Context, cid string) (*types.SignatureResponse, error) {
+	if cid == "" {
+		return nil, fmt.Errorf("CID is required")
+	}
+
+	cfg := s.config.(*types.Config)
+	url := fmt.Sprintf("%s/files/public/signature/%s", cfg.APIUrl, cid)
+
+	var response *types.SignatureResponse
+	if err := request.DoRequest(ctx, cfg, "GET", url, nil, &response); err != nil {
+		if types.IsNotFound(err) {
+			return nil, fmt.Errorf("%w %s: %w", ErrNoSignature, cid, err)
+		}
+		return nil, err
+	}
+
+	if response == nil || response.Signature == "" {
+		return nil, fmt.Errorf("%w %s", ErrNoSignature, cid)
+	}
+
+	return response, nil
+}
+
+// Remove deletes the signature for a CID on the public IPFS network
+func (s *PublicService) Remove(cid string) error {
+	return s.RemoveContext(context.Background(), cid)
+}
+
+// RemoveContext is like Remove but carries ctx through to the underlying requests
+func (s *PublicService) RemoveContext(ctx context.Context, cid string) error {
+	if cid == "" {
+		return fmt.Errorf("CID is required")
+	}
+
+	cfg := s.config.(*types.Config)
+	url := fmt.Sprintf("%s/files/public/signature/%s", cfg.APIUrl, cid)
+
+	return request.DoRequest(ctx, cfg, "DELETE", url, nil, nil)
+}
@@ -0,0 +1,110 @@
+package signatures
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/PinataCloud/pinata-go-sdk/pinata/types"
+)
+
+const testCID = "bafkreihdwdcefgh4dqkjv67uzcmw7ojee6xedzdetojuzjevtenxquvyku"
+
+// signatureServer stores signatures by CID like the signature endpoints
+func signatureServer(t *testing.T, network string) *types.Config {
+	t.Helper()
+
+	signatures := map[string]string{}
+	prefix := "/files/" + network + "/signature/"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(r.URL.Path) <= len(prefix) || r.URL.Path[:len(prefix)] != prefix {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		cid := r.URL.Path[len(prefix):]
+
+		switch r.Method {
+		case "POST":
+			var payload struct {
+				Signature string `json:"signature"`
+			}
+			json.NewDecoder(r.Body).Decode(&payload)
+			signatures[cid] = payload.Signature
+		case "DELETE":
+			delete(signatures, cid)
+			return
+		}
+
+		signature, ok := signatures[cid]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error":"signature not found"}`)
+			return
+		}
+		fmt.Fprintf(w, `{"data":{"cid":%q,"signature":%q}}`, cid, signature)
+	}))
+	t.Cleanup(srv.Close)
+
+	return &types.Config{PinataJWT: "jwt", APIUrl: srv.URL}
+}
+
+func TestPublicAddGetRemove(t *testing.T) {
+	service := NewPublicService(signatureServer(t, "public"))
+
+	added, err := service.Add(testCID, "0xsig")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if added.CID != testCID || added.Signature != "0xsig" {
+		t.Fatalf("Add: got %+v", added)
+	}
+
+	got, err := service.Get(testCID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Signature != "0xsig" {
+		t.Fatalf("Get: got %+v", got)
+	}
+
+	if err := service.Remove(testCID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.Get(testCID); !errors.Is(err, ErrNoSignature) || !types.IsNotFound(err) {
+		t.Fatalf("Get after Remove: got %v, want ErrNoSignature wrapping a 404", err)
+	}
+}
+
+func TestPrivateAddGetRemove(t *testing.T) {
+	service := NewPrivateService(signatureServer(t, "private"))
+
+	if _, err := service.Add(testCID, "0xsig"); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := service.Get(testCID); err != nil || got.Signature != "0xsig" {
+		t.Fatalf("Get: got %+v, %v", got, err)
+	}
+	if err := service.Remove(testCID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.Get(testCID); !errors.Is(err, ErrNoSignature) {
+		t.Fatalf("Get after Remove: got %v, want ErrNoSignature", err)
+	}
+}
+
+func TestRequiredInputs(t *testing.T) {
+	service := NewPublicService(&types.Config{PinataJWT: "jwt"})
+
+	if _, err := service.Add(testCID, ""); err == nil {
+		t.Error("Add without a signature succeeded")
+	}
+	if _, err := service.Get(""); err == nil {
+		t.Error("Get without a CID succeeded")
+	}
+	if err := service.Remove(""); err == nil {
+		t.Error("Remove without a CID succeeded")
+	}
+}
@@ -0,0 +1,32 @@
+// Package signatures provides functionality for managing CID signatures on Pinata
+package signatures
+
+import "errors"
+
+// ErrNoSignature is returned by Get when the CID has no signature
+var ErrNoSignature = errors.New("no signature exists for CID")
+
+// Service provides signature operations for Pinata
+type Service struct {
+	config  interface{}
+	Public  *PublicService
+	Private *PrivateService
+}
+
+// New creates a new signatures service with the provided configuration
+func New(config interface{}) *Service {
+	service := &Service{
+		config: config,
+	}
+
+	// Initialize public and private services
+	service.Public = NewPublicService(config)
+	service.Private = NewPrivateService(config)
+
+	return service
+}
+
+// Config returns the service configuration
+func (s *Service) Config() interface{} {
+	return s.config
+}
@@ -0,0 +1,118 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// Validate checks the configuration for mistakes that would otherwise only
+// surface as confusing errors from the first request: a missing JWT, API or
+// upload URLs that are not absolute http(s) URLs, a malformed gateway, custom
+// headers that cannot be sent and negative limits. Every problem found is
+// reported in the returned error, which joins them with errors.Join. Empty URLs
+// are allowed since requests fall back to the defaults.
+func (c *Config) Validate() error {
+	var errs []error
+
+	if strings.TrimSpace(c.PinataJWT) == "" {
+		errs = append(errs, fmt.Errorf("PinataJWT is empty"))
+	}
+
+	if err := validateBaseURL(c.APIUrl); err != nil {
+		errs = append(errs, fmt.Errorf("APIUrl: %w", err))
+	}
+	if err := validateBaseURL(c.UploadUrl); err != nil {
+		errs = append(errs, fmt.Errorf("UploadUrl: %w", err))
+	}
+
+	if c.PinataGateway != "" && !validGateway(c.PinataGateway) {
+		errs = append(errs, fmt.Errorf("PinataGateway %q is not a gateway domain", c.PinataGateway))
+	}
+	for _, gateway := range c.FallbackGateways {
+		if !validGateway(gateway) {
+			errs = append(errs, fmt.Errorf("fallback gateway %q is not a gateway domain", gateway))
+		}
+	}
+
+	// Sorted so the errors come out in a stable order
+	keys := make([]string, 0, len(c.CustomHeaders))
+	for key := range c.CustomHeaders {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := c.CustomHeaders[key]
+		if !validHeaderName(key) {
+			errs = append(errs, fmt.Errorf("custom header name %q is not valid", key))
+		}
+		if strings.ContainsAny(value, "\r\n\x00") {
+			errs = append(errs, fmt.Errorf("custom header %s has a value containing a line break or NUL", key))
+		}
+	}
+
+	if c.Timeout < 0 {
+		errs = append(errs, fmt.Errorf("Timeout must not be negative"))
+	}
+	if c.MaxRetries < 0 {
+		errs = append(errs, fmt.Errorf("MaxRetries must not be negative"))
+	}
+	if c.RateLimit < 0 {
+		errs = append(errs, fmt.Errorf("RateLimit must not be negative"))
+	}
+	if c.CacheSize < 0 {
+		errs = append(errs, fmt.Errorf("CacheSize must not be negative"))
+	}
+
+	return errors.Join(errs...)
+}
+
+// validateBaseURL checks that raw, when set, is an absolute http or https URL
+func validateBaseURL(raw string) error {
+	if raw == "" {
+		return nil
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("%q must use http or https", raw)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("%q has no host", raw)
+	}
+	if u.RawQuery != "" || u.Fragment != "" {
+		return fmt.Errorf("%q must not have a query or fragment", raw)
+	}
+
+	return nil
+}
+
+// validGateway reports whether gateway is a domain, optionally with an http(s)
+// scheme and a trailing slash, as accepted for PinataGateway
+func validGateway(gateway string) bool {
+	host := strings.TrimPrefix(strings.TrimPrefix(gateway, "https://"), "http://")
+	host = strings.TrimSuffix(host, "/")
+	return host != "" && !strings.ContainsAny(host, " \t\r\n/?#@")
+}
+
+// validHeaderName reports whether name is a non-empty HTTP token
+func validHeaderName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, c := range name {
+		if c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' {
+			continue
+		}
+		if !strings.ContainsRune("!#$%&'*+-.^_`|~", c) {
+			return false
+		}
+	}
+	return true
+}
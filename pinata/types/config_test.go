@@ -0,0 +1,67 @@
+package types
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestConfigValidateAcceptsValidConfigs(t *testing.T) {
+	configs := []*Config{
+		{PinataJWT: "jwt"},
+		{
+			PinataJWT:        "jwt",
+			APIUrl:           "https://api.pinata.cloud/v3",
+			UploadUrl:        "http://localhost:8080/v3",
+			PinataGateway:    "https://example.mypinata.cloud/",
+			FallbackGateways: []string{"gateway.pinata.cloud"},
+			CustomHeaders:    map[string]string{"X-Trace-Id": "abc"},
+		},
+	}
+	for _, cfg := range configs {
+		if err := cfg.Validate(); err != nil {
+			t.Errorf("%+v: %v", cfg, err)
+		}
+	}
+}
+
+func TestConfigValidateRejectsInvalidConfigs(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+		want string
+	}{
+		{"empty JWT", Config{PinataJWT: "  "}, "PinataJWT is empty"},
+		{"relative API URL", Config{PinataJWT: "jwt", APIUrl: "api.pinata.cloud/v3"}, "APIUrl:"},
+		{"unparseable API URL", Config{PinataJWT: "jwt", APIUrl: "https://exa mple.com"}, "APIUrl:"},
+		{"upload URL scheme", Config{PinataJWT: "jwt", UploadUrl: "ftp://uploads.pinata.cloud"}, "UploadUrl:"},
+		{"upload URL query", Config{PinataJWT: "jwt", UploadUrl: "https://uploads.pinata.cloud/v3?x=1"}, "query or fragment"},
+		{"gateway path", Config{PinataJWT: "jwt", PinataGateway: "example.mypinata.cloud/ipfs"}, "PinataGateway"},
+		{"fallback gateway", Config{PinataJWT: "jwt", FallbackGateways: []string{"bad gateway"}}, "fallback gateway"},
+		{"header name", Config{PinataJWT: "jwt", CustomHeaders: map[string]string{"Bad Header": "x"}}, "custom header name"},
+		{"header value", Config{PinataJWT: "jwt", CustomHeaders: map[string]string{"X-Note": "a\r\nb"}}, "line break"},
+		{"negative timeout", Config{PinataJWT: "jwt", Timeout: -1}, "Timeout"},
+		{"negative rate limit", Config{PinataJWT: "jwt", RateLimit: -1}, "RateLimit"},
+	}
+	for _, tt := range tests {
+		err := tt.cfg.Validate()
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: got %v, want an error mentioning %q", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestConfigValidateReportsEveryProblem(t *testing.T) {
+	cfg := Config{APIUrl: "nope", CustomHeaders: map[string]string{"B ad": "x", "A ad": "y"}, CacheSize: -1}
+
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("got no error")
+	}
+	lines := strings.Split(err.Error(), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("got %d problems, want 5:\n%v", len(lines), err)
+	}
+	if !strings.Contains(lines[2], `"A ad"`) || !strings.Contains(lines[3], `"B ad"`) {
+		t.Fatalf("headers not reported in order:\n%v", err)
+	}
+}
@@ -0,0 +1,154 @@
+package types
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// ErrMissingJWT is returned before any authenticated request is sent when the
+// configured JWT is blank
+var ErrMissingJWT = errors.New("pinata: JWT is empty; set PinataJWT")
+
+// ErrMissingGateway is returned by methods that need a gateway when none is configured
+var ErrMissingGateway = errors.New("pinata: gateway is empty; set PinataGateway")
+
+// ErrClientClosed is returned for requests made after Client.Close
+var ErrClientClosed = errors.New("pinata: client is closed")
+
+// APIError is returned when the Pinata API responds with a non-2xx status
+type APIError struct {
+	StatusCode int
+	Body       string
+	// Message and Code are parsed from the body when it is JSON
+	Message string
+	Code    string
+	// RequestID identifies the failed request, for support tickets
+	RequestID string
+}
+
+// NewAPIError creates an APIError from a status code and raw response body
+func NewAPIError(statusCode int, body []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode: statusCode,
+		Body:       string(body),
+	}
+
+	var payload struct {
+		Error   json.RawMessage `json:"error"`
+		Message string          `json:"message"`
+		Code    interface{}     `json:"code"`
+	}
+
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return apiErr
+	}
+
+	apiErr.Message = payload.Message
+	if payload.Code != nil {
+		apiErr.Code = fmt.Sprint(payload.Code)
+	}
+
+	// The error field is either a plain message or an object with a reason
+	if len(payload.Error) > 0 {
+		var message string
+		var detail struct {
+			Reason  string `json:"reason"`
+			Details string `json:"details"`
+		}
+		if err := json.Unmarshal(payload.Error, &message); err == nil {
+			if apiErr.Message == "" {
+				apiErr.Message = message
+			}
+		} else if err := json.Unmarshal(payload.Error, &detail); err == nil {
+			if apiErr.Code == "" {
+				apiErr.Code = detail.Reason
+			}
+			if apiErr.Message == "" {
+				apiErr.Message = detail.Details
+			}
+		}
+	}
+
+	return apiErr
+}
+
+// Error implements the error interface
+func (e *APIError) Error() string {
+	message := e.Body
+	if e.Message != "" {
+		message = e.Message
+	}
+	if e.RequestID != "" {
+		return fmt.Sprintf("API error (status %d, request %s): %s", e.StatusCode, e.RequestID, message)
+	}
+	return fmt.Sprintf("API error (status %d): %s", e.StatusCode, message)
+}
+
+// Is reports whether target is an *APIError with the same status code, so
+// errors.Is(err, &APIError{StatusCode: 404}) matches any not-found error
+func (e *APIError) Is(target error) bool {
+	t, ok := target.(*APIError)
+	if !ok {
+		return false
+	}
+	return t.StatusCode == e.StatusCode
+}
+
+// RateLimitError is returned when the API responds with HTTP 429 and retries are
+// disabled or exhausted
+type RateLimitError struct {
+	*APIError
+	// RetryAfter is the wait requested by the Retry-After header, or zero if the
+	// header was missing or malformed
+	RetryAfter time.Duration
+}
+
+// Error implements the error interface
+func (e *RateLimitError) Error() string {
+	message := e.Body
+	if e.Message != "" {
+		message = e.Message
+	}
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("rate limited (status %d, retry after %s): %s", e.StatusCode, e.RetryAfter, message)
+	}
+	return fmt.Sprintf("rate limited (status %d): %s", e.StatusCode, message)
+}
+
+// Unwrap returns the underlying APIError
+func (e *RateLimitError) Unwrap() error {
+	return e.APIError
+}
+
+// StatusCode returns the HTTP status code carried by err, or zero if err is not
+// an API error
+func StatusCode(err error) int {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode
+	}
+	return 0
+}
+
+// IsNotFound reports whether err is an API error with status 404
+func IsNotFound(err error) bool {
+	return StatusCode(err) == http.StatusNotFound
+}
+
+// IsUnauthorized reports whether err is an API error with status 401
+func IsUnauthorized(err error) bool {
+	return StatusCode(err) == http.StatusUnauthorized
+}
+
+// IsForbidden reports whether err is an API error with status 403
+func IsForbidden(err error) bool {
+	return StatusCode(err) == http.StatusForbidden
+}
+
+// IsRateLimited reports whether err is an API error with status 429
+func IsRateLimited(err error) bool {
+	return StatusCode(err) == http.StatusTooManyRequests
+}
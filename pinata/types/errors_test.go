@@ -0,0 +1,59 @@
+package types
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNewAPIErrorParsesMessage(t *testing.T) {
+	tests := []struct {
+		body    string
+		message string
+		code    string
+	}{
+		{`{"error":"Not found"}`, "Not found", ""},
+		{`{"message":"bad input","code":400}`, "bad input", "400"},
+		{`{"error":{"reason":"INVALID_CREDENTIALS","details":"token expired"}}`, "token expired", "INVALID_CREDENTIALS"},
+		{`not json`, "", ""},
+	}
+
+	for _, tt := range tests {
+		apiErr := NewAPIError(400, []byte(tt.body))
+		if apiErr.Message != tt.message || apiErr.Code != tt.code {
+			t.Errorf("%s: got message %q code %q, want %q %q", tt.body, apiErr.Message, apiErr.Code, tt.message, tt.code)
+		}
+	}
+}
+
+func TestAPIErrorMessage(t *testing.T) {
+	if got := NewAPIError(404, []byte(`{"error":"Not found"}`)).Error(); got != "API error (status 404): Not found" {
+		t.Fatalf("got %q", got)
+	}
+	if got := NewAPIError(502, []byte("bad gateway")).Error(); got != "API error (status 502): bad gateway" {
+		t.Fatalf("got %q", got)
+	}
+}
+
+func TestRateLimitErrorMessage(t *testing.T) {
+	rateErr := &RateLimitError{
+		APIError:   NewAPIError(429, []byte(`{"error":"Too many requests"}`)),
+		RetryAfter: 2 * time.Second,
+	}
+
+	if got := rateErr.Error(); got != "rate limited (status 429, retry after 2s): Too many requests" {
+		t.Fatalf("got %q", got)
+	}
+	if StatusCode(rateErr) != 429 {
+		t.Fatalf("got status %d, want 429", StatusCode(rateErr))
+	}
+	if !errors.Is(rateErr, &APIError{StatusCode: 429}) {
+		t.Fatal("rate limit error does not match a 429 APIError")
+	}
+
+	rateErr.APIError = NewAPIError(429, []byte("slow down"))
+	rateErr.RetryAfter = 0
+	if got := rateErr.Error(); got != "rate limited (status 429): slow down" {
+		t.Fatalf("got %q", got)
+	}
+}
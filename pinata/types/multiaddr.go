@@ -0,0 +1,158 @@
+package types
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// InvalidHostNodesError lists the host node entries that are not valid
+// multiaddrs, each with the reason it was rejected
+type InvalidHostNodesError struct {
+	Invalid []InvalidHostNode
+}
+
+// InvalidHostNode is one rejected host node entry
+type InvalidHostNode struct {
+	Addr   string
+	Reason string
+}
+
+// Error implements the error interface
+func (e *InvalidHostNodesError) Error() string {
+	entries := make([]string, len(e.Invalid))
+	for i, node := range e.Invalid {
+		entries[i] = fmt.Sprintf("%q (%s)", node.Addr, node.Reason)
+	}
+	return fmt.Sprintf("invalid host nodes: %s", strings.Join(entries, ", "))
+}
+
+// ValidateHostNodes checks that every entry looks like a multiaddr such as
+// /ip4/203.0.113.7/tcp/4001/p2p/12D3KooW... and returns an
+// *InvalidHostNodesError listing every entry that does not
+func ValidateHostNodes(nodes []string) error {
+	var invalid []InvalidHostNode
+	for _, node := range nodes {
+		if err := checkMultiaddr(node); err != nil {
+			invalid = append(invalid, InvalidHostNode{Addr: node, Reason: err.Error()})
+		}
+	}
+
+	if len(invalid) > 0 {
+		return &InvalidHostNodesError{Invalid: invalid}
+	}
+	return nil
+}
+
+// multiaddrProtocols maps the protocol names accepted in a host node to a check
+// for their value, or nil for protocols that take no value
+var multiaddrProtocols = map[string]func(string) error{
+	"ip4":           checkIP4,
+	"ip6":           checkIP6,
+	"dns":           checkHostName,
+	"dns4":          checkHostName,
+	"dns6":          checkHostName,
+	"dnsaddr":       checkHostName,
+	"tcp":           checkPort,
+	"udp":           checkPort,
+	"p2p":           checkPeerID,
+	"ipfs":          checkPeerID,
+	"quic":          nil,
+	"quic-v1":       nil,
+	"ws":            nil,
+	"wss":           nil,
+	"tls":           nil,
+	"noise":         nil,
+	"http":          nil,
+	"https":         nil,
+	"webtransport":  nil,
+	"webrtc":        nil,
+	"webrtc-direct": nil,
+	"p2p-circuit":   nil,
+}
+
+// checkMultiaddr parses addr as a sequence of /protocol[/value] components
+func checkMultiaddr(addr string) error {
+	if !strings.HasPrefix(addr, "/") {
+		return fmt.Errorf("must start with /")
+	}
+
+	parts := strings.Split(addr[1:], "/")
+	for i := 0; i < len(parts); i++ {
+		name := parts[i]
+		if name == "" {
+			return fmt.Errorf("empty component")
+		}
+
+		check, ok := multiaddrProtocols[name]
+		if !ok {
+			return fmt.Errorf("unknown protocol %s", name)
+		}
+		if check == nil {
+			continue
+		}
+
+		i++
+		if i >= len(parts) || parts[i] == "" {
+			return fmt.Errorf("missing value for %s", name)
+		}
+		if err := check(parts[i]); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+func checkIP4(value string) error {
+	if ip := net.ParseIP(value); ip == nil || ip.To4() == nil || strings.Contains(value, ":") {
+		return fmt.Errorf("%q is not an IPv4 address", value)
+	}
+	return nil
+}
+
+func checkIP6(value string) error {
+	if ip := net.ParseIP(value); ip == nil || !strings.Contains(value, ":") {
+		return fmt.Errorf("%q is not an IPv6 address", value)
+	}
+	return nil
+}
+
+func checkHostName(value string) error {
+	for _, label := range strings.Split(value, ".") {
+		if label == "" || len(label) > 63 || strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-") {
+			return fmt.Errorf("%q is not a valid host name", value)
+		}
+		for _, c := range label {
+			if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-') {
+				return fmt.Errorf("%q is not a valid host name", value)
+			}
+		}
+	}
+	return nil
+}
+
+func checkPort(value string) error {
+	if port, err := strconv.ParseUint(value, 10, 16); err != nil || port == 0 {
+		return fmt.Errorf("%q is not a valid port", value)
+	}
+	return nil
+}
+
+// checkPeerID accepts base58btc peer IDs (Qm... and 12D3Koo...) and base32
+// CIDv1 peer IDs (b...)
+func checkPeerID(value string) error {
+	const base58 = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+	const base32 = "abcdefghijklmnopqrstuvwxyz234567"
+
+	alphabet := base58
+	if strings.HasPrefix(value, "b") && strings.Trim(value, base32) == "" {
+		alphabet = base32
+	}
+
+	if len(value) < 32 || strings.Trim(value, alphabet) != "" {
+		return fmt.Errorf("%q is not a valid peer ID", value)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package types
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const testPeerID = "12D3KooWGRUVh7BmGHUBS1nqXvsfNMkjwQH9ASBSHBDYfJLVuV7d"
+
+func TestValidateHostNodesAcceptsMultiaddrs(t *testing.T) {
+	valid := []string{
+		"/ip4/203.0.113.7/tcp/4001/p2p/" + testPeerID,
+		"/ip6/2001:db8::1/udp/4001/quic-v1/p2p/" + testPeerID,
+		"/dns4/node.example.com/tcp/443/wss/p2p/" + testPeerID,
+		"/dnsaddr/bootstrap.libp2p.io/ipfs/QmNnooDu7bfjPFoTZYxMNLWUQJyrVwtbZg5gBMjTezGAJN",
+		"/p2p/bafzbeigweq4zr4x4ky2dvv7nanbkw6egutvrrvzw6g3h2rftp7gidyhtt4",
+	}
+	if err := ValidateHostNodes(valid); err != nil {
+		t.Fatal(err)
+	}
+	if err := ValidateHostNodes(nil); err != nil {
+		t.Fatalf("no nodes: %v", err)
+	}
+}
+
+func TestValidateHostNodesListsInvalidEntries(t *testing.T) {
+	nodes := []string{
+		"/ip4/203.0.113.7/tcp/4001/p2p/" + testPeerID,
+		"ip4/203.0.113.7",
+		"/ip4/300.0.0.1/tcp/4001",
+		"/ip6/203.0.113.7",
+		"/ip4/203.0.113.7/tcp/70000",
+		"/ip4/203.0.113.7/tcp",
+		"/smtp/25",
+		"/ip4/203.0.113.7//tcp/1",
+		"/dns4/bad_host/tcp/1",
+		"/p2p/notapeer",
+	}
+	err := ValidateHostNodes(nodes)
+
+	var invalid *InvalidHostNodesError
+	if !errors.As(err, &invalid) {
+		t.Fatalf("got %v, want an *InvalidHostNodesError", err)
+	}
+	if len(invalid.Invalid) != len(nodes)-1 {
+		t.Fatalf("rejected %d entries, want %d: %v", len(invalid.Invalid), len(nodes)-1, err)
+	}
+	for i, node := range invalid.Invalid {
+		if node.Addr != nodes[i+1] || node.Reason == "" {
+			t.Errorf("entry %d: got %+v", i, node)
+		}
+	}
+
+	reasons := []string{"must start with /", "not an IPv4 address", "not an IPv6 address", "not a valid port",
+		"missing value for tcp", "unknown protocol smtp", "empty component", "not a valid host name", "not a valid peer ID"}
+	for i, reason := range reasons {
+		if !strings.Contains(invalid.Invalid[i].Reason, reason) {
+			t.Errorf("%s: reason %q, want %q", invalid.Invalid[i].Addr, invalid.Invalid[i].Reason, reason)
+		}
+	}
+	if !strings.Contains(err.Error(), `"/smtp/25" (unknown protocol smtp)`) {
+		t.Errorf("message %q does not name the entry and reason", err)
+	}
+}
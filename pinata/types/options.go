@@ -0,0 +1,68 @@
+package types
+
+import (
+	"context"
+	"net/http"
+)
+
+// RequestOption customizes a single outgoing request
+type RequestOption func(*http.Request)
+
+// WithRequestHeader returns a RequestOption that sets a header on the request,
+// overriding any custom header from the Config with the same key
+func WithRequestHeader(key string, value string) RequestOption {
+	return func(req *http.Request) {
+		req.Header.Set(key, value)
+	}
+}
+
+type requestOptionsKey struct{}
+
+// WithRequestOptions returns a copy of ctx carrying opts. Every request made by a
+// ...Context method with the returned context has the options applied, leaving
+// the shared Config untouched:
+//
+//	ctx = types.WithRequestOptions(ctx, types.WithRequestHeader("X-Trace-Id", traceID))
+//	resp, err := client.Upload.Public.FileContext(ctx, file, nil)
+func WithRequestOptions(ctx context.Context, opts ...RequestOption) context.Context {
+	existing := RequestOptionsFromContext(ctx)
+	combined := make([]RequestOption, 0, len(existing)+len(opts))
+	combined = append(combined, existing...)
+	combined = append(combined, opts...)
+	return context.WithValue(ctx, requestOptionsKey{}, combined)
+}
+
+// RequestOptionsFromContext returns the request options carried by ctx
+func RequestOptionsFromContext(ctx context.Context) []RequestOption {
+	opts, _ := ctx.Value(requestOptionsKey{}).([]RequestOption)
+	return opts
+}
+
+// ResponseHook is called with the final HTTP response of a request, before its
+// body is consumed. It can be used to read headers such as rate-limit counters or
+// request IDs that the typed results do not expose. Hooks must not read or close
+// the body.
+type ResponseHook func(*http.Response)
+
+type responseHooksKey struct{}
+
+// WithResponseHook returns a copy of ctx carrying hook. Every request made by a
+// ...Context method with the returned context calls it:
+//
+//	var requestID string
+//	ctx = types.WithResponseHook(ctx, func(resp *http.Response) {
+//		requestID = resp.Header.Get("X-Request-Id")
+//	})
+func WithResponseHook(ctx context.Context, hook ResponseHook) context.Context {
+	existing := ResponseHooksFromContext(ctx)
+	combined := make([]ResponseHook, 0, len(existing)+1)
+	combined = append(combined, existing...)
+	combined = append(combined, hook)
+	return context.WithValue(ctx, responseHooksKey{}, combined)
+}
+
+// ResponseHooksFromContext returns the response hooks carried by ctx
+func ResponseHooksFromContext(ctx context.Context) []ResponseHook {
+	hooks, _ := ctx.Value(responseHooksKey{}).([]ResponseHook)
+	return hooks
+}
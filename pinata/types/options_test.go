@@ -0,0 +1,119 @@
+package types_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/PinataCloud/pinata-go-sdk/pinata/files"
+	"github.com/PinataCloud/pinata-go-sdk/pinata/types"
+	"github.com/PinataCloud/pinata-go-sdk/pinata/upload"
+)
+
+func TestRequestHeaderOnlyOnThatRequest(t *testing.T) {
+	var traces []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traces = append(traces, r.URL.Path+"="+r.Header.Get("X-Trace-Id")+","+r.Header.Get("X-Env"))
+		fmt.Fprint(w, `{"data":{"id":"file-1","cid":"bafy"}}`)
+	}))
+	defer srv.Close()
+
+	cfg := &types.Config{
+		PinataJWT:     "jwt",
+		APIUrl:        srv.URL,
+		UploadUrl:     srv.URL,
+		CustomHeaders: map[string]string{"X-Env": "test"},
+	}
+	fileService := files.NewPublicService(cfg)
+	uploadService := upload.NewPublicService(cfg)
+
+	ctx := types.WithRequestOptions(context.Background(),
+		types.WithRequestHeader("X-Trace-Id", "trace-1"),
+		types.WithRequestHeader("X-Env", "override"),
+	)
+
+	if _, err := fileService.GetContext(ctx, "file-1"); err != nil {
+		t.Fatal(err)
+	}
+	data := &upload.FileData{Reader: bytes.NewReader([]byte("hi")), Name: "hi.txt", Size: 2}
+	if _, err := uploadService.FileReaderContext(ctx, data, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fileService.Get("file-1"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "[/files/public/file-1=trace-1,override /files=trace-1,override /files/public/file-1=,test]"
+	if fmt.Sprint(traces) != want {
+		t.Fatalf("got %v, want %s", traces, want)
+	}
+	if len(cfg.CustomHeaders) != 1 || cfg.CustomHeaders["X-Env"] != "test" {
+		t.Fatalf("config headers changed to %v", cfg.CustomHeaders)
+	}
+}
+
+func TestWithRequestOptionsAppends(t *testing.T) {
+	ctx := types.WithRequestOptions(context.Background(), types.WithRequestHeader("A", "1"))
+	ctx = types.WithRequestOptions(ctx, types.WithRequestHeader("B", "2"))
+
+	req := httptest.NewRequest("GET", "/", nil)
+	for _, opt := range types.RequestOptionsFromContext(ctx) {
+		opt(req)
+	}
+	if req.Header.Get("A") != "1" || req.Header.Get("B") != "2" {
+		t.Fatalf("got headers %v", req.Header)
+	}
+}
+
+func TestRequestIDCaptured(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/files/public/file-1":
+			w.Header().Set("X-Request-Id", "req-ok")
+			fmt.Fprint(w, `{"data":{"id":"file-1"}}`)
+		case "/files/public/missing":
+			w.Header().Set("X-Request-Id", "req-failed")
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error":"not found"}`)
+		default:
+			w.Header().Set("Cf-Ray", "ray-1")
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer srv.Close()
+
+	service := files.NewPublicService(&types.Config{PinataJWT: "jwt", APIUrl: srv.URL})
+
+	// Success: read from the response through a hook
+	var requestID string
+	ctx := types.WithResponseHook(context.Background(), func(resp *http.Response) {
+		requestID = resp.Header.Get("X-Request-Id")
+	})
+	if _, err := service.GetContext(ctx, "file-1"); err != nil {
+		t.Fatal(err)
+	}
+	if requestID != "req-ok" {
+		t.Fatalf("hook saw request ID %q, want req-ok", requestID)
+	}
+
+	// Error: carried on the APIError and in its message
+	_, err := service.Get("missing")
+	var apiErr *types.APIError
+	if !errors.As(err, &apiErr) || apiErr.RequestID != "req-failed" {
+		t.Fatalf("got %v, want an APIError with request ID req-failed", err)
+	}
+	if !strings.Contains(err.Error(), "req-failed") {
+		t.Fatalf("error %q does not mention the request ID", err)
+	}
+
+	// Without X-Request-Id the Cloudflare ray ID is used
+	_, err = service.Get("other")
+	if !errors.As(err, &apiErr) || apiErr.RequestID != "ray-1" {
+		t.Fatalf("got %v, want an APIError with request ID ray-1", err)
+	}
+}
@@ -0,0 +1,69 @@
+package types
+
+import (
+	"math"
+	"time"
+)
+
+// DefaultRetryBaseDelay is the delay before the first retry when a policy leaves
+// BaseDelay zero
+const DefaultRetryBaseDelay = 500 * time.Millisecond
+
+// DefaultRetryMultiplier is the growth factor between retries when a policy
+// leaves Multiplier zero
+const DefaultRetryMultiplier = 2.0
+
+// RetryPolicy controls how requests are retried after a transient failure
+type RetryPolicy struct {
+	// MaxRetries is the number of retries after the first attempt. Zero disables retries.
+	MaxRetries int
+	// BaseDelay is the delay before the first retry. Zero uses DefaultRetryBaseDelay.
+	BaseDelay time.Duration
+	// MaxDelay caps the delay before any retry, jitter and a server's
+	// Retry-After included. Zero means no cap.
+	MaxDelay time.Duration
+	// Multiplier grows the delay after each retry. Zero uses DefaultRetryMultiplier.
+	Multiplier float64
+	// Jitter adds up to this fraction of the delay at random, so clients that
+	// failed together do not retry together. Zero disables jitter.
+	Jitter float64
+}
+
+// DefaultRetryPolicy is a reasonable policy for most applications: three retries
+// starting at half a second, doubling up to 30 seconds, with up to 50% jitter
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries: 3,
+	BaseDelay:  DefaultRetryBaseDelay,
+	MaxDelay:   30 * time.Second,
+	Multiplier: DefaultRetryMultiplier,
+	Jitter:     0.5,
+}
+
+// Delay returns the wait before retry number attempt, counting from zero. random
+// is a value in [0, 1) that picks the jitter, which keeps Delay deterministic.
+func (p RetryPolicy) Delay(attempt int, random float64) time.Duration {
+	base := p.BaseDelay
+	if base <= 0 {
+		base = DefaultRetryBaseDelay
+	}
+	multiplier := p.Multiplier
+	if multiplier <= 0 {
+		multiplier = DefaultRetryMultiplier
+	}
+	if attempt < 0 {
+		attempt = 0
+	}
+
+	delay := float64(base) * math.Pow(multiplier, float64(attempt))
+	if p.Jitter > 0 {
+		delay += delay * p.Jitter * random
+	}
+
+	if p.MaxDelay > 0 && delay > float64(p.MaxDelay) {
+		return p.MaxDelay
+	}
+	if delay >= math.MaxInt64 {
+		return math.MaxInt64
+	}
+	return time.Duration(delay)
+}
@@ -0,0 +1,70 @@
+package types
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicyDelayProgression(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: 100 * time.Millisecond, Multiplier: 3}
+	want := []time.Duration{100 * time.Millisecond, 300 * time.Millisecond, 900 * time.Millisecond, 2700 * time.Millisecond}
+	for attempt, w := range want {
+		if got := policy.Delay(attempt, 0.99); got != w {
+			t.Errorf("attempt %d: got %s, want %s", attempt, got, w)
+		}
+	}
+
+	// Zero fields use the defaults and a negative attempt counts as the first
+	defaults := RetryPolicy{}
+	if got := defaults.Delay(-1, 0); got != DefaultRetryBaseDelay {
+		t.Errorf("defaults: got %s, want %s", got, DefaultRetryBaseDelay)
+	}
+	if got, want := defaults.Delay(2, 0), time.Duration(float64(DefaultRetryBaseDelay)*DefaultRetryMultiplier*DefaultRetryMultiplier); got != want {
+		t.Errorf("defaults attempt 2: got %s, want %s", got, want)
+	}
+}
+
+func TestRetryPolicyDelayJitter(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: time.Second, Multiplier: 2, Jitter: 0.5}
+	tests := []struct {
+		random float64
+		want   time.Duration
+	}{
+		{0, 2 * time.Second},
+		{0.5, 2500 * time.Millisecond},
+		{0.999, 2*time.Second + 999*time.Millisecond},
+	}
+	for _, tt := range tests {
+		if got := policy.Delay(1, tt.random); got != tt.want {
+			t.Errorf("random %v: got %s, want %s", tt.random, got, tt.want)
+		}
+	}
+}
+
+func TestRetryPolicyDelayCap(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: time.Second, Multiplier: 2, MaxDelay: 5 * time.Second, Jitter: 1}
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	for attempt, w := range want {
+		if got := policy.Delay(attempt, 0); got != w {
+			t.Errorf("attempt %d: got %s, want %s", attempt, got, w)
+		}
+	}
+	// The cap includes the jitter
+	if got := policy.Delay(2, 0.9); got != 5*time.Second {
+		t.Errorf("jittered: got %s, want the cap", got)
+	}
+
+	// Without a cap a huge attempt saturates instead of overflowing
+	uncapped := RetryPolicy{BaseDelay: time.Second, Multiplier: 10}
+	if got := uncapped.Delay(1000, 0); got != math.MaxInt64 {
+		t.Errorf("overflow: got %s", got)
+	}
+}
+
+func TestDefaultRetryPolicy(t *testing.T) {
+	p := DefaultRetryPolicy
+	if p.MaxRetries != 3 || p.Delay(0, 0) != 500*time.Millisecond || p.Delay(10, 0) != 30*time.Second {
+		t.Fatalf("got %+v", p)
+	}
+}
@@ -0,0 +1,65 @@
+package types
+
+import (
+	"fmt"
+	"time"
+)
+
+// timeLayouts are the timestamp formats used by the API, most common first
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999Z07",
+	"2006-01-02 15:04:05.999999999",
+}
+
+// ParseTime parses a timestamp returned by the API, such as File.CreatedAt
+func ParseTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, fmt.Errorf("timestamp is empty")
+	}
+
+	for _, layout := range timeLayouts {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return parsed, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("unrecognised timestamp %q", value)
+}
+
+// CreatedAtTime parses CreatedAt
+func (r *File) CreatedAtTime() (time.Time, error) {
+	return ParseTime(r.CreatedAt)
+}
+
+// CreatedAtTime parses CreatedAt
+func (r *UploadResponse) CreatedAtTime() (time.Time, error) {
+	return ParseTime(r.CreatedAt)
+}
+
+// CreatedAtTime parses CreatedAt
+func (r *Group) CreatedAtTime() (time.Time, error) {
+	return ParseTime(r.CreatedAt)
+}
+
+// CreatedAtTime parses CreatedAt
+func (r *SwapResponse) CreatedAtTime() (time.Time, error) {
+	return ParseTime(r.CreatedAt)
+}
+
+// CreatedAtTime parses CreatedAt
+func (r *Key) CreatedAtTime() (time.Time, error) {
+	return ParseTime(r.CreatedAt)
+}
+
+// DateQueuedTime parses DateQueued
+func (r *PinByHashResponse) DateQueuedTime() (time.Time, error) {
+	return ParseTime(r.DateQueued)
+}
+
+// DateQueuedTime parses DateQueued
+func (r *PinQueueItem) DateQueuedTime() (time.Time, error) {
+	return ParseTime(r.DateQueued)
+}
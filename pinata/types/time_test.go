@@ -0,0 +1,63 @@
+package types
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTime(t *testing.T) {
+	want := time.Date(2024, 5, 6, 7, 8, 9, 123000000, time.UTC)
+	valid := []string{
+		"2024-05-06T07:08:09.123Z",
+		"2024-05-06T07:08:09.123+00:00",
+		"2024-05-06T09:08:09.123+02:00",
+		"2024-05-06T07:08:09.123",
+		"2024-05-06 07:08:09.123+00",
+		"2024-05-06 07:08:09.123+00:00",
+		"2024-05-06 07:08:09.123",
+	}
+	for _, value := range valid {
+		got, err := ParseTime(value)
+		if err != nil {
+			t.Errorf("%s: %v", value, err)
+			continue
+		}
+		if !got.Equal(want) {
+			t.Errorf("%s: got %s, want %s", value, got, want)
+		}
+	}
+
+	for _, value := range []string{"", "yesterday", "2024-13-01T00:00:00Z", "1715000000"} {
+		if _, err := ParseTime(value); err == nil {
+			t.Errorf("%q: got no error", value)
+		}
+	}
+}
+
+func TestCreatedAtTime(t *testing.T) {
+	const stamp = "2024-05-06T07:08:09Z"
+	want := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+
+	parsers := map[string]func() (time.Time, error){
+		"File":              (&File{CreatedAt: stamp}).CreatedAtTime,
+		"UploadResponse":    (&UploadResponse{CreatedAt: stamp}).CreatedAtTime,
+		"Group":             (&Group{CreatedAt: stamp}).CreatedAtTime,
+		"SwapResponse":      (&SwapResponse{CreatedAt: stamp}).CreatedAtTime,
+		"Key":               (&Key{CreatedAt: stamp}).CreatedAtTime,
+		"PinByHashResponse": (&PinByHashResponse{DateQueued: stamp}).DateQueuedTime,
+		"PinQueueItem":      (&PinQueueItem{DateQueued: stamp}).DateQueuedTime,
+	}
+	for name, parse := range parsers {
+		got, err := parse()
+		if err != nil || !got.Equal(want) {
+			t.Errorf("%s: got %s, %v", name, got, err)
+		}
+	}
+
+	if _, err := (&File{}).CreatedAtTime(); err == nil {
+		t.Error("empty CreatedAt: got no error")
+	}
+	if _, err := (&Group{CreatedAt: "not a time"}).CreatedAtTime(); err == nil {
+		t.Error("malformed CreatedAt: got no error")
+	}
+}
@@ -1,5 +1,11 @@
 package types
 
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
 // Config holds the configuration for the Pinata SDK client
 type Config struct {
 	PinataJWT        string
@@ -8,6 +14,75 @@ type Config struct {
 	CustomHeaders    map[string]string
 	APIUrl           string
 	UploadUrl        string
+
+	// HTTPClient is used to send requests when set. Timeout, if non-zero, takes
+	// precedence over the client's own timeout.
+	HTTPClient *http.Client
+
+	// DisableKeepAlives closes each connection after its request, sending
+	// Connection: close, for networks where persistent connections break. It
+	// applies to a custom HTTPClient too, without changing its transport.
+	DisableKeepAlives bool
+
+	// Timeout limits the total time of each HTTP request, including streaming the
+	// upload body and reading the response. Zero disables the timeout, which is
+	// useful for large FileArray uploads. When a ...Context method is used, the
+	// request is cancelled by whichever of the context or the timeout fires first.
+	Timeout time.Duration
+
+	// MaxRetries is the number of times a request is retried after a transient
+	// failure (a dropped connection or a 502, 503 or 504 response). Zero disables
+	// retries. Idempotent requests and uploads are retried; other requests are not.
+	MaxRetries int
+
+	// RetryBaseDelay is the delay before the first retry. Each further retry doubles
+	// it and adds random jitter. Zero uses a default of 500ms.
+	RetryBaseDelay time.Duration
+
+	// Retry, when set, controls retries in full and takes precedence over
+	// MaxRetries and RetryBaseDelay. See DefaultRetryPolicy.
+	Retry *RetryPolicy
+
+	// UserAgent is sent as the User-Agent header of every request. Empty uses
+	// DefaultUserAgent.
+	UserAgent string
+
+	// Logger receives a line for every request sent and response received, with
+	// credentials redacted. Nil disables logging unless Debug is set.
+	Logger Logger
+
+	// Debug logs requests to stderr when no Logger is set
+	Debug bool
+
+	// RateLimit caps the number of requests started per second, including
+	// retries, across every service sharing this Config. Zero means unlimited.
+	RateLimit float64
+
+	// CacheSize enables an in-memory LRU cache of up to this many Files.Get
+	// results. Update and Delete invalidate the entries they affect. Zero
+	// disables caching.
+	CacheSize int
+
+	// CacheTTL is how long a cached Get result is used. Zero keeps entries
+	// until they are evicted or invalidated.
+	CacheTTL time.Duration
+
+	// DefaultPublicGroupID and DefaultPrivateGroupID are the groups file uploads
+	// to each network go into when FileOptions has no GroupID or GroupName. Set
+	// FileOptions.NoGroup to upload a file outside the default group.
+	DefaultPublicGroupID  string
+	DefaultPrivateGroupID string
+
+	// FallbackGateways are tried in order when PinataGateway fails to serve a
+	// gateway download, for example with a 502 or a timeout. They take the same
+	// forms as PinataGateway. The gateway key and CustomHeaders are only sent
+	// to PinataGateway.
+	FallbackGateways []string
+}
+
+// Logger is the interface used for request logging. *log.Logger implements it.
+type Logger interface {
+	Printf(format string, v ...interface{})
 }
 
 // File represents a file stored on Pinata
@@ -26,22 +101,70 @@ type File struct {
 	IsDuplicate   bool              `json:"is_duplicate,omitempty"`
 }
 
+// UnmarshalJSON decodes a File, also accepting numberOfFiles for NumberOfFiles
+// since some endpoints use that spelling
+func (f *File) UnmarshalJSON(data []byte) error {
+	type plain File
+	var decoded struct {
+		plain
+		NumberOfFilesCamel *int `json:"numberOfFiles"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	*f = File(decoded.plain)
+	if f.NumberOfFiles == 0 && decoded.NumberOfFilesCamel != nil {
+		f.NumberOfFiles = *decoded.NumberOfFilesCamel
+	}
+	return nil
+}
+
 // FileListResponse represents the response for listing files
 type FileListResponse struct {
 	Files         []File `json:"files"`
 	NextPageToken string `json:"next_page_token"`
 }
 
+// HasMore reports whether another page can be fetched with NextPageToken
+func (r *FileListResponse) HasMore() bool {
+	return r != nil && r.NextPageToken != ""
+}
+
 // DeleteResponse represents the response for deleting a file
 type DeleteResponse struct {
 	ID     string `json:"id"`
 	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// DeleteByCIDResponse represents the result of deleting the files with one CID.
+// IDs lists the files that were deleted, which may be only some of them on failure.
+type DeleteByCIDResponse struct {
+	CID    string   `json:"cid"`
+	IDs    []string `json:"ids"`
+	Status string   `json:"status"`
+	Error  string   `json:"error,omitempty"`
+}
+
+// UpdateBatchResponse represents the result of updating one file in a batch.
+// File is the updated record when Status is "updated".
+type UpdateBatchResponse struct {
+	ID     string `json:"id"`
+	File   *File  `json:"file,omitempty"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
 }
 
 // SwapResponse represents a CID swap record
 type SwapResponse struct {
 	MappedCID string `json:"mapped_cid"`
 	CreatedAt string `json:"created_at"`
+	// CID is the original CID the swap applies to and Domain the gateway it was
+	// looked up on. The API does not echo them, so they are filled in from the
+	// request when missing.
+	CID    string `json:"cid,omitempty"`
+	Domain string `json:"domain,omitempty"`
 }
 
 // PinByHashResponse represents the response for pinning by hash
@@ -74,6 +197,11 @@ type PinQueueResponse struct {
 	NextPageToken string         `json:"next_page_token"`
 }
 
+// HasMore reports whether another page can be fetched with NextPageToken
+func (r *PinQueueResponse) HasMore() bool {
+	return r != nil && r.NextPageToken != ""
+}
+
 // AccessLinkOptions represents options for creating an access link
 type AccessLinkOptions struct {
 	CID     string
@@ -87,11 +215,25 @@ type VectorizeResponse struct {
 	Status bool `json:"status"`
 }
 
+// VectorizeBatchResponse represents the result of vectorizing one file in a batch
+type VectorizeBatchResponse struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
 // VectorQueryOptions represents options for querying vectors
 type VectorQueryOptions struct {
 	GroupID    string
 	Query      string
 	ReturnFile bool
+	// Limit caps the number of matches returned. Zero uses the API default.
+	Limit int
+	// MinScore drops matches scoring below it. Zero keeps every match.
+	MinScore float64
+	// IncludeMetadata fetches each matched file and sets VectorMatch.File, so
+	// names and keyvalues are available without further lookups
+	IncludeMetadata bool
 }
 
 // VectorMatch represents a match from a vector query
@@ -99,6 +241,8 @@ type VectorMatch struct {
 	FileID string  `json:"file_id"`
 	CID    string  `json:"cid"`
 	Score  float64 `json:"score"`
+	// File holds the matched file's metadata when VectorQueryOptions.IncludeMetadata is set
+	File *File `json:"-"`
 }
 
 // VectorQueryResponse represents the response for a vector query
@@ -123,6 +267,18 @@ type GroupListResponse struct {
 	NextPageToken string  `json:"next_page_token"`
 }
 
+// HasMore reports whether another page can be fetched with NextPageToken
+func (r *GroupListResponse) HasMore() bool {
+	return r != nil && r.NextPageToken != ""
+}
+
+// GroupFileResponse represents the result of adding or removing a file from a group
+type GroupFileResponse struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
 // UploadResponse represents the response from an upload
 type UploadResponse struct {
 	ID            string            `json:"id"`
@@ -139,6 +295,51 @@ type UploadResponse struct {
 	IsDuplicate   bool              `json:"is_duplicate,omitempty"`
 }
 
+// UnmarshalJSON decodes a UploadResponse, also accepting numberOfFiles for NumberOfFiles
+// since some endpoints use that spelling
+func (f *UploadResponse) UnmarshalJSON(data []byte) error {
+	type plain UploadResponse
+	var decoded struct {
+		plain
+		NumberOfFilesCamel *int `json:"numberOfFiles"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	*f = UploadResponse(decoded.plain)
+	if f.NumberOfFiles == 0 && decoded.NumberOfFilesCamel != nil {
+		f.NumberOfFiles = *decoded.NumberOfFilesCamel
+	}
+	return nil
+}
+
+// WasDeduplicated reports whether the content was already stored, in which case
+// no new file was created and the response describes the existing one
+func (r *UploadResponse) WasDeduplicated() bool {
+	return r != nil && r.IsDuplicate
+}
+
+// Usage represents the storage used by an account
+type Usage struct {
+	FileCount int64
+	TotalSize int64
+	// TotalSizeWithReplications counts every replica of the stored data
+	TotalSizeWithReplications int64
+}
+
+// RateLimitStatus is the API rate limit as reported by the x-ratelimit-limit,
+// x-ratelimit-remaining and x-ratelimit-reset headers of the latest response
+// that carried them. Fields whose header was missing are left zero.
+type RateLimitStatus struct {
+	Limit     int
+	Remaining int
+	// Reset is when the current window ends and Remaining goes back to Limit
+	Reset time.Time
+	// UpdatedAt is when the response carrying these values was received
+	UpdatedAt time.Time
+}
+
 // Key represents an API key
 type Key struct {
 	ID        string    `json:"id"`
@@ -154,6 +355,19 @@ type Key struct {
 	UpdatedAt string    `json:"updatedAt"`
 }
 
+// RemainingUses reports how many more times the key can be used. A MaxUses of
+// zero means the key is unlimited, in which case unlimited is true and remaining
+// is zero. A limited key that has been used up has zero remaining.
+func (k *Key) RemainingUses() (remaining int, unlimited bool) {
+	if k.MaxUses <= 0 {
+		return 0, true
+	}
+	if k.Uses >= k.MaxUses {
+		return 0, false
+	}
+	return k.MaxUses - k.Uses, false
+}
+
 // KeyScopes represents the scopes for an API key
 type KeyScopes struct {
 	Admin     bool `json:"admin"`
@@ -171,6 +385,14 @@ type KeyListResponse struct {
 	Count int   `json:"count"`
 }
 
+// CreateKeyResponse represents the credentials returned when creating an API key.
+// The secret and JWT are only available at creation time.
+type CreateKeyResponse struct {
+	JWT             string `json:"JWT"`
+	PinataAPIKey    string `json:"pinata_api_key"`
+	PinataAPISecret string `json:"pinata_api_secret"`
+}
+
 // SignatureResponse represents a signature for a CID
 type SignatureResponse struct {
 	CID       string `json:"cid"`
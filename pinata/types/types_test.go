@@ -0,0 +1,85 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestHasMore(t *testing.T) {
+	var nilFiles *FileListResponse
+	var nilGroups *GroupListResponse
+	var nilQueue *PinQueueResponse
+
+	tests := []struct {
+		name string
+		got  bool
+		want bool
+	}{
+		{"nil files", nilFiles.HasMore(), false},
+		{"files without token", (&FileListResponse{}).HasMore(), false},
+		{"files with token", (&FileListResponse{NextPageToken: "next"}).HasMore(), true},
+		{"nil groups", nilGroups.HasMore(), false},
+		{"groups without token", (&GroupListResponse{}).HasMore(), false},
+		{"groups with token", (&GroupListResponse{NextPageToken: "next"}).HasMore(), true},
+		{"nil queue", nilQueue.HasMore(), false},
+		{"queue without token", (&PinQueueResponse{}).HasMore(), false},
+		{"queue with token", (&PinQueueResponse{NextPageToken: "next"}).HasMore(), true},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s: HasMore() = %t, want %t", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestUploadResponseNumberOfFiles(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		{"snake case", `{"id":"f","number_of_files":3}`, 3},
+		{"camel case", `{"id":"f","numberOfFiles":4}`, 4},
+		{"both keys", `{"id":"f","number_of_files":2,"numberOfFiles":5}`, 2},
+		{"missing", `{"id":"f"}`, 0},
+	}
+	for _, tt := range tests {
+		var resp UploadResponse
+		if err := json.Unmarshal([]byte(tt.body), &resp); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if resp.NumberOfFiles != tt.want || resp.ID != "f" {
+			t.Errorf("%s: got %+v, want %d files", tt.name, resp, tt.want)
+		}
+
+		var file File
+		if err := json.Unmarshal([]byte(tt.body), &file); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if file.NumberOfFiles != tt.want {
+			t.Errorf("%s: File.NumberOfFiles = %d, want %d", tt.name, file.NumberOfFiles, tt.want)
+		}
+	}
+}
+
+func TestKeyRemainingUses(t *testing.T) {
+	tests := []struct {
+		name          string
+		key           Key
+		wantRemaining int
+		wantUnlimited bool
+	}{
+		{"unlimited", Key{MaxUses: 0, Uses: 42}, 0, true},
+		{"negative max", Key{MaxUses: -1}, 0, true},
+		{"unused", Key{MaxUses: 10}, 10, false},
+		{"partly used", Key{MaxUses: 10, Uses: 3}, 7, false},
+		{"used up", Key{MaxUses: 10, Uses: 10}, 0, false},
+		{"overused", Key{MaxUses: 10, Uses: 12}, 0, false},
+	}
+	for _, tt := range tests {
+		remaining, unlimited := tt.key.RemainingUses()
+		if remaining != tt.wantRemaining || unlimited != tt.wantUnlimited {
+			t.Errorf("%s: got %d, %t, want %d, %t", tt.name, remaining, unlimited, tt.wantRemaining, tt.wantUnlimited)
+		}
+	}
+}
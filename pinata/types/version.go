@@ -0,0 +1,7 @@
+package types
+
+// Version is the version of the SDK, sent in the default User-Agent header
+const Version = "0.1.0"
+
+// DefaultUserAgent is the User-Agent sent when Config.UserAgent is empty
+const DefaultUserAgent = "pinata-go-sdk/" + Version
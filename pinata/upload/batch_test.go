@@ -0,0 +1,108 @@
+package upload
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/PinataCloud/pinata-go-sdk/pinata/types"
+)
+
+// concurrencyServer answers uploads slowly, echoing the file name as the ID, and
+// records the most uploads it saw in flight at once. Files named fail.txt are
+// rejected.
+type concurrencyServer struct {
+	mu       sync.Mutex
+	inFlight int
+	max      int
+}
+
+func (c *concurrencyServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.mu.Lock()
+	c.inFlight++
+	c.max = max(c.max, c.inFlight)
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		c.inFlight--
+		c.mu.Unlock()
+	}()
+
+	_, header, err := r.FormFile("file")
+	if err != nil || header.Filename == "fail.txt" {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"error":"rejected"}`)
+		return
+	}
+	time.Sleep(20 * time.Millisecond)
+	fmt.Fprintf(w, `{"data":{"id":%q,"cid":"bafy"}}`, header.Filename)
+}
+
+func openNamedFiles(t *testing.T, names ...string) []*os.File {
+	t.Helper()
+
+	dir := t.TempDir()
+	files := make([]*os.File, len(names))
+	for i, name := range names {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(name), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		file, err := os.Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { file.Close() })
+		files[i] = file
+	}
+	return files
+}
+
+func TestFileBatchBoundsConcurrency(t *testing.T) {
+	c := &concurrencyServer{}
+	cfg := newTestConfig(t, c.ServeHTTP)
+
+	var names []string
+	for i := 0; i < 10; i++ {
+		names = append(names, fmt.Sprintf("file-%d.txt", i))
+	}
+	files := openNamedFiles(t, names...)
+
+	responses, errs := NewPublicService(cfg).FileBatch(files, nil, 3)
+
+	for i, name := range names {
+		if errs[i] != nil {
+			t.Fatalf("%s: %v", name, errs[i])
+		}
+		if responses[i] == nil || responses[i].ID != name {
+			t.Fatalf("response %d is %+v, want ID %s", i, responses[i], name)
+		}
+	}
+	if c.max > 3 {
+		t.Fatalf("%d uploads in flight at once, want at most 3", c.max)
+	}
+	if c.max < 2 {
+		t.Fatalf("uploads ran one at a time")
+	}
+}
+
+func TestFileBatchPerFileErrors(t *testing.T) {
+	cfg := newTestConfig(t, (&concurrencyServer{}).ServeHTTP)
+
+	files := openNamedFiles(t, "a.txt", "fail.txt", "b.txt")
+	responses, errs := NewPrivateService(cfg).FileBatch(files, &FileOptions{FileName: "ignored"}, 0)
+
+	if errs[0] != nil || errs[2] != nil {
+		t.Fatalf("got errors %v", errs)
+	}
+	if responses[0].ID != "a.txt" || responses[2].ID != "b.txt" {
+		t.Fatalf("got responses %+v and %+v", responses[0], responses[2])
+	}
+	if responses[1] != nil || types.StatusCode(errs[1]) != http.StatusBadRequest {
+		t.Fatalf("failed file got %+v, %v", responses[1], errs[1])
+	}
+}
@@ -0,0 +1,198 @@
+package upload
+
+import (
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+
+	"github.com/PinataCloud/pinata-go-sdk/pinata/types"
+)
+
+// cidBlockSize is the largest content stored as a single IPFS block. Larger
+// content is split into a DAG whose layout depends on the chunker the upload
+// service uses, so its CID cannot be reproduced reliably.
+const cidBlockSize = 256 * 1024
+
+// Multicodec and multihash codes used by the CIDs computed here
+const (
+	codecRaw    = 0x55
+	codecDagPB  = 0x70
+	hashSHA2256 = 0x12
+)
+
+// ErrCIDUnverifiable is returned with the upload response when FileOptions.VerifyCID
+// is set but the CID cannot be computed locally: the content is larger than one
+// block, the reader cannot be read again, or the CID uses an unsupported format
+var ErrCIDUnverifiable = errors.New("CID cannot be verified locally")
+
+// CIDMismatchError is returned with the upload response when FileOptions.VerifyCID
+// is set and the CID returned by the API differs from the one computed locally
+type CIDMismatchError struct {
+	Expected string
+	Actual   string
+}
+
+// Error implements the error interface
+func (e *CIDMismatchError) Error() string {
+	return fmt.Sprintf("uploaded CID %s does not match the locally computed CID %s", e.Actual, e.Expected)
+}
+
+// verifyCID compares resp.CID to the CID of the content returned by reread when
+// opts asks for it. reread may be nil when the content cannot be read again.
+func verifyCID(resp *types.UploadResponse, reread func() (io.Reader, error), opts *FileOptions) error {
+	if opts == nil || !opts.VerifyCID || resp == nil {
+		return nil
+	}
+	if reread == nil {
+		return fmt.Errorf("%w: the content cannot be read again", ErrCIDUnverifiable)
+	}
+
+	r, err := reread()
+	if err != nil {
+		return fmt.Errorf("failed to read content for CID verification: %w", err)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r, cidBlockSize+1))
+	if err != nil {
+		return fmt.Errorf("failed to read content for CID verification: %w", err)
+	}
+	if len(data) > cidBlockSize {
+		return fmt.Errorf("%w: content is larger than %d bytes", ErrCIDUnverifiable, cidBlockSize)
+	}
+
+	expected, err := computeCID(data, resp.CID)
+	if err != nil {
+		return err
+	}
+	if expected != resp.CID {
+		return &CIDMismatchError{Expected: expected, Actual: resp.CID}
+	}
+
+	return nil
+}
+
+// rereader returns a function that rewinds r to its current position so its
+// content can be read again, or nil if r is not an io.Seeker
+func rereader(r io.Reader) func() (io.Reader, error) {
+	seeker, ok := r.(io.ReadSeeker)
+	if !ok {
+		return nil
+	}
+
+	start, err := seeker.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil
+	}
+
+	return func() (io.Reader, error) {
+		if _, err := seeker.Seek(start, io.SeekStart); err != nil {
+			return nil, err
+		}
+		return seeker, nil
+	}
+}
+
+// computeCID returns the CID of single-block content in the format of like, which
+// is either a CIDv0 (base58btc, dag-pb UnixFS file node), or a CIDv1 in base32 with either
+// the raw codec (a raw leaf) or dag-pb, always hashed with sha2-256
+func computeCID(data []byte, like string) (string, error) {
+	if strings.HasPrefix(like, "Qm") {
+		return base58Encode(multihash(unixfsFileNode(data))), nil
+	}
+
+	if !strings.HasPrefix(like, "b") {
+		return "", fmt.Errorf("%w: unsupported CID encoding %q", ErrCIDUnverifiable, like)
+	}
+	decoded, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(strings.ToUpper(like[1:]))
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrCIDUnverifiable, err)
+	}
+
+	version, n := binary.Uvarint(decoded)
+	if n <= 0 || version != 1 {
+		return "", fmt.Errorf("%w: unsupported CID version", ErrCIDUnverifiable)
+	}
+	codec, m := binary.Uvarint(decoded[n:])
+	if m <= 0 {
+		return "", fmt.Errorf("%w: malformed CID", ErrCIDUnverifiable)
+	}
+	if hash, _ := binary.Uvarint(decoded[n+m:]); hash != hashSHA2256 {
+		return "", fmt.Errorf("%w: unsupported hash function", ErrCIDUnverifiable)
+	}
+
+	var block []byte
+	switch codec {
+	case codecRaw:
+		block = data
+	case codecDagPB:
+		block = unixfsFileNode(data)
+	default:
+		return "", fmt.Errorf("%w: unsupported codec 0x%x", ErrCIDUnverifiable, codec)
+	}
+
+	cid := binary.AppendUvarint(nil, 1)
+	cid = binary.AppendUvarint(cid, codec)
+	cid = append(cid, multihash(block)...)
+	return "b" + strings.ToLower(base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(cid)), nil
+}
+
+// multihash returns the sha2-256 multihash of block
+func multihash(block []byte) []byte {
+	sum := sha256.Sum256(block)
+	return append([]byte{hashSHA2256, sha256.Size}, sum[:]...)
+}
+
+// unixfsFileNode encodes a dag-pb node holding data as a single UnixFS file
+func unixfsFileNode(data []byte) []byte {
+	// UnixFS Data message: Type = File, Data, filesize. Empty files leave
+	// Data out entirely, as go-unixfs does.
+	var unixfs []byte
+	unixfs = append(unixfs, 0x08, 0x02)
+	if len(data) > 0 {
+		unixfs = appendBytesField(unixfs, 0x12, data)
+	}
+	unixfs = append(unixfs, 0x18)
+	unixfs = binary.AppendUvarint(unixfs, uint64(len(data)))
+
+	// PBNode with no links and the UnixFS message as its Data field
+	return appendBytesField(nil, 0x0a, unixfs)
+}
+
+// appendBytesField appends a length-delimited protobuf field with the given tag
+func appendBytesField(buf []byte, tag byte, value []byte) []byte {
+	buf = append(buf, tag)
+	buf = binary.AppendUvarint(buf, uint64(len(value)))
+	return append(buf, value...)
+}
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// base58Encode encodes b with the Bitcoin base58 alphabet used by CIDv0
+func base58Encode(b []byte) string {
+	n := new(big.Int).SetBytes(b)
+	radix := big.NewInt(58)
+	mod := new(big.Int)
+
+	var out []byte
+	for n.Sign() > 0 {
+		n.DivMod(n, radix, mod)
+		out = append(out, base58Alphabet[mod.Int64()])
+	}
+	for _, c := range b {
+		if c != 0 {
+			break
+		}
+		out = append(out, base58Alphabet[0])
+	}
+
+	// Digits were produced least significant first
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return string(out)
+}
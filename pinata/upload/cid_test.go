@@ -0,0 +1,75 @@
+package upload
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/PinataCloud/pinata-go-sdk/pinata/types"
+)
+
+// Known CIDs as produced by ipfs add with default settings
+func TestComputeCID(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		like string
+		want string
+	}{
+		{"v0 hello", "hello world\n", "Qm", "QmT78zSuBmuS4z925WZfrqQ1qHaJ56DQaTfyMUF7F8ff5o"},
+		{"v0 empty", "", "Qm", "QmbFMke1KXqnYyBBWxB74N4c5SBnJMVAiMNRcGu6x1AwQH"},
+		{"v1 dag-pb hello", "hello world\n", "bafybeicg2rebjoofv4kbyovkw7af3rpiitvnl6i7ckcywaq6xjcxnc2mby", "bafybeicg2rebjoofv4kbyovkw7af3rpiitvnl6i7ckcywaq6xjcxnc2mby"},
+		{"v1 dag-pb empty", "", "bafybeif7ztnhq65lumvvtr4ekcwd2ifwgm3awq4zfr3srh462rwyinlb4y", "bafybeif7ztnhq65lumvvtr4ekcwd2ifwgm3awq4zfr3srh462rwyinlb4y"},
+		{"v1 raw empty", "", "bafkreihdwdcefgh4dqkjv67uzcmw7ojee6xedzdetojuzjevtenxquvyku", "bafkreihdwdcefgh4dqkjv67uzcmw7ojee6xedzdetojuzjevtenxquvyku"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := computeCID([]byte(tt.data), tt.like)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Fatalf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestComputeCIDUnsupported(t *testing.T) {
+	for _, like := range []string{"zb2rh", "bafyfoo!"} {
+		if _, err := computeCID([]byte("x"), like); !errors.Is(err, ErrCIDUnverifiable) {
+			t.Errorf("%s: got %v, want ErrCIDUnverifiable", like, err)
+		}
+	}
+}
+
+func TestVerifyCID(t *testing.T) {
+	content := []byte("hello world\n")
+	reread := rereader(bytes.NewReader(content))
+	opts := &FileOptions{VerifyCID: true}
+
+	match := &types.UploadResponse{CID: "QmT78zSuBmuS4z925WZfrqQ1qHaJ56DQaTfyMUF7F8ff5o"}
+	if err := verifyCID(match, reread, opts); err != nil {
+		t.Fatalf("matching CID: %v", err)
+	}
+
+	mismatch := &types.UploadResponse{CID: "QmbFMke1KXqnYyBBWxB74N4c5SBnJMVAiMNRcGu6x1AwQH"}
+	var mismatchErr *CIDMismatchError
+	if err := verifyCID(mismatch, reread, opts); !errors.As(err, &mismatchErr) {
+		t.Fatalf("got %v, want a *CIDMismatchError", err)
+	}
+	if mismatchErr.Expected != match.CID {
+		t.Fatalf("expected CID %s, want %s", mismatchErr.Expected, match.CID)
+	}
+
+	if err := verifyCID(match, nil, opts); !errors.Is(err, ErrCIDUnverifiable) {
+		t.Fatalf("without reread: got %v, want ErrCIDUnverifiable", err)
+	}
+
+	large := rereader(io.NewSectionReader(bytes.NewReader(make([]byte, cidBlockSize+1)), 0, cidBlockSize+1))
+	if err := verifyCID(match, large, opts); !errors.Is(err, ErrCIDUnverifiable) {
+		t.Fatalf("large content: got %v, want ErrCIDUnverifiable", err)
+	}
+}
@@ -0,0 +1,138 @@
+package upload
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/PinataCloud/pinata-go-sdk/pinata/types"
+)
+
+// newContentTest serves uploads on /files, recording the uploaded part's file
+// name and content, and a fixed document on /source/doc.txt
+func newContentTest(t *testing.T) (*types.Config, *[]string) {
+	t.Helper()
+
+	var uploaded []string
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/files":
+			file, header, err := r.FormFile("file")
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			content, _ := io.ReadAll(file)
+			uploaded = append(uploaded, header.Filename+":"+string(content))
+			fmt.Fprint(w, `{"data":{"id":"file-1","cid":"bafy"}}`)
+		case "/source/doc.txt":
+			fmt.Fprint(w, "fetched")
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	return cfg, &uploaded
+}
+
+func TestContentUploadsAcceptNilOptions(t *testing.T) {
+	cfg, uploaded := newContentTest(t)
+	public := NewPublicService(cfg)
+	private := NewPrivateService(cfg)
+
+	uploads := []struct {
+		name   string
+		upload func() (*types.UploadResponse, error)
+		want   string
+	}{
+		{"public JSON", func() (*types.UploadResponse, error) { return public.JSON(map[string]int{"a": 1}, nil) }, `data.json:{"a":1}`},
+		{"private JSON", func() (*types.UploadResponse, error) { return private.JSON(map[string]int{"a": 1}, nil) }, `data.json:{"a":1}`},
+		{"public Base64", func() (*types.UploadResponse, error) { return public.Base64("aGk=", nil) }, "file:hi"},
+		{"private Base64", func() (*types.UploadResponse, error) { return private.Base64("aGk=", nil) }, "file:hi"},
+		{"public URL", func() (*types.UploadResponse, error) { return public.URL(cfg.UploadUrl+"/source/doc.txt", nil) }, "doc.txt:fetched"},
+		{"private URL", func() (*types.UploadResponse, error) { return private.URL(cfg.UploadUrl+"/source/doc.txt", nil) }, "doc.txt:fetched"},
+	}
+
+	for _, tt := range uploads {
+		t.Run(tt.name, func(t *testing.T) {
+			*uploaded = nil
+			if _, err := tt.upload(); err != nil {
+				t.Fatal(err)
+			}
+			if len(*uploaded) != 1 || (*uploaded)[0] != tt.want {
+				t.Fatalf("uploaded %q, want %q", *uploaded, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONIndentAndContentType(t *testing.T) {
+	var body, contentType string
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		content, _ := io.ReadAll(file)
+		body, contentType = string(content), header.Header.Get("Content-Type")
+		fmt.Fprint(w, `{"data":{"id":"file-1","cid":"bafy"}}`)
+	})
+
+	data := map[string]string{"name": "token"}
+	for _, service := range []interface {
+		JSON(interface{}, *JSONOptions) (*types.UploadResponse, error)
+	}{NewPublicService(cfg), NewPrivateService(cfg)} {
+		if _, err := service.JSON(data, nil); err != nil {
+			t.Fatal(err)
+		}
+		if body != `{"name":"token"}` || contentType != "application/json" {
+			t.Errorf("default: got %q as %q", body, contentType)
+		}
+
+		if _, err := service.JSON(data, &JSONOptions{Indent: true, ContentType: "application/ld+json"}); err != nil {
+			t.Fatal(err)
+		}
+		if want := "{\n  \"name\": \"token\"\n}"; body != want || contentType != "application/ld+json" {
+			t.Errorf("indented: got %q as %q, want %q as application/ld+json", body, contentType, want)
+		}
+	}
+}
+
+func TestContentUploadsDoNotUseDisk(t *testing.T) {
+	cfg, uploaded := newContentTest(t)
+	// Point temp files at a directory that does not exist, so any attempt to
+	// create one fails the upload
+	t.Setenv("TMPDIR", filepath.Join(t.TempDir(), "missing"))
+	if _, err := os.CreateTemp("", "probe"); err == nil {
+		t.Fatal("temp dir is still writable")
+	}
+
+	public := NewPublicService(cfg)
+	private := NewPrivateService(cfg)
+	keyvalues := map[string]string{"env": "test"}
+	uploads := []func() (*types.UploadResponse, error){
+		func() (*types.UploadResponse, error) {
+			return public.JSON(map[string]int{"a": 1}, &JSONOptions{Name: "a.json", KeyValues: keyvalues})
+		},
+		func() (*types.UploadResponse, error) {
+			return private.Base64("aGk=", &Base64Options{Name: "hi.txt", KeyValues: keyvalues})
+		},
+		func() (*types.UploadResponse, error) {
+			return public.URL(cfg.UploadUrl+"/source/doc.txt", &URLOptions{KeyValues: keyvalues})
+		},
+	}
+	for _, upload := range uploads {
+		if _, err := upload(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := []string{`a.json:{"a":1}`, "hi.txt:hi", "doc.txt:fetched"}
+	if strings.Join(*uploaded, ",") != strings.Join(want, ",") {
+		t.Fatalf("uploaded %q, want %q", *uploaded, want)
+	}
+}
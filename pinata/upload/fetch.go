@@ -0,0 +1,166 @@
+package upload
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/PinataCloud/pinata-go-sdk/pinata/internal/request"
+	"github.com/PinataCloud/pinata-go-sdk/pinata/types"
+)
+
+// defaultMaxRedirects matches the limit of net/http's default redirect policy
+const defaultMaxRedirects = 10
+
+// ErrURLTooLarge is returned when URL content is larger than URLOptions.MaxSize
+var ErrURLTooLarge = errors.New("URL content exceeds the maximum size")
+
+// ErrURLFetchTimeout is returned when fetching URL content takes longer than
+// URLOptions.FetchTimeout, as opposed to an error from the upload itself
+var ErrURLFetchTimeout = errors.New("timed out fetching URL content")
+
+// ErrHostNotAllowed is returned when a URL upload or one of its redirects targets
+// a host that is not in URLOptions.AllowedHosts
+var ErrHostNotAllowed = errors.New("URL host is not allowed")
+
+// fetchURL GETs targetURL for a URL upload, enforcing the redirect, host and size
+// limits in opts. The caller must close the returned response body. The fetch
+// timeout also covers reading the body, which is streamed into the upload.
+func fetchURL(ctx context.Context, cfg *types.Config, targetURL string, opts *URLOptions) (_ *http.Response, err error) {
+	var timeout time.Duration
+	if opts != nil {
+		timeout = opts.FetchTimeout
+	}
+
+	cancel := context.CancelFunc(func() {})
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeoutCause(ctx, timeout, ErrURLFetchTimeout)
+	}
+	defer func() {
+		if err != nil {
+			cancel()
+		}
+	}()
+
+	fetchReq, err := http.NewRequestWithContext(ctx, "GET", targetURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create URL request: %w", err)
+	}
+
+	if err := checkHost(fetchReq.URL, opts); err != nil {
+		return nil, err
+	}
+
+	maxRedirects := defaultMaxRedirects
+	if opts != nil && opts.MaxRedirects != 0 {
+		maxRedirects = opts.MaxRedirects
+	}
+
+	// Fetch with a copy of the configured client that applies the redirect policy
+	client := *request.Client(cfg)
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if maxRedirects < 0 {
+			return fmt.Errorf("redirects are not allowed")
+		}
+		if len(via) > maxRedirects {
+			return fmt.Errorf("stopped after %d redirects", maxRedirects)
+		}
+		return checkHost(req.URL, opts)
+	}
+
+	resp, err := request.DoWithClient(cfg, &client, fetchReq)
+	if err != nil {
+		if context.Cause(ctx) == ErrURLFetchTimeout {
+			return nil, fmt.Errorf("%w after %s", ErrURLFetchTimeout, timeout)
+		}
+		return nil, fmt.Errorf("failed to fetch URL content: %w", err)
+	}
+	resp.Body = &fetchBody{ReadCloser: resp.Body, ctx: ctx, cancel: cancel, timeout: timeout}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("URL returned non-OK status: %d", resp.StatusCode)
+	}
+
+	if opts != nil && opts.MaxSize > 0 {
+		if resp.ContentLength > opts.MaxSize {
+			resp.Body.Close()
+			return nil, fmt.Errorf("%w: %d bytes is more than %d", ErrURLTooLarge, resp.ContentLength, opts.MaxSize)
+		}
+		// The length may be missing or wrong, so also stop reading past the limit
+		resp.Body = &maxSizeReader{ReadCloser: resp.Body, remaining: opts.MaxSize, max: opts.MaxSize}
+	}
+
+	return resp, nil
+}
+
+// checkHost returns ErrHostNotAllowed if opts restricts hosts and u's host is not listed
+func checkHost(u *url.URL, opts *URLOptions) error {
+	if opts == nil || len(opts.AllowedHosts) == 0 {
+		return nil
+	}
+
+	for _, host := range opts.AllowedHosts {
+		if strings.EqualFold(host, u.Hostname()) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: %s", ErrHostNotAllowed, u.Hostname())
+}
+
+// fetchBody reports a read cut short by the fetch timeout as ErrURLFetchTimeout
+// and releases the timeout when closed
+type fetchBody struct {
+	io.ReadCloser
+	ctx     context.Context
+	cancel  context.CancelFunc
+	timeout time.Duration
+}
+
+// Read implements io.Reader
+func (b *fetchBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err != nil && err != io.EOF && context.Cause(b.ctx) == ErrURLFetchTimeout {
+		return n, fmt.Errorf("%w after %s", ErrURLFetchTimeout, b.timeout)
+	}
+	return n, err
+}
+
+// Close implements io.Closer
+func (b *fetchBody) Close() error {
+	defer b.cancel()
+	return b.ReadCloser.Close()
+}
+
+// maxSizeReader fails with ErrURLTooLarge once more than max bytes have been read,
+// unlike io.LimitReader which would silently truncate the upload
+type maxSizeReader struct {
+	io.ReadCloser
+	remaining int64
+	max       int64
+}
+
+// Read implements io.Reader
+func (r *maxSizeReader) Read(p []byte) (int, error) {
+	if r.remaining < 0 {
+		return 0, fmt.Errorf("%w of %d bytes", ErrURLTooLarge, r.max)
+	}
+
+	// Read one byte past the limit to tell an exact fit from an overflow
+	if int64(len(p)) > r.remaining+1 {
+		p = p[:r.remaining+1]
+	}
+
+	n, err := r.ReadCloser.Read(p)
+	r.remaining -= int64(n)
+	if r.remaining < 0 {
+		return 0, fmt.Errorf("%w of %d bytes", ErrURLTooLarge, r.max)
+	}
+	return n, err
+}
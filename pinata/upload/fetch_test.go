@@ -0,0 +1,136 @@
+package upload
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PinataCloud/pinata-go-sdk/pinata/types"
+)
+
+// newFetchTest serves uploads on /files, recording the uploaded part's file
+// name and content type, and routes every other path to source
+func newFetchTest(t *testing.T, source http.HandlerFunc) (*types.Config, *[]string) {
+	t.Helper()
+
+	var uploaded []string
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/files" {
+			source(w, r)
+			return
+		}
+		_, header, err := r.FormFile("file")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		uploaded = append(uploaded, header.Filename+":"+header.Header.Get("Content-Type"))
+		fmt.Fprint(w, `{"data":{"id":"file-1","cid":"bafy"}}`)
+	})
+	return cfg, &uploaded
+}
+
+func TestURLUploadKeepsSourceTypeAndName(t *testing.T) {
+	cfg, uploaded := newFetchTest(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/jpeg")
+		w.Header().Set("Content-Disposition", `attachment; filename="cat.jpg"`)
+		fmt.Fprint(w, "not really a jpeg")
+	})
+
+	if _, err := NewPublicService(cfg).URL(cfg.UploadUrl+"/download?id=7", nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewPrivateService(cfg).URL(cfg.UploadUrl+"/download?id=7", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, got := range *uploaded {
+		if got != "cat.jpg:image/jpeg" {
+			t.Errorf("uploaded %q, want cat.jpg:image/jpeg", got)
+		}
+	}
+	if len(*uploaded) != 2 {
+		t.Fatalf("got %d uploads, want 2", len(*uploaded))
+	}
+}
+
+func TestURLUploadLimits(t *testing.T) {
+	cfg, uploaded := newFetchTest(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/big":
+			fmt.Fprint(w, "0123456789")
+		case "/chunked":
+			// Flushing before writing everything leaves the length out
+			w.Write([]byte("01234"))
+			w.(http.Flusher).Flush()
+			w.Write([]byte("56789"))
+		case "/loop":
+			http.Redirect(w, r, "/loop", http.StatusFound)
+		}
+	})
+	service := NewPublicService(cfg)
+
+	tests := []struct {
+		name string
+		path string
+		opts *URLOptions
+		want error
+	}{
+		{"declared size", "/big", &URLOptions{MaxSize: 5}, ErrURLTooLarge},
+		{"streamed size", "/chunked", &URLOptions{MaxSize: 5}, ErrURLTooLarge},
+		{"host", "/big", &URLOptions{AllowedHosts: []string{"example.com"}}, ErrHostNotAllowed},
+	}
+	for _, tt := range tests {
+		_, err := service.URL(cfg.UploadUrl+tt.path, tt.opts)
+		if !errors.Is(err, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, err, tt.want)
+		}
+	}
+
+	if _, err := service.URL(cfg.UploadUrl+"/loop", &URLOptions{MaxRedirects: 2}); err == nil || !strings.Contains(err.Error(), "stopped after 2 redirects") {
+		t.Errorf("redirect loop: got %v", err)
+	}
+	if _, err := service.URL(cfg.UploadUrl+"/loop", &URLOptions{MaxRedirects: -1}); err == nil || !strings.Contains(err.Error(), "redirects are not allowed") {
+		t.Errorf("no redirects: got %v", err)
+	}
+	if len(*uploaded) != 0 {
+		t.Fatalf("uploaded %q past the limits", *uploaded)
+	}
+
+	if _, err := service.URL(cfg.UploadUrl+"/big", &URLOptions{MaxSize: 10}); err != nil {
+		t.Fatalf("content at the limit: %v", err)
+	}
+}
+
+func TestURLUploadFetchTimeout(t *testing.T) {
+	release := make(chan struct{})
+	cfg, uploaded := newFetchTest(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow-body" {
+			w.Write([]byte("partial"))
+			w.(http.Flusher).Flush()
+		}
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	})
+	defer close(release)
+	service := NewPrivateService(cfg)
+
+	for _, path := range []string{"/slow-headers", "/slow-body"} {
+		start := time.Now()
+		_, err := service.URL(cfg.UploadUrl+path, &URLOptions{FetchTimeout: 50 * time.Millisecond})
+		if !errors.Is(err, ErrURLFetchTimeout) {
+			t.Fatalf("%s: got %v, want ErrURLFetchTimeout", path, err)
+		}
+		if elapsed := time.Since(start); elapsed > 2*time.Second {
+			t.Fatalf("%s: took %s to time out", path, elapsed)
+		}
+	}
+	if len(*uploaded) != 0 {
+		t.Fatalf("uploaded %q", *uploaded)
+	}
+}
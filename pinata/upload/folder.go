@@ -0,0 +1,128 @@
+package upload
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// FolderOptions represents options for uploading a directory from disk
+type FolderOptions struct {
+	FileOptions
+	// FollowSymlinks uploads the files symbolic links point to. By default links
+	// are skipped. Links to directories are always skipped.
+	FollowSymlinks bool
+}
+
+// folderFile is a file to upload as part of a folder, with its path inside the folder
+type folderFile struct {
+	Path string
+	File *os.File
+}
+
+// baseFolderFiles places every file at the root of the folder under its base name
+func baseFolderFiles(files []*os.File) []folderFile {
+	entries := make([]folderFile, len(files))
+	for i, file := range files {
+		entries[i] = folderFile{Path: filepath.Base(file.Name()), File: file}
+	}
+	return entries
+}
+
+// pathFolderFiles validates the relative paths of files and returns them sorted by path
+func pathFolderFiles(files map[string]*os.File) ([]folderFile, error) {
+	entries := make([]folderFile, 0, len(files))
+	for name, file := range files {
+		if file == nil {
+			return nil, fmt.Errorf("file for path %q is nil", name)
+		}
+
+		cleaned, err := cleanFolderPath(name)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, folderFile{Path: cleaned, File: file})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Path < entries[j].Path
+	})
+
+	return entries, nil
+}
+
+// cleanFolderPath normalises a relative path inside a folder upload to use forward
+// slashes, rejecting absolute paths and paths that escape the folder
+func cleanFolderPath(name string) (string, error) {
+	slashed := filepath.ToSlash(name)
+	if slashed == "" || path.IsAbs(slashed) || filepath.IsAbs(name) {
+		return "", fmt.Errorf("invalid path %q: must be relative", name)
+	}
+
+	cleaned := path.Clean(slashed)
+	if cleaned == "." || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", fmt.Errorf("invalid path %q: must stay inside the folder", name)
+	}
+
+	return cleaned, nil
+}
+
+// openFolder opens every regular file under dir, keyed by its path relative to
+// dir. The returned function closes them all and must be called even on error.
+func openFolder(dir string, followSymlinks bool) ([]folderFile, func(), error) {
+	var entries []folderFile
+	closeAll := func() {
+		for _, entry := range entries {
+			entry.File.Close()
+		}
+	}
+
+	err := filepath.WalkDir(dir, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		if d.Type()&fs.ModeSymlink != 0 {
+			if !followSymlinks {
+				return nil
+			}
+			info, err := os.Stat(name)
+			if err != nil {
+				return fmt.Errorf("failed to resolve symlink %s: %w", name, err)
+			}
+			if !info.Mode().IsRegular() {
+				return nil
+			}
+		} else if !d.Type().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, name)
+		if err != nil {
+			return err
+		}
+
+		file, err := os.Open(name)
+		if err != nil {
+			return fmt.Errorf("failed to open %s: %w", name, err)
+		}
+		entries = append(entries, folderFile{Path: filepath.ToSlash(rel), File: file})
+		return nil
+	})
+	if err != nil {
+		return nil, closeAll, fmt.Errorf("failed to read directory: %w", err)
+	}
+
+	if len(entries) == 0 {
+		return nil, closeAll, fmt.Errorf("directory %s contains no files", dir)
+	}
+
+	return entries, closeAll, nil
+}
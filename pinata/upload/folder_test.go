@@ -0,0 +1,253 @@
+package upload
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/PinataCloud/pinata-go-sdk/pinata/types"
+)
+
+// newFolderTest records the full file name of every file part, with its content,
+// and the name field of each folder upload. multipart.Part.FileName drops
+// directories, so the Content-Disposition header is parsed directly.
+func newFolderTest(t *testing.T) (*types.Config, *[]string, *[]string) {
+	t.Helper()
+
+	var parts, names []string
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		reader, err := r.MultipartReader()
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		for {
+			part, err := reader.NextPart()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			content, _ := io.ReadAll(part)
+			_, params, _ := mime.ParseMediaType(part.Header.Get("Content-Disposition"))
+			switch params["name"] {
+			case "file":
+				parts = append(parts, params["filename"]+"="+string(content))
+			case "name":
+				names = append(names, string(content))
+			}
+		}
+		fmt.Fprint(w, `{"data":{"id":"folder-1","cid":"bafy-dir"}}`)
+	})
+
+	return cfg, &parts, &names
+}
+
+// writeTree creates files under dir from a map of slash-separated relative
+// paths to contents
+func writeTree(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestFileArrayWithPathsPreservesNesting(t *testing.T) {
+	cfg, parts, _ := newFolderTest(t)
+
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{"index.html": "home", "css/site.css": "body{}", "img/icons/logo.svg": "<svg/>"})
+	files := map[string]*os.File{}
+	for _, name := range []string{"index.html", "css/site.css", "img/icons/logo.svg"} {
+		file, err := os.Open(filepath.Join(dir, filepath.FromSlash(name)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer file.Close()
+		files["site/"+name] = file
+	}
+
+	if _, err := NewPublicService(cfg).FileArrayWithPaths(files, &FileOptions{FileName: "site"}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "[site/css/site.css=body{} site/img/icons/logo.svg=<svg/> site/index.html=home]"
+	if fmt.Sprint(*parts) != want {
+		t.Fatalf("got parts %v, want %s", *parts, want)
+	}
+}
+
+func TestFileArrayWithPathsRejectsEscapes(t *testing.T) {
+	cfg, parts, _ := newFolderTest(t)
+	file := openTempFiles(t, 1)[0]
+
+	for _, name := range []string{"../secret.txt", "site/../../secret.txt", "/etc/passwd", "", "."} {
+		if _, err := NewPrivateService(cfg).FileArrayWithPaths(map[string]*os.File{name: file}, nil); err == nil {
+			t.Errorf("path %q accepted", name)
+		}
+	}
+	if len(*parts) != 0 {
+		t.Fatalf("sent %v", *parts)
+	}
+}
+
+func TestCleanFolderPath(t *testing.T) {
+	tests := map[string]string{
+		"a.txt":          "a.txt",
+		"dir/./a.txt":    "dir/a.txt",
+		"dir//sub/a.txt": "dir/sub/a.txt",
+		"dir/sub/../a":   "dir/a",
+	}
+	for name, want := range tests {
+		got, err := cleanFolderPath(name)
+		if err != nil || got != want {
+			t.Errorf("cleanFolderPath(%q) = %q, %v, want %q", name, got, err, want)
+		}
+	}
+	if _, err := cleanFolderPath(strings.Repeat("../", 2) + "a"); err == nil {
+		t.Error("escaping path accepted")
+	}
+}
+
+func TestFolderFromPath(t *testing.T) {
+	cfg, parts, names := newFolderTest(t)
+
+	dir := filepath.Join(t.TempDir(), "public")
+	writeTree(t, dir, map[string]string{"index.html": "home", "assets/app.js": "run()", "assets/img/a.png": "png"})
+	if err := os.MkdirAll(filepath.Join(dir, "empty"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := NewPublicService(cfg).FolderFromPath(dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.CID != "bafy-dir" {
+		t.Fatalf("got %+v", resp)
+	}
+
+	want := "[assets/app.js=run() assets/img/a.png=png index.html=home]"
+	if fmt.Sprint(*parts) != want {
+		t.Fatalf("got parts %v, want %s", *parts, want)
+	}
+	if fmt.Sprint(*names) != "[public]" {
+		t.Fatalf("folder named %v, want the directory name", *names)
+	}
+}
+
+func TestFolderFromPathSymlinks(t *testing.T) {
+	outside := t.TempDir()
+	writeTree(t, outside, map[string]string{"shared.txt": "shared"})
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{"a.txt": "a"})
+	if err := os.Symlink(filepath.Join(outside, "shared.txt"), filepath.Join(dir, "link.txt")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+	if err := os.Symlink(outside, filepath.Join(dir, "linkdir")); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, parts, _ := newFolderTest(t)
+	if _, err := NewPrivateService(cfg).FolderFromPath(dir, nil); err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(*parts) != "[a.txt=a]" {
+		t.Fatalf("default: got parts %v, want links skipped", *parts)
+	}
+
+	*parts = nil
+	if _, err := NewPrivateService(cfg).FolderFromPath(dir, &FolderOptions{FollowSymlinks: true}); err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(*parts) != "[a.txt=a link.txt=shared]" {
+		t.Fatalf("FollowSymlinks: got parts %v, want the linked file but not the linked directory", *parts)
+	}
+}
+
+func TestFolderFromPathEmpty(t *testing.T) {
+	cfg, parts, _ := newFolderTest(t)
+
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "nested", "empty"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NewPublicService(cfg).FolderFromPath(dir, nil); err == nil || !strings.Contains(err.Error(), "contains no files") {
+		t.Fatalf("got %v, want a no files error", err)
+	}
+	if _, err := NewPublicService(cfg).FolderFromPath(filepath.Join(dir, "missing"), nil); err == nil {
+		t.Fatal("missing directory accepted")
+	}
+	if len(*parts) != 0 {
+		t.Fatalf("sent %v", *parts)
+	}
+}
+
+func TestOpenFolderClosesFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{"a.txt": "a", "b/c.txt": "c"})
+
+	entries, closeAll, err := openFolder(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("opened %d files, want 2", len(entries))
+	}
+
+	closeAll()
+	for _, entry := range entries {
+		if _, err := entry.File.Stat(); err == nil {
+			t.Errorf("%s left open", entry.Path)
+		}
+	}
+}
+
+func TestUploadsReportNumberOfFiles(t *testing.T) {
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if len(r.MultipartForm.File["file"]) > 1 {
+			fmt.Fprint(w, `{"data":{"id":"folder-1","cid":"bafy-dir","numberOfFiles":2}}`)
+			return
+		}
+		fmt.Fprint(w, `{"data":{"id":"file-1","cid":"bafy"}}`)
+	})
+
+	for _, service := range []interface {
+		File(*os.File, *FileOptions) (*types.UploadResponse, error)
+		FileArray([]*os.File, *FileOptions) (*types.UploadResponse, error)
+	}{NewPublicService(cfg), NewPrivateService(cfg)} {
+		resp, err := service.File(openTempFiles(t, 1)[0], nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.NumberOfFiles != 1 {
+			t.Errorf("single file: NumberOfFiles = %d, want 1", resp.NumberOfFiles)
+		}
+
+		resp, err = service.FileArray(openNamedFiles(t, "a.txt", "b.txt"), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.NumberOfFiles != 2 {
+			t.Errorf("folder: NumberOfFiles = %d, want 2", resp.NumberOfFiles)
+		}
+	}
+}
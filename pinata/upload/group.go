@@ -0,0 +1,187 @@
+package upload
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"runtime"
+	"strings"
+	"sync"
+	"weak"
+
+	"github.com/PinataCloud/pinata-go-sdk/pinata/groups"
+	"github.com/PinataCloud/pinata-go-sdk/pinata/types"
+)
+
+// groupKey identifies a group resolved from FileOptions.GroupName within one Config
+type groupKey struct {
+	network string
+	name    string
+}
+
+// groupCache holds the groups resolved for one Config
+type groupCache struct {
+	// locks holds a *sync.Mutex per groupKey while its group is being resolved,
+	// so concurrent uploads with the same GroupName look up and create the
+	// group one at a time
+	locks sync.Map
+	// ids remembers the ID resolved for each groupKey
+	ids sync.Map
+}
+
+// groupCaches holds a *groupCache per Config, keyed by a weak pointer so the
+// groups of discarded configs do not accumulate
+var groupCaches sync.Map
+
+// groupCacheFor returns the groups resolved for cfg, deleting them once cfg is
+// garbage collected
+func groupCacheFor(cfg *types.Config) *groupCache {
+	key := weak.Make(cfg)
+	if cache, ok := groupCaches.Load(key); ok {
+		return cache.(*groupCache)
+	}
+
+	cache, loaded := groupCaches.LoadOrStore(key, &groupCache{})
+	if !loaded {
+		runtime.AddCleanup(cfg, func(key weak.Pointer[types.Config]) {
+			groupCaches.Delete(key)
+		}, key)
+	}
+	return cache.(*groupCache)
+}
+
+// resolveGroup returns opts with the GroupID the upload goes into. An explicit
+// GroupID wins, then GroupName, which is found by name or created. Otherwise the
+// network's default group from cfg is used unless NoGroup is set.
+func resolveGroup(ctx context.Context, cfg *types.Config, network string, opts *FileOptions) (*FileOptions, error) {
+	if opts != nil && opts.GroupID != "" {
+		return opts, nil
+	}
+
+	var id string
+	switch {
+	case opts != nil && opts.GroupName != "":
+		found, err := findOrCreateGroup(ctx, cfg, network, opts.GroupName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve group %q: %w", opts.GroupName, err)
+		}
+		id = found
+	case opts != nil && opts.NoGroup:
+		return opts, nil
+	default:
+		id = defaultGroupID(cfg, network)
+	}
+
+	if id == "" {
+		return opts, nil
+	}
+
+	var resolved FileOptions
+	if opts != nil {
+		resolved = *opts
+	}
+	resolved.GroupID = id
+	return &resolved, nil
+}
+
+// defaultGroupID returns the group uploads to network go into when none is given
+func defaultGroupID(cfg *types.Config, network string) string {
+	if network == "private" {
+		return cfg.DefaultPrivateGroupID
+	}
+	return cfg.DefaultPublicGroupID
+}
+
+// forgetGroup drops the cached ID for the GroupName in opts when err shows the
+// group no longer exists, and reports whether it did so, in which case the
+// upload may be sent again to have the group resolved afresh. opts must be the
+// options the caller passed in, before resolveGroup.
+func forgetGroup(cfg *types.Config, network string, opts *FileOptions, err error) bool {
+	if err == nil || opts == nil || opts.GroupID != "" || opts.GroupName == "" {
+		return false
+	}
+	if !isGroupError(err) {
+		return false
+	}
+
+	_, loaded := groupCacheFor(cfg).ids.LoadAndDelete(groupKey{network: network, name: opts.GroupName})
+	return loaded
+}
+
+// isGroupError reports whether err is the API rejecting an upload's group,
+// either as not found or as an invalid group
+func isGroupError(err error) bool {
+	switch types.StatusCode(err) {
+	case http.StatusNotFound:
+		return true
+	case http.StatusBadRequest:
+		return strings.Contains(strings.ToLower(err.Error()), "group")
+	}
+	return false
+}
+
+// findOrCreateGroup returns the ID of the group named name on network, creating
+// it if there is none. Within this process a name is only ever created once per
+// Config; uploads from other processes can still race.
+func findOrCreateGroup(ctx context.Context, cfg *types.Config, network string, name string) (string, error) {
+	cache := groupCacheFor(cfg)
+	key := groupKey{network: network, name: name}
+	if id, ok := cache.ids.Load(key); ok {
+		return id.(string), nil
+	}
+
+	lock, _ := cache.locks.LoadOrStore(key, &sync.Mutex{})
+	mu := lock.(*sync.Mutex)
+	mu.Lock()
+	defer mu.Unlock()
+
+	// Once the ID is cached the lock is no longer needed. Uploads still waiting
+	// on it find the ID below, so it is only kept after a failed attempt.
+	resolved := false
+	defer func() {
+		if resolved {
+			cache.locks.Delete(key)
+		}
+	}()
+
+	// Another upload may have resolved it while this one waited
+	if id, ok := cache.ids.Load(key); ok {
+		return id.(string), nil
+	}
+
+	var list func(context.Context, *groups.ListOptions) *groups.GroupIterator
+	var create func(context.Context, string, bool) (*types.Group, error)
+	if network == "private" {
+		service := groups.NewPrivateService(cfg)
+		list, create = service.ListIterContext, service.CreateContext
+	} else {
+		service := groups.NewPublicService(cfg)
+		list, create = service.ListIterContext, service.CreateContext
+	}
+
+	// The name filter is not an exact match, so compare names here
+	it := list(ctx, &groups.ListOptions{Name: name})
+	for it.Next() {
+		if group := it.Group(); group.Name == name {
+			cache.ids.Store(key, group.ID)
+			resolved = true
+			return group.ID, nil
+		}
+	}
+	if err := it.Err(); err != nil {
+		return "", err
+	}
+
+	// Groups on the public network are public themselves
+	group, err := create(ctx, name, network == "public")
+	if err != nil {
+		return "", err
+	}
+	if group == nil || group.ID == "" {
+		return "", fmt.Errorf("created group has no ID")
+	}
+
+	cache.ids.Store(key, group.ID)
+	resolved = true
+	return group.ID, nil
+}
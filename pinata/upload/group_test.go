@@ -0,0 +1,235 @@
+package upload
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"weak"
+
+	"github.com/PinataCloud/pinata-go-sdk/pinata/types"
+)
+
+// groupServer fakes the group and upload endpoints. Uploads into a group ID
+// listed in deleted fail with 404.
+type groupServer struct {
+	mu      sync.Mutex
+	created []bool
+	uploads []string
+	deleted map[string]bool
+}
+
+func (g *groupServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	switch {
+	case r.Method == "GET" && (r.URL.Path == "/groups/public" || r.URL.Path == "/groups/private"):
+		fmt.Fprint(w, `{"data":{"groups":[]}}`)
+	case r.Method == "POST" && (r.URL.Path == "/groups/public" || r.URL.Path == "/groups/private"):
+		var payload struct {
+			IsPublic bool `json:"is_public"`
+		}
+		json.NewDecoder(r.Body).Decode(&payload)
+		g.created = append(g.created, payload.IsPublic)
+		fmt.Fprintf(w, `{"data":{"id":"group-%d","name":"photos"}}`, len(g.created))
+	case r.Method == "POST" && r.URL.Path == "/files":
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		groupID := r.FormValue("group_id")
+		g.uploads = append(g.uploads, groupID)
+		if g.deleted[groupID] {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error":"group not found"}`)
+			return
+		}
+		fmt.Fprint(w, `{"data":{"id":"file-1","cid":"bafy"}}`)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func newGroupTest(t *testing.T) (*groupServer, *types.Config, *os.File) {
+	t.Helper()
+
+	g := &groupServer{deleted: map[string]bool{}}
+	path := filepath.Join(t.TempDir(), "hello.txt")
+	if err := os.WriteFile(path, []byte("hello"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { file.Close() })
+
+	return g, newTestConfig(t, g.ServeHTTP), file
+}
+
+func TestGroupNameCreatesPublicGroupOnPublicNetwork(t *testing.T) {
+	g, cfg, file := newGroupTest(t)
+
+	if _, err := NewPublicService(cfg).File(file, &FileOptions{GroupName: "photos"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewPrivateService(cfg).File(file, &FileOptions{GroupName: "photos"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(g.created) != 2 || !g.created[0] || g.created[1] {
+		t.Fatalf("created groups with is_public %v, want [true false]", g.created)
+	}
+}
+
+func TestGroupNameCachedAcrossUploads(t *testing.T) {
+	g, cfg, file := newGroupTest(t)
+	service := NewPublicService(cfg)
+
+	for i := 0; i < 3; i++ {
+		if _, err := service.File(file, &FileOptions{GroupName: "photos"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(g.created) != 1 {
+		t.Fatalf("created %d groups, want 1", len(g.created))
+	}
+	if _, ok := groupCacheFor(cfg).locks.Load(groupKey{network: "public", name: "photos"}); ok {
+		t.Fatal("group lock kept after the group was resolved")
+	}
+}
+
+func TestGroupNameResolvedAgainAfterGroupDeleted(t *testing.T) {
+	g, cfg, file := newGroupTest(t)
+	service := NewPublicService(cfg)
+
+	if _, err := service.File(file, &FileOptions{GroupName: "photos"}); err != nil {
+		t.Fatal(err)
+	}
+
+	g.mu.Lock()
+	g.deleted["group-1"] = true
+	g.mu.Unlock()
+
+	if _, err := service.File(file, &FileOptions{GroupName: "photos"}); err != nil {
+		t.Fatalf("upload after the group was deleted: %v", err)
+	}
+
+	want := []string{"group-1", "group-1", "group-2"}
+	if fmt.Sprint(g.uploads) != fmt.Sprint(want) {
+		t.Fatalf("uploads went to groups %v, want %v", g.uploads, want)
+	}
+}
+
+func TestExplicitGroupIDNotRetried(t *testing.T) {
+	g, cfg, file := newGroupTest(t)
+	g.deleted["gone"] = true
+
+	_, err := NewPublicService(cfg).File(file, &FileOptions{GroupID: "gone"})
+	if !types.IsNotFound(err) {
+		t.Fatalf("got %v, want a not found error", err)
+	}
+	if len(g.uploads) != 1 {
+		t.Fatalf("sent %d uploads, want 1", len(g.uploads))
+	}
+}
+
+func TestDefaultGroupPerNetwork(t *testing.T) {
+	g, cfg, file := newGroupTest(t)
+	cfg.DefaultPublicGroupID = "public-default"
+	cfg.DefaultPrivateGroupID = "private-default"
+	public := NewPublicService(cfg)
+	private := NewPrivateService(cfg)
+
+	uploads := []struct {
+		name   string
+		upload func() (*types.UploadResponse, error)
+	}{
+		{"public default", func() (*types.UploadResponse, error) { return public.File(file, nil) }},
+		{"private default", func() (*types.UploadResponse, error) { return private.File(file, &FileOptions{}) }},
+		{"explicit override", func() (*types.UploadResponse, error) { return private.File(file, &FileOptions{GroupID: "chosen"}) }},
+		{"explicit no group", func() (*types.UploadResponse, error) { return public.File(file, &FileOptions{NoGroup: true}) }},
+	}
+	for _, tt := range uploads {
+		if _, err := tt.upload(); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+	}
+
+	want := []string{"public-default", "private-default", "chosen", ""}
+	if fmt.Sprint(g.uploads) != fmt.Sprint(want) {
+		t.Fatalf("uploaded into groups %q, want %q", g.uploads, want)
+	}
+	if len(g.created) != 0 {
+		t.Fatalf("created %d groups", len(g.created))
+	}
+}
+
+func TestGroupNameNotCreatedForRejectedUploads(t *testing.T) {
+	g, cfg, file := newGroupTest(t)
+	mime := func() *FileOptions {
+		return &FileOptions{GroupName: "photos", AllowedMimeTypes: []string{"image/*"}}
+	}
+	size := func() *FileOptions { return &FileOptions{GroupName: "photos", MaxFileSize: 1} }
+	// A reader that cannot seek, with neither a type nor an extension to go by
+	unnamed := func() *FileData {
+		return &FileData{Reader: io.MultiReader(strings.NewReader("plain text")), Name: "notes"}
+	}
+
+	uploads := []struct {
+		name   string
+		upload func() error
+		want   interface{}
+	}{
+		{"File MIME", func() error { _, err := NewPublicService(cfg).File(file, mime()); return err }, new(*MimeTypeError)},
+		{"File size", func() error { _, err := NewPrivateService(cfg).File(file, size()); return err }, new(*FileSizeError)},
+		{"FileReader MIME", func() error {
+			_, err := NewPublicService(cfg).FileReader(&FileData{Reader: strings.NewReader("hi"), Name: "hi.txt"}, mime())
+			return err
+		}, new(*MimeTypeError)},
+		{"FileReader sniffed MIME", func() error { _, err := NewPrivateService(cfg).FileReader(unnamed(), mime()); return err }, new(*MimeTypeError)},
+		{"FileReader size", func() error {
+			_, err := NewPublicService(cfg).FileReader(&FileData{Reader: strings.NewReader("hi"), Name: "hi.txt", Size: 2}, size())
+			return err
+		}, new(*FileSizeError)},
+		{"FileArray MIME", func() error { _, err := NewPrivateService(cfg).FileArray([]*os.File{file}, mime()); return err }, new(*MimeTypeError)},
+		{"FileArray size", func() error { _, err := NewPublicService(cfg).FileArray([]*os.File{file}, size()); return err }, new(*FileSizeError)},
+	}
+	for _, u := range uploads {
+		if err := u.upload(); !errors.As(err, u.want) {
+			t.Errorf("%s: got %v, want a %T", u.name, err, u.want)
+		}
+	}
+
+	if len(g.created) != 0 || len(g.uploads) != 0 {
+		t.Fatalf("rejected uploads created %d groups and sent %d uploads", len(g.created), len(g.uploads))
+	}
+}
+
+func TestGroupCacheReleasedAfterCollection(t *testing.T) {
+	_, cfg, file := newGroupTest(t)
+	if _, err := NewPublicService(cfg).File(file, &FileOptions{GroupName: "photos"}); err != nil {
+		t.Fatal(err)
+	}
+	key := weak.Make(cfg)
+	cfg = nil
+
+	for i := 0; i < 50; i++ {
+		runtime.GC()
+		if _, ok := groupCaches.Load(key); !ok {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("groups resolved for a collected config were not released")
+}
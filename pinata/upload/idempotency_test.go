@@ -0,0 +1,63 @@
+package upload
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/PinataCloud/pinata-go-sdk/pinata/types"
+)
+
+func TestIdempotencyKeyStableAcrossRetries(t *testing.T) {
+	var keys []string
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get(IdempotencyKeyHeader))
+		if len(keys) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, `{"data":{"id":"file-1","cid":"bafy"}}`)
+	})
+	cfg.Retry = &types.RetryPolicy{MaxRetries: 3, BaseDelay: time.Millisecond}
+
+	path := filepath.Join(t.TempDir(), "hello.txt")
+	if err := os.WriteFile(path, []byte("hello"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	if _, err := NewPublicService(cfg).File(file, &FileOptions{IdempotencyKey: "upload-1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(keys) != 3 {
+		t.Fatalf("got %d attempts, want 3", len(keys))
+	}
+	for i, key := range keys {
+		if key != "upload-1" {
+			t.Fatalf("attempt %d sent key %q, want upload-1", i+1, key)
+		}
+	}
+}
+
+func TestIdempotencyKeyOmittedWhenEmpty(t *testing.T) {
+	sent := true
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		_, sent = r.Header[http.CanonicalHeaderKey(IdempotencyKeyHeader)]
+		fmt.Fprint(w, `{"data":{"id":"file-1","cid":"bafy"}}`)
+	})
+
+	if _, err := NewPublicService(cfg).JSON(map[string]string{"a": "b"}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if sent {
+		t.Fatal("idempotency key header sent without a key")
+	}
+}
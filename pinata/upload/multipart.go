@@ -0,0 +1,227 @@
+package upload
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/PinataCloud/pinata-go-sdk/pinata/types"
+)
+
+// sniffLen is the number of bytes http.DetectContentType looks at
+const sniffLen = 512
+
+// quoteEscaper escapes a file name for a Content-Disposition header the same way mime/multipart does
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+// newMultipartWriter creates a multipart writer for w that uses opts.Boundary
+// when set instead of a random boundary
+func newMultipartWriter(w io.Writer, opts *FileOptions) (*multipart.Writer, error) {
+	writer := multipart.NewWriter(w)
+	if opts != nil && opts.Boundary != "" {
+		if err := writer.SetBoundary(opts.Boundary); err != nil {
+			return nil, fmt.Errorf("invalid multipart boundary %q: %w", opts.Boundary, err)
+		}
+	}
+
+	return writer, nil
+}
+
+// createFilePart adds a "file" part to the form with an explicit content type,
+// unlike multipart.Writer.CreateFormFile which always uses application/octet-stream
+func createFilePart(writer *multipart.Writer, filename string, contentType string) (io.Writer, error) {
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file"; filename="%s"`, quoteEscaper.Replace(filename)))
+	header.Set("Content-Type", contentType)
+
+	part, err := writer.CreatePart(header)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create form file: %w", err)
+	}
+
+	return part, nil
+}
+
+// contentTypeByName returns the content type registered for the file name's extension, if any
+func contentTypeByName(name string) string {
+	return mime.TypeByExtension(filepath.Ext(name))
+}
+
+// dispositionFileName returns the file name suggested by a Content-Disposition
+// header, without any directory part, or "" if there is none
+func dispositionFileName(header string) string {
+	if header == "" {
+		return ""
+	}
+
+	_, params, err := mime.ParseMediaType(header)
+	if err != nil {
+		return ""
+	}
+
+	name := filepath.Base(filepath.FromSlash(params["filename"]))
+	if name == "." || name == string(filepath.Separator) {
+		return ""
+	}
+	return name
+}
+
+// detectFileContentType determines the content type of file from the extension of
+// name, the name it is uploaded under, falling back to sniffing its first bytes.
+// The read position is reset to the start.
+func detectFileContentType(file *os.File, name string) (string, error) {
+	if contentType := contentTypeByName(name); contentType != "" {
+		return contentType, nil
+	}
+
+	buf := make([]byte, sniffLen)
+	n, err := io.ReadFull(file, buf)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return "", fmt.Errorf("failed to read file header: %w", err)
+	}
+
+	// Reset file position to start
+	if _, err := file.Seek(0, 0); err != nil {
+		return "", fmt.Errorf("failed to reset file position: %w", err)
+	}
+
+	return http.DetectContentType(buf[:n]), nil
+}
+
+// MimeTypeError is returned before an upload is sent when a file's content type
+// does not match FileOptions.AllowedMimeTypes
+type MimeTypeError struct {
+	Name        string
+	ContentType string
+	Allowed     []string
+}
+
+// Error implements the error interface
+func (e *MimeTypeError) Error() string {
+	return fmt.Sprintf("file %q has content type %s, which is not one of the allowed types %s",
+		e.Name, e.ContentType, strings.Join(e.Allowed, ", "))
+}
+
+// checkMimeType returns a *MimeTypeError if opts restricts the allowed content
+// types and contentType is not among them
+func checkMimeType(name string, contentType string, opts *FileOptions) error {
+	if opts == nil || len(opts.AllowedMimeTypes) == 0 {
+		return nil
+	}
+
+	for _, pattern := range opts.AllowedMimeTypes {
+		if mimeTypeMatches(pattern, contentType) {
+			return nil
+		}
+	}
+
+	return &MimeTypeError{Name: name, ContentType: contentType, Allowed: opts.AllowedMimeTypes}
+}
+
+// mimeTypeMatches reports whether contentType matches pattern, which is either an
+// exact type such as "image/png" or a wildcard such as "image/*" or "*/*".
+// Parameters like charset are ignored.
+func mimeTypeMatches(pattern string, contentType string) bool {
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		contentType = mediaType
+	}
+	pattern = strings.ToLower(strings.TrimSpace(pattern))
+	contentType = strings.ToLower(contentType)
+
+	if pattern == "*" || pattern == "*/*" {
+		return true
+	}
+	if prefix, ok := strings.CutSuffix(pattern, "/*"); ok {
+		return strings.HasPrefix(contentType, prefix+"/")
+	}
+
+	return pattern == contentType
+}
+
+// FileSizeError is returned before an upload is sent when it is larger than
+// FileOptions.MaxFileSize
+type FileSizeError struct {
+	Name    string
+	Size    int64
+	MaxSize int64
+}
+
+// Error implements the error interface
+func (e *FileSizeError) Error() string {
+	return fmt.Sprintf("%s is %d bytes, which exceeds the maximum upload size of %d bytes", e.Name, e.Size, e.MaxSize)
+}
+
+// checkFileSize returns a *FileSizeError if opts sets a maximum size and size exceeds it
+func checkFileSize(name string, size int64, opts *FileOptions) error {
+	if opts == nil || opts.MaxFileSize <= 0 || size <= opts.MaxFileSize {
+		return nil
+	}
+
+	return &FileSizeError{Name: name, Size: size, MaxSize: opts.MaxFileSize}
+}
+
+// ErrDuplicate is returned with the existing file's record when
+// FileOptions.FailOnDuplicate is set and the content was already uploaded
+var ErrDuplicate = errors.New("file was already uploaded")
+
+// checkDuplicate turns a deduplicated upload into ErrDuplicate when opts asks for it
+func checkDuplicate(resp *types.UploadResponse, opts *FileOptions) (*types.UploadResponse, error) {
+	if opts != nil && opts.FailOnDuplicate && resp.WasDeduplicated() {
+		return resp, fmt.Errorf("%w: %s", ErrDuplicate, resp.CID)
+	}
+
+	return resp, nil
+}
+
+// reservedFields are form fields the SDK always sets, which ExtraFields must not override
+var reservedFields = []string{"file", "network", "name"}
+
+// checkExtraFields rejects ExtraFields that would override a reserved field
+func checkExtraFields(opts *FileOptions) error {
+	if opts == nil {
+		return nil
+	}
+
+	for key := range opts.ExtraFields {
+		for _, reserved := range reservedFields {
+			if strings.EqualFold(key, reserved) {
+				return fmt.Errorf("extra field %q is reserved", key)
+			}
+		}
+	}
+
+	return nil
+}
+
+// writeExtraFields writes opts.ExtraFields to the form in a stable order
+func writeExtraFields(writer *multipart.Writer, opts *FileOptions) error {
+	if opts == nil || len(opts.ExtraFields) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(opts.ExtraFields))
+	for key := range opts.ExtraFields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if err := writer.WriteField(key, opts.ExtraFields[key]); err != nil {
+			return fmt.Errorf("failed to add %s field: %w", key, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,375 @@
+package upload
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/PinataCloud/pinata-go-sdk/pinata/types"
+)
+
+// newTestConfig serves both the API and uploads from handler
+func newTestConfig(t *testing.T, handler http.HandlerFunc) *types.Config {
+	t.Helper()
+
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	return &types.Config{PinataJWT: "jwt", APIUrl: srv.URL, UploadUrl: srv.URL}
+}
+
+// pngHeader is enough of a PNG file for http.DetectContentType to recognize it
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR\x00\x00\x00\x01\x00\x00\x00\x01\x08\x06\x00\x00\x00")
+
+// newPartTest records the content type and content of each uploaded file part
+func newPartTest(t *testing.T) (*types.Config, *[]string) {
+	t.Helper()
+
+	var parts []string
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		content, _ := io.ReadAll(file)
+		parts = append(parts, fmt.Sprintf("%s:%t", header.Header.Get("Content-Type"), bytes.Equal(content, pngHeader)))
+		fmt.Fprint(w, `{"data":{"id":"file-1","cid":"bafy"}}`)
+	})
+
+	return cfg, &parts
+}
+
+func writePNG(t *testing.T, name string) *os.File {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, pngHeader, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { file.Close() })
+	return file
+}
+
+func TestFilePartContentType(t *testing.T) {
+	cfg, parts := newPartTest(t)
+	service := NewPrivateService(cfg)
+
+	// Detected from the extension, then sniffed from the content
+	if _, err := service.File(writePNG(t, "photo.png"), nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.File(writePNG(t, "photo"), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "[image/png:true image/png:true]"
+	if fmt.Sprint(*parts) != want {
+		t.Fatalf("got parts %v, want %s", *parts, want)
+	}
+}
+
+func TestFileReaderPartContentType(t *testing.T) {
+	cfg, parts := newPartTest(t)
+	service := NewPublicService(cfg)
+
+	sniffed := &FileData{Reader: bytes.NewReader(pngHeader), Name: "photo", Size: int64(len(pngHeader))}
+	if _, err := service.FileReader(sniffed, nil); err != nil {
+		t.Fatal(err)
+	}
+	declared := &FileData{Reader: bytes.NewReader(pngHeader), Name: "photo.bin", Size: int64(len(pngHeader)), ContentType: "image/x-custom"}
+	if _, err := service.FileReader(declared, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "[image/png:true image/x-custom:true]"
+	if fmt.Sprint(*parts) != want {
+		t.Fatalf("got parts %v, want %s", *parts, want)
+	}
+}
+
+func TestAllowedMimeTypes(t *testing.T) {
+	cfg, parts := newPartTest(t)
+	service := NewPublicService(cfg)
+
+	if _, err := service.File(writePNG(t, "photo.png"), &FileOptions{AllowedMimeTypes: []string{"image/png"}}); err != nil {
+		t.Fatalf("allowed type: %v", err)
+	}
+	if _, err := service.File(writePNG(t, "photo"), &FileOptions{AllowedMimeTypes: []string{"text/plain", "image/*"}}); err != nil {
+		t.Fatalf("wildcard: %v", err)
+	}
+
+	_, err := service.File(writePNG(t, "photo.png"), &FileOptions{AllowedMimeTypes: []string{"application/pdf", "text/*"}})
+	var mimeErr *MimeTypeError
+	if !errors.As(err, &mimeErr) || mimeErr.ContentType != "image/png" || mimeErr.Name != "photo.png" {
+		t.Fatalf("disallowed type: got %v, want a *MimeTypeError", err)
+	}
+
+	data := &FileData{Reader: bytes.NewReader(pngHeader), Name: "photo", Size: int64(len(pngHeader))}
+	if _, err := service.FileReader(data, &FileOptions{AllowedMimeTypes: []string{"video/*"}}); !errors.As(err, &mimeErr) {
+		t.Fatalf("disallowed sniffed type: got %v, want a *MimeTypeError", err)
+	}
+
+	if len(*parts) != 2 {
+		t.Fatalf("server received %d uploads, want only the 2 allowed ones", len(*parts))
+	}
+}
+
+func TestMimeTypeMatches(t *testing.T) {
+	tests := []struct {
+		pattern     string
+		contentType string
+		want        bool
+	}{
+		{"image/png", "image/png", true},
+		{"IMAGE/PNG", "image/png", true},
+		{"text/plain", "text/plain; charset=utf-8", true},
+		{"image/*", "image/webp", true},
+		{"image/*", "imagex/png", false},
+		{"*/*", "application/pdf", true},
+		{"*", "application/pdf", true},
+		{"image/png", "image/jpeg", false},
+	}
+	for _, tt := range tests {
+		if got := mimeTypeMatches(tt.pattern, tt.contentType); got != tt.want {
+			t.Errorf("mimeTypeMatches(%q, %q) = %t, want %t", tt.pattern, tt.contentType, got, tt.want)
+		}
+	}
+}
+
+func TestMaxFileSize(t *testing.T) {
+	cfg, parts := newPartTest(t)
+	service := NewPrivateService(cfg)
+	size := int64(len(pngHeader))
+
+	if _, err := service.File(writePNG(t, "photo.png"), &FileOptions{MaxFileSize: size}); err != nil {
+		t.Fatalf("file at the limit: %v", err)
+	}
+	if _, err := service.File(writePNG(t, "photo.png"), &FileOptions{}); err != nil {
+		t.Fatalf("unlimited: %v", err)
+	}
+
+	_, err := service.File(writePNG(t, "photo.png"), &FileOptions{MaxFileSize: size - 1})
+	var sizeErr *FileSizeError
+	if !errors.As(err, &sizeErr) || sizeErr.Size != size || sizeErr.MaxSize != size-1 {
+		t.Fatalf("file over the limit: got %v, want a *FileSizeError", err)
+	}
+
+	data := &FileData{Reader: bytes.NewReader(pngHeader), Name: "photo.png", Size: size}
+	if _, err := service.FileReader(data, &FileOptions{MaxFileSize: 10}); !errors.As(err, &sizeErr) {
+		t.Fatalf("reader over the limit: got %v, want a *FileSizeError", err)
+	}
+
+	if len(*parts) != 2 {
+		t.Fatalf("server received %d uploads, want only the 2 under the limit", len(*parts))
+	}
+}
+
+func TestMaxFileSizeFileArrayCumulative(t *testing.T) {
+	cfg, parts := newPartTest(t)
+	service := NewPublicService(cfg)
+	size := int64(len(pngHeader))
+
+	files := []*os.File{writePNG(t, "a.png"), writePNG(t, "b.png")}
+	if _, err := service.FileArray(files, &FileOptions{MaxFileSize: 2 * size}); err != nil {
+		t.Fatalf("folder at the limit: %v", err)
+	}
+
+	files = []*os.File{writePNG(t, "a.png"), writePNG(t, "b.png")}
+	_, err := service.FileArray(files, &FileOptions{MaxFileSize: 2*size - 1})
+	var sizeErr *FileSizeError
+	if !errors.As(err, &sizeErr) || sizeErr.Size != 2*size {
+		t.Fatalf("folder over the limit: got %v, want a *FileSizeError for %d bytes", err, 2*size)
+	}
+
+	if len(*parts) != 1 {
+		t.Fatalf("server received %d uploads, want 1", len(*parts))
+	}
+}
+
+func TestDuplicateUploads(t *testing.T) {
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data":{"id":"existing","cid":"bafy","is_duplicate":true}}`)
+	})
+	service := NewPublicService(cfg)
+
+	// By default the existing record is returned
+	resp, err := service.File(writePNG(t, "photo.png"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resp.WasDeduplicated() || resp.ID != "existing" {
+		t.Fatalf("got %+v, want the existing record", resp)
+	}
+
+	// FailOnDuplicate turns it into ErrDuplicate, still returning the record
+	resp, err = service.File(writePNG(t, "photo.png"), &FileOptions{FailOnDuplicate: true})
+	if !errors.Is(err, ErrDuplicate) {
+		t.Fatalf("got %v, want ErrDuplicate", err)
+	}
+	if resp == nil || resp.ID != "existing" {
+		t.Fatalf("got %+v with the error, want the existing record", resp)
+	}
+}
+
+func TestFailOnDuplicateAllowsNewContent(t *testing.T) {
+	cfg, _ := newPartTest(t)
+
+	resp, err := NewPrivateService(cfg).File(writePNG(t, "photo.png"), &FileOptions{FailOnDuplicate: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.WasDeduplicated() {
+		t.Fatal("new content reported as deduplicated")
+	}
+
+	var missing *types.UploadResponse
+	if missing.WasDeduplicated() {
+		t.Fatal("nil response reported as deduplicated")
+	}
+}
+
+func TestExtraFields(t *testing.T) {
+	var fields []string
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		reader, err := r.MultipartReader()
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		for {
+			part, err := reader.NextPart()
+			if err != nil {
+				break
+			}
+			if part.FormName() == "file" {
+				fields = append(fields, "file")
+				continue
+			}
+			value, _ := io.ReadAll(part)
+			fields = append(fields, part.FormName()+"="+string(value))
+		}
+		fmt.Fprint(w, `{"data":{"id":"file-1","cid":"bafy"}}`)
+	})
+	service := NewPublicService(cfg)
+
+	opts := &FileOptions{ExtraFields: map[string]string{"zeta": "2", "alpha": "1"}}
+	if _, err := service.File(writePNG(t, "photo.png"), opts); err != nil {
+		t.Fatal(err)
+	}
+	got := strings.Join(fields, ",")
+	if !strings.Contains(got, "alpha=1,zeta=2") || !strings.HasSuffix(got, ",file") {
+		t.Fatalf("sent fields %s, want the extra fields in order before the file", got)
+	}
+
+	fields = nil
+	for _, reserved := range []string{"file", "network", "Name"} {
+		opts := &FileOptions{ExtraFields: map[string]string{reserved: "x"}}
+		if _, err := service.File(writePNG(t, "photo.png"), opts); err == nil {
+			t.Errorf("%s: got no error", reserved)
+		}
+	}
+	if len(fields) != 0 {
+		t.Fatalf("sent %q with reserved extra fields", fields)
+	}
+}
+
+func TestFixedBoundaryBody(t *testing.T) {
+	var contentType, body string
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		raw, _ := io.ReadAll(r.Body)
+		contentType, body = r.Header.Get("Content-Type"), string(raw)
+		fmt.Fprint(w, `{"data":{"id":"file-1","cid":"bafy"}}`)
+	})
+
+	path := filepath.Join(t.TempDir(), "hello.txt")
+	if err := os.WriteFile(path, []byte("hello"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	opts := &FileOptions{Boundary: "fixed-boundary", KeyValues: map[string]string{"env": "test"}}
+	if _, err := NewPublicService(cfg).File(file, opts); err != nil {
+		t.Fatal(err)
+	}
+
+	want := strings.ReplaceAll(`--fixed-boundary
+Content-Disposition: form-data; name="network"
+
+public
+--fixed-boundary
+Content-Disposition: form-data; name="name"
+
+hello.txt
+--fixed-boundary
+Content-Disposition: form-data; name="keyvalues"
+
+{"env":"test"}
+--fixed-boundary
+Content-Disposition: form-data; name="file"; filename="hello.txt"
+Content-Type: text/plain; charset=utf-8
+
+hello
+--fixed-boundary--
+`, "\n", "\r\n")
+	if contentType != "multipart/form-data; boundary=fixed-boundary" {
+		t.Fatalf("content type %q", contentType)
+	}
+	if body != want {
+		t.Fatalf("body:\n%s\nwant:\n%s", body, want)
+	}
+
+	if _, err := NewPublicService(cfg).File(file, &FileOptions{Boundary: "bad boundary!"}); err == nil {
+		t.Fatal("invalid boundary: got no error")
+	}
+}
+
+func TestFileNameSetsPartFileName(t *testing.T) {
+	var parts []string
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		_, header, err := r.FormFile("file")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		parts = append(parts, fmt.Sprintf("%s|%s|%s", header.Filename, r.FormValue("name"), header.Header.Get("Content-Type")))
+		fmt.Fprint(w, `{"data":{"id":"file-1","cid":"bafy"}}`)
+	})
+
+	path := filepath.Join(t.TempDir(), "upload-123.tmp")
+	if err := os.WriteFile(path, []byte("plain words"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	if _, err := NewPublicService(cfg).File(file, &FileOptions{FileName: "report.json"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewPrivateService(cfg).File(file, &FileOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The content type follows the name the file is stored under
+	want := []string{"report.json|report.json|application/json", "upload-123.tmp|upload-123.tmp|text/plain; charset=utf-8"}
+	if fmt.Sprint(parts) != fmt.Sprint(want) {
+		t.Fatalf("sent %q, want %q", parts, want)
+	}
+}
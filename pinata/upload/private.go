@@ -2,6 +2,7 @@ package upload
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
@@ -13,6 +14,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/PinataCloud/pinata-go-sdk/pinata/internal/batch"
+	"github.com/PinataCloud/pinata-go-sdk/pinata/internal/request"
 	"github.com/PinataCloud/pinata-go-sdk/pinata/types"
 )
 
@@ -30,27 +33,74 @@ func NewPrivateService(config interface{}) *PrivateService {
 
 // File uploads a file to the public IPFS network
 func (s *PrivateService) File(file *os.File, opts *FileOptions) (*types.UploadResponse, error) {
+	return s.FileContext(context.Background(), file, opts)
+}
+
+// FileContext is like File but carries ctx through to the underlying requests
+func (s *PrivateService) FileContext(ctx context.Context, file *os.File, opts *FileOptions) (*types.UploadResponse, error) {
+	resp, err := s.uploadFile(ctx, file, opts)
+	if forgetGroup(s.config.(*types.Config), "private", opts, err) {
+		// The named group was deleted after it was cached, so resolve it again
+		resp, err = s.uploadFile(ctx, file, opts)
+	}
+	return resp, err
+}
+
+// uploadFile resolves the upload's group and sends file in one request
+func (s *PrivateService) uploadFile(ctx context.Context, file *os.File, opts *FileOptions) (*types.UploadResponse, error) {
 	if file == nil {
 		return nil, fmt.Errorf("file is required")
 	}
 
+	if err := checkExtraFields(opts); err != nil {
+		return nil, err
+	}
+
 	// Get file info
 	fileInfo, err := file.Stat()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get file info: %w", err)
 	}
+	if err := checkFileSize(fileInfo.Name(), fileInfo.Size(), opts); err != nil {
+		return nil, err
+	}
 
 	// Reset file position to start
 	if _, err := file.Seek(0, 0); err != nil {
 		return nil, fmt.Errorf("failed to reset file position: %w", err)
 	}
 
+	// Name the part after the upload's file name, so the part and the name field
+	// agree and the content type is detected from the name it is stored as
+	partName := filepath.Base(file.Name())
+	if opts != nil && opts.FileName != "" {
+		partName = opts.FileName
+	}
+
+	contentType, err := detectFileContentType(file, partName)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkMimeType(partName, contentType, opts); err != nil {
+		return nil, err
+	}
+
 	cfg := s.config.(*types.Config)
 	url := fmt.Sprintf("%s/files", cfg.UploadUrl)
 
+	// Resolve the group only once the upload is known to be valid, since it may
+	// create the group
+	opts, err = resolveGroup(ctx, cfg, "private", opts)
+	if err != nil {
+		return nil, err
+	}
+
 	// Create multipart form data
 	body := &bytes.Buffer{}
-	writer := multipart.NewWriter(body)
+	writer, err := newMultipartWriter(body, opts)
+	if err != nil {
+		return nil, err
+	}
 
 	// Add the network parameter
 	if err := writer.WriteField("network", "private"); err != nil {
@@ -86,12 +136,22 @@ func (s *PrivateService) File(file *os.File, opts *FileOptions) (*types.UploadRe
 				return nil, fmt.Errorf("failed to add keyvalues field: %w", err)
 			}
 		}
+
+		if opts.Vectorize {
+			if err := writer.WriteField("vectorize", "true"); err != nil {
+				return nil, fmt.Errorf("failed to add vectorize field: %w", err)
+			}
+		}
+	}
+
+	if err := writeExtraFields(writer, opts); err != nil {
+		return nil, err
 	}
 
 	// Add the file
-	part, err := writer.CreateFormFile("file", filepath.Base(file.Name()))
+	part, err := createFilePart(writer, partName, contentType)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create form file: %w", err)
+		return nil, err
 	}
 
 	if _, err := io.Copy(part, file); err != nil {
@@ -104,56 +164,274 @@ func (s *PrivateService) File(file *os.File, opts *FileOptions) (*types.UploadRe
 	}
 
 	// Create the request
-	req, err := http.NewRequest("POST", url, body)
+	req, err := http.NewRequestWithContext(ctx, "POST", url, body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", writer.FormDataContentType())
-	req.Header.Set("Authorization", "Bearer "+cfg.PinataJWT)
+	request.SetHeaders(cfg, req)
 
-	// Add custom headers if any
-	for key, value := range cfg.CustomHeaders {
-		req.Header.Set(key, value)
+	if opts != nil {
+		request.SetIdempotencyKey(req, opts.IdempotencyKey)
 	}
 
-	// Send the request
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	// Send the request, resending the buffered body on transient failures
+	resp, err := request.DoIdempotent(cfg, req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send request: %w", err)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+	// Parse the response
+	var response *types.UploadResponse
+	if err := request.Decode(resp, &response); err != nil {
+		return nil, err
+	}
+
+	// A single file upload holds one file even when the API leaves the count out
+	if response != nil && response.NumberOfFiles == 0 {
+		response.NumberOfFiles = 1
+	}
+
+	reread := func() (io.Reader, error) {
+		_, err := file.Seek(0, io.SeekStart)
+		return file, err
+	}
+	if err := verifyCID(response, reread, opts); err != nil {
+		return response, err
+	}
+
+	return checkDuplicate(response, opts)
+}
+
+// FileReader uploads the content of data to the private IPFS network. The reader is
+// streamed straight into the request body, so nothing is written to disk or
+// buffered in memory. Retries are only possible when the reader is an io.Seeker.
+func (s *PrivateService) FileReader(data *FileData, opts *FileOptions) (*types.UploadResponse, error) {
+	return s.FileReaderContext(context.Background(), data, opts)
+}
+
+// FileReaderContext is like FileReader but carries ctx through to the underlying requests
+func (s *PrivateService) FileReaderContext(ctx context.Context, data *FileData, opts *FileOptions) (*types.UploadResponse, error) {
+	resp, err := s.uploadFileReader(ctx, data, opts)
+	// The reader is spent, so the next upload with this GroupName resolves it again
+	forgetGroup(s.config.(*types.Config), "private", opts, err)
+	return resp, err
+}
+
+// uploadFileReader resolves the upload's group and streams data in one request
+func (s *PrivateService) uploadFileReader(ctx context.Context, data *FileData, opts *FileOptions) (*types.UploadResponse, error) {
+	if data == nil || data.Reader == nil {
+		return nil, fmt.Errorf("file data is required")
+	}
+
+	// Validate before resolving the group, which may create it
+	data, err := checkStream(data, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := s.config.(*types.Config)
+
+	opts, err = resolveGroup(ctx, cfg, "private", opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Remember where the content starts so it can be read again for VerifyCID
+	var reread func() (io.Reader, error)
+	if opts != nil && opts.VerifyCID {
+		reread = rereader(data.Reader)
+	}
+
+	req, err := newStreamingRequest(ctx, cfg, "private", data, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	request.SetHeaders(cfg, req)
+
+	if opts != nil {
+		request.SetIdempotencyKey(req, opts.IdempotencyKey)
+	}
+
+	// Send the request
+	resp, err := request.DoIdempotent(cfg, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
 	}
 
 	// Parse the response
-	var response struct {
-		Data *types.UploadResponse `json:"data"`
+	var response *types.UploadResponse
+	if err := request.Decode(resp, &response); err != nil {
+		return nil, err
+	}
+
+	// A single file upload holds one file even when the API leaves the count out
+	if response != nil && response.NumberOfFiles == 0 {
+		response.NumberOfFiles = 1
+	}
+
+	if err := verifyCID(response, reread, opts); err != nil {
+		return response, err
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	return checkDuplicate(response, opts)
+}
+
+// FileFromMultipart uploads a file received in a multipart form, such as one from
+// http.Request.FormFile, to the private IPFS network. The file is opened from fh
+// and streamed through FileReader under its form file name and size, and is
+// closed once the upload finishes.
+func (s *PrivateService) FileFromMultipart(fh *multipart.FileHeader, opts *FileOptions) (*types.UploadResponse, error) {
+	return s.FileFromMultipartContext(context.Background(), fh, opts)
+}
+
+// FileFromMultipartContext is like FileFromMultipart but carries ctx through to the underlying requests
+func (s *PrivateService) FileFromMultipartContext(ctx context.Context, fh *multipart.FileHeader, opts *FileOptions) (*types.UploadResponse, error) {
+	if fh == nil {
+		return nil, fmt.Errorf("file header is required")
+	}
+
+	file, err := fh.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open multipart file: %w", err)
+	}
+	defer file.Close()
+
+	// Browsers send application/octet-stream for types they do not know, so let
+	// the file name or content decide instead
+	contentType := fh.Header.Get("Content-Type")
+	if contentType == "application/octet-stream" {
+		contentType = ""
 	}
 
-	return response.Data, nil
+	data := NewCustomFileData(file, fh.Filename, fh.Size, contentType)
+	return s.FileReaderContext(ctx, data, opts)
 }
 
 // FileArray uploads multiple files as a folder to the public IPFS network
 func (s *PrivateService) FileArray(files []*os.File, opts *FileOptions) (*types.UploadResponse, error) {
+	return s.FileArrayContext(context.Background(), files, opts)
+}
+
+// FileArrayContext is like FileArray but carries ctx through to the underlying requests
+func (s *PrivateService) FileArrayContext(ctx context.Context, files []*os.File, opts *FileOptions) (*types.UploadResponse, error) {
+	return s.fileArray(ctx, baseFolderFiles(files), opts)
+}
+
+// FileArrayWithPaths uploads multiple files as a folder to the private IPFS network,
+// keeping the directory layout given by the map keys, which are slash separated
+// paths relative to the folder root such as "assets/logo.png"
+func (s *PrivateService) FileArrayWithPaths(files map[string]*os.File, opts *FileOptions) (*types.UploadResponse, error) {
+	return s.FileArrayWithPathsContext(context.Background(), files, opts)
+}
+
+// FileArrayWithPathsContext is like FileArrayWithPaths but carries ctx through to the underlying requests
+func (s *PrivateService) FileArrayWithPathsContext(ctx context.Context, files map[string]*os.File, opts *FileOptions) (*types.UploadResponse, error) {
+	entries, err := pathFolderFiles(files)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.fileArray(ctx, entries, opts)
+}
+
+// FolderFromPath uploads every file under dir as one folder to the private IPFS
+// network, keeping their paths relative to dir. The folder is named after dir
+// unless opts.FileName is set.
+func (s *PrivateService) FolderFromPath(dir string, opts *FolderOptions) (*types.UploadResponse, error) {
+	return s.FolderFromPathContext(context.Background(), dir, opts)
+}
+
+// FolderFromPathContext is like FolderFromPath but carries ctx through to the underlying requests
+func (s *PrivateService) FolderFromPathContext(ctx context.Context, dir string, opts *FolderOptions) (*types.UploadResponse, error) {
+	if opts == nil {
+		opts = &FolderOptions{}
+	}
+
+	entries, closeAll, err := openFolder(dir, opts.FollowSymlinks)
+	defer closeAll()
+	if err != nil {
+		return nil, err
+	}
+
+	fileOpts := opts.FileOptions
+	if fileOpts.FileName == "" {
+		fileOpts.FileName = filepath.Base(filepath.Clean(dir))
+	}
+
+	return s.fileArray(ctx, entries, &fileOpts)
+}
+
+// fileArray uploads files as a folder, naming each part after its path in the folder
+func (s *PrivateService) fileArray(ctx context.Context, files []folderFile, opts *FileOptions) (*types.UploadResponse, error) {
+	resp, err := s.uploadFileArray(ctx, files, opts)
+	if forgetGroup(s.config.(*types.Config), "private", opts, err) {
+		// The named group was deleted after it was cached, so resolve it again
+		resp, err = s.uploadFileArray(ctx, files, opts)
+	}
+	return resp, err
+}
+
+// uploadFileArray resolves the upload's group and sends files in one request
+func (s *PrivateService) uploadFileArray(ctx context.Context, files []folderFile, opts *FileOptions) (*types.UploadResponse, error) {
 	if len(files) == 0 {
 		return nil, fmt.Errorf("at least one file is required")
 	}
 
+	if err := checkExtraFields(opts); err != nil {
+		return nil, err
+	}
+
+	// Check the combined size before any file is read
+	if opts != nil && opts.MaxFileSize > 0 {
+		var total int64
+		for _, entry := range files {
+			fileInfo, err := entry.File.Stat()
+			if err != nil {
+				return nil, fmt.Errorf("failed to get file info: %w", err)
+			}
+			total += fileInfo.Size()
+		}
+		if err := checkFileSize("folder", total, opts); err != nil {
+			return nil, err
+		}
+	}
+
+	// Check every file's content type before any part is written
+	contentTypes := make([]string, len(files))
+	for i, entry := range files {
+		if _, err := entry.File.Seek(0, 0); err != nil {
+			return nil, fmt.Errorf("failed to reset file position: %w", err)
+		}
+
+		contentType, err := detectFileContentType(entry.File, entry.Path)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkMimeType(entry.Path, contentType, opts); err != nil {
+			return nil, err
+		}
+		contentTypes[i] = contentType
+	}
+
+	// Resolve the group only once the upload is known to be valid, since it may
+	// create the group
+	opts, err := resolveGroup(ctx, s.config.(*types.Config), "private", opts)
+	if err != nil {
+		return nil, err
+	}
+
 	cfg := s.config.(*types.Config)
 	url := fmt.Sprintf("%s/files", cfg.UploadUrl)
 
 	// Create multipart form data
 	body := &bytes.Buffer{}
-	writer := multipart.NewWriter(body)
+	writer, err := newMultipartWriter(body, opts)
+	if err != nil {
+		return nil, err
+	}
 
 	// Add the network parameter
 	if err := writer.WriteField("network", "private"); err != nil {
@@ -186,18 +464,30 @@ func (s *PrivateService) FileArray(files []*os.File, opts *FileOptions) (*types.
 				return nil, fmt.Errorf("failed to add keyvalues field: %w", err)
 			}
 		}
+
+		if opts.Vectorize {
+			if err := writer.WriteField("vectorize", "true"); err != nil {
+				return nil, fmt.Errorf("failed to add vectorize field: %w", err)
+			}
+		}
+	}
+
+	if err := writeExtraFields(writer, opts); err != nil {
+		return nil, err
 	}
 
 	// Add all files
-	for _, file := range files {
+	for i, entry := range files {
+		file := entry.File
+
 		// Reset file position to start
 		if _, err := file.Seek(0, 0); err != nil {
 			return nil, fmt.Errorf("failed to reset file position: %w", err)
 		}
 
-		part, err := writer.CreateFormFile("file", filepath.Base(file.Name()))
+		part, err := createFilePart(writer, entry.Path, contentTypes[i])
 		if err != nil {
-			return nil, fmt.Errorf("failed to create form file: %w", err)
+			return nil, err
 		}
 
 		if _, err := io.Copy(part, file); err != nil {
@@ -211,76 +501,168 @@ func (s *PrivateService) FileArray(files []*os.File, opts *FileOptions) (*types.
 	}
 
 	// Create the request
-	req, err := http.NewRequest("POST", url, body)
+	req, err := http.NewRequestWithContext(ctx, "POST", url, body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", writer.FormDataContentType())
-	req.Header.Set("Authorization", "Bearer "+cfg.PinataJWT)
+	request.SetHeaders(cfg, req)
 
-	// Add custom headers if any
-	for key, value := range cfg.CustomHeaders {
-		req.Header.Set(key, value)
+	if opts != nil {
+		request.SetIdempotencyKey(req, opts.IdempotencyKey)
 	}
 
-	// Send the request
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	// Send the request, resending the buffered body on transient failures
+	resp, err := request.DoIdempotent(cfg, req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send request: %w", err)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
-	}
 
 	// Parse the response
-	var response struct {
-		Data *types.UploadResponse `json:"data"`
+	var response *types.UploadResponse
+	if err := request.Decode(resp, &response); err != nil {
+		return nil, err
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	// A folder's CID depends on the DAG layout chosen by the upload service
+	if opts != nil && opts.VerifyCID {
+		return response, fmt.Errorf("%w: folder uploads are not supported", ErrCIDUnverifiable)
 	}
 
-	return response.Data, nil
+	return checkDuplicate(response, opts)
 }
 
-// JSON uploads a JSON object to the public IPFS network
-func (s *PrivateService) JSON(data interface{}, opts *JSONOptions) (*types.UploadResponse, error) {
-	if data == nil {
-		return nil, fmt.Errorf("JSON data is required")
+// FileBatch uploads each file as a separate file on the private IPFS network,
+// running at most concurrency uploads at a time (a default is used when it is
+// zero or less). Bodies are streamed from disk rather than buffered. The returned
+// slices are aligned with files: for each index either the response or the error
+// is set. opts.FileName is ignored so every file keeps its own name.
+func (s *PrivateService) FileBatch(files []*os.File, opts *FileOptions, concurrency int) ([]*types.UploadResponse, []error) {
+	return s.FileBatchContext(context.Background(), files, opts, concurrency)
+}
+
+// FileBatchContext is like FileBatch but carries ctx through to the underlying requests
+func (s *PrivateService) FileBatchContext(ctx context.Context, files []*os.File, opts *FileOptions, concurrency int) ([]*types.UploadResponse, []error) {
+	responses := make([]*types.UploadResponse, len(files))
+	errs := make([]error, len(files))
+
+	var fileOpts *FileOptions
+	if opts != nil {
+		copied := *opts
+		copied.FileName = ""
+		fileOpts = &copied
 	}
 
-	// Marshal the JSON data
-	jsonData, err := json.Marshal(data)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal JSON data: %w", err)
+	batch.Run(ctx, len(files), concurrency, func(ctx context.Context, i int) {
+		responses[i], errs[i] = s.fileStream(ctx, files[i], fileOpts)
+	})
+
+	// Files skipped because ctx was cancelled have neither a response nor an error
+	for i := range files {
+		if responses[i] == nil && errs[i] == nil {
+			errs[i] = fmt.Errorf("upload not attempted: %w", ctx.Err())
+		}
 	}
 
-	// Create a temporary file to hold the JSON data
-	tmpFile, err := os.CreateTemp("", "pinata-json-*.json")
-	if err != nil {
-		return nil, fmt.Errorf("failed to create temporary file: %w", err)
+	return responses, errs
+}
+
+// UploadStream uploads every file received from files to the private IPFS network as
+// a separate file, running at most concurrency uploads at a time (a default is
+// used when it is zero or less). Bodies are streamed from disk and retried like
+// FileBatch. One result is sent per file; the channel is closed once files is
+// closed and every upload has finished. When ctx is done, files not yet started
+// are not uploaded and get a result carrying ctx's error. The caller must close
+// files, even after ctx is done, and receive until the channel is closed.
+// opts.FileName is ignored.
+func (s *PrivateService) UploadStream(ctx context.Context, files <-chan *os.File, opts *FileOptions, concurrency int) <-chan UploadResult {
+	var fileOpts *FileOptions
+	if opts != nil {
+		copied := *opts
+		copied.FileName = ""
+		fileOpts = &copied
 	}
-	defer os.Remove(tmpFile.Name()) // Clean up
 
-	if _, err := tmpFile.Write(jsonData); err != nil {
-		return nil, fmt.Errorf("failed to write to temporary file: %w", err)
+	return streamUploads(ctx, files, concurrency, func(ctx context.Context, file *os.File) (*types.UploadResponse, error) {
+		return s.fileStream(ctx, file, fileOpts)
+	})
+}
+
+// fileStream uploads a single file, streaming its content into the request body
+func (s *PrivateService) fileStream(ctx context.Context, file *os.File, opts *FileOptions) (*types.UploadResponse, error) {
+	if file == nil {
+		return nil, fmt.Errorf("file is required")
 	}
 
 	// Reset file position to start
-	if _, err := tmpFile.Seek(0, 0); err != nil {
+	if _, err := file.Seek(0, 0); err != nil {
 		return nil, fmt.Errorf("failed to reset file position: %w", err)
 	}
 
+	data, err := NewFileData(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file info: %w", err)
+	}
+
+	return s.FileReaderContext(ctx, data, opts)
+}
+
+// ResumableFile uploads a large file to the private IPFS network in chunks using the
+// TUS protocol. If the upload stops part way, the returned error is a
+// *ResumableUploadError holding what ResumeFile needs to continue.
+func (s *PrivateService) ResumableFile(file *os.File, opts *ResumableOptions) (*types.UploadResponse, error) {
+	return s.ResumableFileContext(context.Background(), file, opts)
+}
+
+// ResumableFileContext is like ResumableFile but carries ctx through to the underlying requests
+func (s *PrivateService) ResumableFileContext(ctx context.Context, file *os.File, opts *ResumableOptions) (*types.UploadResponse, error) {
+	return resumableFile(ctx, s.config.(*types.Config), "private", file, opts)
+}
+
+// ResumeFile continues an interrupted resumable upload from offset. A negative
+// offset asks the server how much it already received.
+func (s *PrivateService) ResumeFile(uploadURL string, file *os.File, offset int64, chunkSize int64) (*types.UploadResponse, error) {
+	return s.ResumeFileContext(context.Background(), uploadURL, file, offset, chunkSize)
+}
+
+// ResumeFileContext is like ResumeFile but carries ctx through to the underlying requests
+func (s *PrivateService) ResumeFileContext(ctx context.Context, uploadURL string, file *os.File, offset int64, chunkSize int64) (*types.UploadResponse, error) {
+	return resumeFile(ctx, s.config.(*types.Config), "private", uploadURL, file, offset, chunkSize)
+}
+
+// JSON uploads a JSON object to the public IPFS network
+func (s *PrivateService) JSON(data interface{}, opts *JSONOptions) (*types.UploadResponse, error) {
+	return s.JSONContext(context.Background(), data, opts)
+}
+
+// JSONContext is like JSON but carries ctx through to the underlying requests
+func (s *PrivateService) JSONContext(ctx context.Context, data interface{}, opts *JSONOptions) (*types.UploadResponse, error) {
+	if data == nil {
+		return nil, fmt.Errorf("JSON data is required")
+	}
+
+	if opts == nil {
+		opts = &JSONOptions{}
+	}
+
+	// Marshal the JSON data
+	var jsonData []byte
+	var err error
+	if opts.Indent {
+		jsonData, err = json.MarshalIndent(data, "", "  ")
+	} else {
+		jsonData, err = json.Marshal(data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal JSON data: %w", err)
+	}
+
 	// Create file options
 	fileOpts := &FileOptions{
 		GroupID:   opts.GroupID,
 		KeyValues: opts.KeyValues,
+		Vectorize: opts.Vectorize,
 	}
 
 	// Use custom name or default
@@ -290,42 +672,42 @@ func (s *PrivateService) JSON(data interface{}, opts *JSONOptions) (*types.Uploa
 		fileOpts.FileName = "data.json"
 	}
 
-	// Use the File method to upload
-	return s.File(tmpFile, fileOpts)
+	contentType := "application/json"
+	if opts.ContentType != "" {
+		contentType = opts.ContentType
+	}
+
+	// Upload straight from memory so nothing touches the disk
+	fileData := NewCustomFileData(bytes.NewReader(jsonData), fileOpts.FileName, int64(len(jsonData)), contentType)
+	return s.FileReaderContext(ctx, fileData, fileOpts)
 }
 
 // Base64 uploads base64-encoded data to the public IPFS network
 func (s *PrivateService) Base64(data string, opts *Base64Options) (*types.UploadResponse, error) {
+	return s.Base64Context(context.Background(), data, opts)
+}
+
+// Base64Context is like Base64 but carries ctx through to the underlying requests
+func (s *PrivateService) Base64Context(ctx context.Context, data string, opts *Base64Options) (*types.UploadResponse, error) {
 	if data == "" {
 		return nil, fmt.Errorf("base64 data is required")
 	}
 
+	if opts == nil {
+		opts = &Base64Options{}
+	}
+
 	// Decode the base64 data
 	decoded, err := base64.StdEncoding.DecodeString(data)
 	if err != nil {
 		return nil, fmt.Errorf("failed to decode base64 data: %w", err)
 	}
 
-	// Create a temporary file to hold the decoded data
-	tmpFile, err := os.CreateTemp("", "pinata-base64-*")
-	if err != nil {
-		return nil, fmt.Errorf("failed to create temporary file: %w", err)
-	}
-	defer os.Remove(tmpFile.Name()) // Clean up
-
-	if _, err := tmpFile.Write(decoded); err != nil {
-		return nil, fmt.Errorf("failed to write to temporary file: %w", err)
-	}
-
-	// Reset file position to start
-	if _, err := tmpFile.Seek(0, 0); err != nil {
-		return nil, fmt.Errorf("failed to reset file position: %w", err)
-	}
-
 	// Create file options
 	fileOpts := &FileOptions{
 		GroupID:   opts.GroupID,
 		KeyValues: opts.KeyValues,
+		Vectorize: opts.Vectorize,
 	}
 
 	// Use custom name or default
@@ -335,54 +717,45 @@ func (s *PrivateService) Base64(data string, opts *Base64Options) (*types.Upload
 		fileOpts.FileName = "file"
 	}
 
-	// Use the File method to upload
-	return s.File(tmpFile, fileOpts)
+	// Upload straight from memory so nothing touches the disk
+	fileData := NewCustomFileData(bytes.NewReader(decoded), fileOpts.FileName, int64(len(decoded)), "")
+	return s.FileReaderContext(ctx, fileData, fileOpts)
 }
 
 // URL uploads the content of a URL to the public IPFS network
 func (s *PrivateService) URL(targetURL string, opts *URLOptions) (*types.UploadResponse, error) {
+	return s.URLContext(context.Background(), targetURL, opts)
+}
+
+// URLContext is like URL but carries ctx through to the underlying requests
+func (s *PrivateService) URLContext(ctx context.Context, targetURL string, opts *URLOptions) (*types.UploadResponse, error) {
 	if targetURL == "" {
 		return nil, fmt.Errorf("URL is required")
 	}
 
-	// Fetch the content from the URL
-	client := &http.Client{}
-	resp, err := client.Get(targetURL)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch URL content: %w", err)
+	if opts == nil {
+		opts = &URLOptions{}
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("URL returned non-OK status: %d", resp.StatusCode)
-	}
-
-	// Create a temporary file to hold the content
-	tmpFile, err := os.CreateTemp("", "pinata-url-*")
+	// Fetch the content from the URL
+	resp, err := fetchURL(ctx, s.config.(*types.Config), targetURL, opts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create temporary file: %w", err)
-	}
-	defer os.Remove(tmpFile.Name()) // Clean up
-
-	// Copy the content to the temporary file
-	if _, err := io.Copy(tmpFile, resp.Body); err != nil {
-		return nil, fmt.Errorf("failed to copy URL content: %w", err)
-	}
-
-	// Reset file position to start
-	if _, err := tmpFile.Seek(0, 0); err != nil {
-		return nil, fmt.Errorf("failed to reset file position: %w", err)
+		return nil, err
 	}
+	defer resp.Body.Close()
 
 	// Create file options
 	fileOpts := &FileOptions{
 		GroupID:   opts.GroupID,
 		KeyValues: opts.KeyValues,
+		Vectorize: opts.Vectorize,
 	}
 
-	// Use custom name or extract from URL
+	// Use custom name, the server's suggested name or extract from URL
 	if opts.Name != "" {
 		fileOpts.FileName = opts.Name
+	} else if name := dispositionFileName(resp.Header.Get("Content-Disposition")); name != "" {
+		fileOpts.FileName = name
 	} else {
 		// Extract filename from URL or use default
 		urlPath := strings.Split(targetURL, "/")
@@ -393,12 +766,83 @@ func (s *PrivateService) URL(targetURL string, opts *URLOptions) (*types.UploadR
 		}
 	}
 
-	// Use the File method to upload
-	return s.File(tmpFile, fileOpts)
+	// Stream the fetched body into the upload so nothing touches the disk. The
+	// body cannot be rewound, so a failed upload is not retried.
+	var size int64
+	if resp.ContentLength > 0 {
+		size = resp.ContentLength
+	}
+	fileData := NewCustomFileData(resp.Body, fileOpts.FileName, size, resp.Header.Get("Content-Type"))
+	return s.FileReaderContext(ctx, fileData, fileOpts)
+}
+
+// PinCID pins an existing CID to the private IPFS network. opts.HostNodes lists
+// multiaddrs of peers known to hold the content, which speeds up the fetch.
+func (s *PrivateService) PinCID(opts *CIDOptions) (*types.PinByHashResponse, error) {
+	return s.PinCIDContext(context.Background(), opts)
+}
+
+// PinCIDContext is like PinCID but carries ctx through to the underlying requests
+func (s *PrivateService) PinCIDContext(ctx context.Context, opts *CIDOptions) (*types.PinByHashResponse, error) {
+	if opts == nil || opts.CID == "" {
+		return nil, fmt.Errorf("CID is required")
+	}
+	if err := types.ValidateHostNodes(opts.HostNodes); err != nil {
+		return nil, err
+	}
+
+	cfg := s.config.(*types.Config)
+	url := fmt.Sprintf("%s/files/private/pin_by_cid", cfg.APIUrl)
+
+	// Build request payload
+	payload := map[string]interface{}{
+		"cid": opts.CID,
+	}
+
+	if opts.Name != "" {
+		payload["name"] = opts.Name
+	}
+
+	if opts.GroupID != "" {
+		payload["group_id"] = opts.GroupID
+	}
+
+	if len(opts.KeyValues) > 0 {
+		payload["keyvalues"] = opts.KeyValues
+	}
+
+	if len(opts.HostNodes) > 0 {
+		payload["host_nodes"] = opts.HostNodes
+	}
+
+	// Create the request
+	req, err := request.New(ctx, cfg, "POST", url, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	// Send the request
+	resp, err := request.Do(cfg, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+
+	// Parse the response
+	var response *types.PinByHashResponse
+	if err := request.Decode(resp, &response); err != nil {
+		return nil, err
+	}
+
+	return response, nil
 }
 
 // CreateSignedURL generates a signed URL for client-side uploads
 func (s *PrivateService) CreateSignedURL(opts *SignedUploadOptions) (string, error) {
+	return s.CreateSignedURLContext(context.Background(), opts)
+}
+
+// CreateSignedURLContext is like CreateSignedURL but carries ctx through to the underlying requests
+func (s *PrivateService) CreateSignedURLContext(ctx context.Context, opts *SignedUploadOptions) (string, error) {
 	if opts == nil || opts.Expires <= 0 {
 		return "", fmt.Errorf("expiration time is required")
 	}
@@ -444,46 +888,25 @@ func (s *PrivateService) CreateSignedURL(opts *SignedUploadOptions) (string, err
 		payload["allow_mime_types"] = opts.MimeTypes
 	}
 
-	jsonData, err := json.Marshal(payload)
-	if err != nil {
-		return "", fmt.Errorf("failed to marshal request body: %w", err)
-	}
-
 	// Create the request
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+	req, err := request.New(ctx, cfg, "POST", url, payload)
 	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
+		return "", err
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+cfg.PinataJWT)
-
-	// Add custom headers if any
-	for key, value := range cfg.CustomHeaders {
-		req.Header.Set(key, value)
-	}
+	request.SetIdempotencyKey(req, opts.IdempotencyKey)
 
 	// Send the request
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := request.Do(cfg, req)
 	if err != nil {
 		return "", fmt.Errorf("failed to send request: %w", err)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
-	}
 
 	// Parse the response
-	var response struct {
-		Data string `json:"data"`
-	}
-
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return "", fmt.Errorf("failed to decode response: %w", err)
+	var response string
+	if err := request.Decode(resp, &response); err != nil {
+		return "", err
 	}
 
-	return response.Data, nil
+	return response, nil
 }
@@ -0,0 +1,111 @@
+package upload
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/PinataCloud/pinata-go-sdk/pinata/types"
+)
+
+// newVectorizeTest records the vectorize form field of each upload, or "-"
+// when the field is absent
+func newVectorizeTest(t *testing.T) (*types.Config, *[]string) {
+	t.Helper()
+
+	var fields []string
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		field := "-"
+		if values, ok := r.MultipartForm.Value["vectorize"]; ok {
+			field = values[0]
+		}
+		fields = append(fields, field)
+		fmt.Fprint(w, `{"data":{"id":"file-1","cid":"bafy"}}`)
+	})
+
+	return cfg, &fields
+}
+
+func TestVectorizeFieldSentWhenEnabled(t *testing.T) {
+	cfg, fields := newVectorizeTest(t)
+	service := NewPrivateService(cfg)
+	file := openTempFiles(t, 1)[0]
+
+	if _, err := service.File(file, &FileOptions{Vectorize: true}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.JSON(map[string]int{"a": 1}, &JSONOptions{Vectorize: true}); err != nil {
+		t.Fatal(err)
+	}
+	data := &FileData{Reader: bytes.NewReader([]byte("hi")), Name: "hi.txt", Size: 2}
+	if _, err := service.FileReader(data, &FileOptions{Vectorize: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"true", "true", "true"}
+	if fmt.Sprint(*fields) != fmt.Sprint(want) {
+		t.Fatalf("vectorize fields %v, want %v", *fields, want)
+	}
+}
+
+func TestVectorizeFieldOmittedByDefault(t *testing.T) {
+	cfg, fields := newVectorizeTest(t)
+	file := openTempFiles(t, 1)[0]
+
+	if _, err := NewPrivateService(cfg).File(file, &FileOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewPrivateService(cfg).JSON(map[string]int{"a": 1}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"-", "-"}
+	if fmt.Sprint(*fields) != fmt.Sprint(want) {
+		t.Fatalf("vectorize fields %v, want %v", *fields, want)
+	}
+}
+
+func TestPinCIDSendsHostNodes(t *testing.T) {
+	var path string
+	var payload map[string]interface{}
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		path = r.Method + " " + r.URL.Path
+		json.NewDecoder(r.Body).Decode(&payload)
+		fmt.Fprint(w, `{"data":{"id":"req-1","cid":"bafy","status":"prechecking","host_nodes":["/dnsaddr/node.example.com"]}}`)
+	})
+
+	nodes := []string{"/ip4/203.0.113.7/tcp/4001/p2p/QmYyQSo1c1Ym7orWxLYvCrM2EmxFTANf8wXmmE7DWjhx5N", "/dnsaddr/node.example.com"}
+	resp, err := NewPrivateService(cfg).PinCID(&CIDOptions{CID: "bafy", Name: "pinned", GroupID: "group-1", HostNodes: nodes})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.ID != "req-1" || resp.Status != "prechecking" {
+		t.Fatalf("got %+v", resp)
+	}
+	if path != "POST /files/private/pin_by_cid" {
+		t.Fatalf("sent %s", path)
+	}
+	if fmt.Sprint(payload["host_nodes"]) != fmt.Sprint(nodes) || payload["cid"] != "bafy" || payload["group_id"] != "group-1" {
+		t.Fatalf("sent payload %v", payload)
+	}
+}
+
+func TestPinCIDRejectsInvalidHostNodes(t *testing.T) {
+	cfg := &types.Config{PinataJWT: "jwt", APIUrl: "http://127.0.0.1:0"}
+
+	_, err := NewPrivateService(cfg).PinCID(&CIDOptions{CID: "bafy", HostNodes: []string{"203.0.113.7:4001"}})
+	var invalid *types.InvalidHostNodesError
+	if !errors.As(err, &invalid) || len(invalid.Invalid) != 1 {
+		t.Fatalf("got %v, want an *InvalidHostNodesError", err)
+	}
+	if _, err := NewPrivateService(cfg).PinCID(&CIDOptions{}); err == nil {
+		t.Fatal("PinCID without a CID succeeded")
+	}
+}
@@ -0,0 +1,325 @@
+package upload
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/PinataCloud/pinata-go-sdk/pinata/internal/request"
+	"github.com/PinataCloud/pinata-go-sdk/pinata/types"
+)
+
+// DefaultChunkSize is the chunk size used by resumable uploads when none is set
+const DefaultChunkSize int64 = 10 * 1024 * 1024
+
+// tusVersion is the version of the TUS resumable upload protocol spoken by the upload endpoint
+const tusVersion = "1.0.0"
+
+// ResumableOptions represents options for resumable uploads. Vectorize and
+// ExtraFields are not supported and are rejected.
+type ResumableOptions struct {
+	FileOptions
+	// ChunkSize is the number of bytes sent per request. Zero uses DefaultChunkSize.
+	ChunkSize int64
+}
+
+// ResumableUploadError is returned when a resumable upload stops before all
+// chunks were sent. Pass UploadURL and Offset to ResumeFile to continue.
+type ResumableUploadError struct {
+	UploadURL string
+	Offset    int64
+	Err       error
+}
+
+// Error implements the error interface
+func (e *ResumableUploadError) Error() string {
+	return fmt.Sprintf("upload interrupted at offset %d: %v", e.Offset, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *ResumableUploadError) Unwrap() error {
+	return e.Err
+}
+
+// resumableFile creates a TUS upload for file and sends it chunk by chunk
+func resumableFile(ctx context.Context, cfg *types.Config, network string, file *os.File, opts *ResumableOptions) (*types.UploadResponse, error) {
+	if file == nil {
+		return nil, fmt.Errorf("file is required")
+	}
+
+	// Get file info
+	fileInfo, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file info: %w", err)
+	}
+
+	if opts == nil {
+		opts = &ResumableOptions{}
+	}
+
+	// The TUS endpoint only takes the metadata below, so these cannot be honoured
+	if opts.Vectorize {
+		return nil, fmt.Errorf("resumable uploads do not support Vectorize")
+	}
+	if len(opts.ExtraFields) > 0 {
+		return nil, fmt.Errorf("resumable uploads do not support ExtraFields")
+	}
+
+	name := fileInfo.Name()
+	if opts.FileName != "" {
+		name = opts.FileName
+	}
+
+	if err := checkFileSize(name, fileInfo.Size(), &opts.FileOptions); err != nil {
+		return nil, err
+	}
+
+	contentType, err := detectFileContentType(file, name)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkMimeType(name, contentType, &opts.FileOptions); err != nil {
+		return nil, err
+	}
+
+	uploadURL, err := createUpload(ctx, cfg, network, name, contentType, fileInfo.Size(), &opts.FileOptions)
+	if forgetGroup(cfg, network, &opts.FileOptions, err) {
+		// The named group was deleted after it was cached, so resolve it again
+		uploadURL, err = createUpload(ctx, cfg, network, name, contentType, fileInfo.Size(), &opts.FileOptions)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return sendChunks(ctx, cfg, network, uploadURL, file, 0, fileInfo.Size(), opts.ChunkSize)
+}
+
+// createUpload resolves the upload's group and creates a TUS upload of size
+// bytes, returning the URL the chunks are sent to
+func createUpload(ctx context.Context, cfg *types.Config, network string, name string, contentType string, size int64, opts *FileOptions) (string, error) {
+	opts, err := resolveGroup(ctx, cfg, network, opts)
+	if err != nil {
+		return "", err
+	}
+
+	metadata := map[string]string{
+		"filename": name,
+		"filetype": contentType,
+		"network":  network,
+	}
+	if opts.GroupID != "" {
+		metadata["group_id"] = opts.GroupID
+	}
+	if len(opts.KeyValues) > 0 {
+		keyvaluesJSON, err := json.Marshal(opts.KeyValues)
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal keyvalues: %w", err)
+		}
+		metadata["keyvalues"] = string(keyvaluesJSON)
+	}
+
+	endpoint := fmt.Sprintf("%s/files", cfg.UploadUrl)
+
+	// Create the upload; the endpoint answers with its location
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Tus-Resumable", tusVersion)
+	req.Header.Set("Upload-Length", strconv.FormatInt(size, 10))
+	req.Header.Set("Upload-Metadata", encodeUploadMetadata(metadata))
+
+	request.SetHeaders(cfg, req)
+
+	request.SetIdempotencyKey(req, opts.IdempotencyKey)
+
+	resp, err := request.Do(cfg, req)
+	if err != nil {
+		return "", fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		return "", request.Error(resp)
+	}
+
+	location := resp.Header.Get("Location")
+	if location == "" {
+		return "", fmt.Errorf("upload endpoint did not return a location")
+	}
+
+	// The location may be relative to the endpoint
+	ref, err := url.Parse(location)
+	if err != nil {
+		return "", fmt.Errorf("invalid upload location %q: %w", location, err)
+	}
+
+	return resp.Request.URL.ResolveReference(ref).String(), nil
+}
+
+// resumeFile continues a TUS upload from offset, asking the server for the
+// current offset when offset is negative
+func resumeFile(ctx context.Context, cfg *types.Config, network string, uploadURL string, file *os.File, offset int64, chunkSize int64) (*types.UploadResponse, error) {
+	if uploadURL == "" {
+		return nil, fmt.Errorf("upload URL is required")
+	}
+	if file == nil {
+		return nil, fmt.Errorf("file is required")
+	}
+
+	fileInfo, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file info: %w", err)
+	}
+
+	if offset < 0 {
+		offset, err = uploadOffset(ctx, cfg, uploadURL)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return sendChunks(ctx, cfg, network, uploadURL, file, offset, fileInfo.Size(), chunkSize)
+}
+
+// uploadOffset asks the upload endpoint how many bytes it has received
+func uploadOffset(ctx context.Context, cfg *types.Config, uploadURL string) (int64, error) {
+	req, err := http.NewRequestWithContext(ctx, "HEAD", uploadURL, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Tus-Resumable", tusVersion)
+
+	request.SetHeaders(cfg, req)
+
+	resp, err := request.Do(cfg, req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return 0, request.Error(resp)
+	}
+
+	offset, err := strconv.ParseInt(resp.Header.Get("Upload-Offset"), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid upload offset: %w", err)
+	}
+
+	return offset, nil
+}
+
+// sendChunks PATCHes the file to uploadURL from offset until size bytes were
+// sent, then fetches the resulting file record
+func sendChunks(ctx context.Context, cfg *types.Config, network string, uploadURL string, file *os.File, offset int64, size int64, chunkSize int64) (*types.UploadResponse, error) {
+	if chunkSize <= 0 {
+		chunkSize = DefaultChunkSize
+	}
+
+	buf := make([]byte, chunkSize)
+
+	for offset < size {
+		n, err := file.ReadAt(buf, offset)
+		if err != nil && err != io.EOF {
+			return nil, &ResumableUploadError{UploadURL: uploadURL, Offset: offset, Err: fmt.Errorf("failed to read file: %w", err)}
+		}
+
+		next, err := patchChunk(ctx, cfg, uploadURL, offset, buf[:n])
+		if err != nil {
+			return nil, &ResumableUploadError{UploadURL: uploadURL, Offset: offset, Err: err}
+		}
+		// Without progress the loop would resend the same chunk forever
+		if next <= offset || next > size {
+			return nil, &ResumableUploadError{UploadURL: uploadURL, Offset: offset, Err: fmt.Errorf("server reported upload offset %d after a chunk at offset %d of %d", next, offset, size)}
+		}
+		offset = next
+	}
+
+	return uploadedFile(ctx, cfg, network, uploadURL)
+}
+
+// patchChunk sends one chunk at offset and returns the new offset reported by the server
+func patchChunk(ctx context.Context, cfg *types.Config, uploadURL string, offset int64, chunk []byte) (int64, error) {
+	req, err := http.NewRequestWithContext(ctx, "PATCH", uploadURL, bytes.NewReader(chunk))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/offset+octet-stream")
+	req.Header.Set("Tus-Resumable", tusVersion)
+	req.Header.Set("Upload-Offset", strconv.FormatInt(offset, 10))
+
+	request.SetHeaders(cfg, req)
+
+	// Resending a chunk at the same offset is safe
+	resp, err := request.DoIdempotent(cfg, req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		return 0, request.Error(resp)
+	}
+
+	next, err := strconv.ParseInt(resp.Header.Get("Upload-Offset"), 10, 64)
+	if err != nil {
+		return offset + int64(len(chunk)), nil
+	}
+
+	return next, nil
+}
+
+// uploadedFile fetches the file record created by a completed resumable upload
+func uploadedFile(ctx context.Context, cfg *types.Config, network string, uploadURL string) (*types.UploadResponse, error) {
+	id, err := uploadFileID(uploadURL)
+	if err != nil {
+		return nil, err
+	}
+	url := fmt.Sprintf("%s/files/%s/%s", cfg.APIUrl, network, id)
+
+	var response *types.UploadResponse
+	if err := request.DoRequest(ctx, cfg, "GET", url, nil, &response); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// uploadFileID returns the file ID that ends the path of a TUS upload URL,
+// such as the abc123 in https://uploads.pinata.cloud/v3/files/abc123
+func uploadFileID(uploadURL string) (string, error) {
+	u, err := url.Parse(uploadURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid upload URL %q: %w", uploadURL, err)
+	}
+
+	id := path.Base(strings.TrimSuffix(u.Path, "/"))
+	if id == "" || id == "." || id == "/" || id == "files" || strings.Trim(id, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_") != "" {
+		return "", fmt.Errorf("upload URL %q does not end in a file ID", uploadURL)
+	}
+
+	return id, nil
+}
+
+// encodeUploadMetadata formats metadata for the Upload-Metadata header as
+// comma-separated "key base64(value)" pairs
+func encodeUploadMetadata(metadata map[string]string) string {
+	pairs := make([]string, 0, len(metadata))
+	for key, value := range metadata {
+		pairs = append(pairs, key+" "+base64.StdEncoding.EncodeToString([]byte(value)))
+	}
+	return strings.Join(pairs, ",")
+}
@@ -0,0 +1,171 @@
+package upload
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/PinataCloud/pinata-go-sdk/pinata/types"
+)
+
+// tusServer fakes the TUS upload endpoint for a single upload at /files/up1.
+// The PATCH numbered failPatch (counting from 1) is rejected without storing
+// its chunk.
+type tusServer struct {
+	mu        sync.Mutex
+	received  []byte
+	metadata  string
+	patches   []int64
+	failPatch int
+}
+
+func (s *tusServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case r.Method == "POST" && r.URL.Path == "/files":
+		s.metadata = r.Header.Get("Upload-Metadata")
+		// A relative location is resolved against the endpoint
+		w.Header().Set("Location", "files/up1")
+		w.WriteHeader(http.StatusCreated)
+	case r.Method == "HEAD" && r.URL.Path == "/files/up1":
+		w.Header().Set("Upload-Offset", strconv.Itoa(len(s.received)))
+	case r.Method == "PATCH" && r.URL.Path == "/files/up1":
+		offset, _ := strconv.ParseInt(r.Header.Get("Upload-Offset"), 10, 64)
+		s.patches = append(s.patches, offset)
+		if len(s.patches) == s.failPatch {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"error":"connection reset"}`)
+			return
+		}
+		if offset != int64(len(s.received)) {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		chunk, _ := io.ReadAll(r.Body)
+		s.received = append(s.received, chunk...)
+		w.Header().Set("Upload-Offset", strconv.Itoa(len(s.received)))
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == "GET" && r.URL.Path == "/files/public/up1":
+		fmt.Fprintf(w, `{"data":{"id":"up1","cid":"bafy","size":%d}}`, len(s.received))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func newResumableTest(t *testing.T, failPatch int) (*tusServer, *PublicService, *os.File, string) {
+	t.Helper()
+
+	s := &tusServer{failPatch: failPatch}
+	content := strings.Repeat("0123456789", 10)
+	path := filepath.Join(t.TempDir(), "big.txt")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { file.Close() })
+
+	return s, NewPublicService(newTestConfig(t, s.ServeHTTP)), file, content
+}
+
+func TestResumableFileSendsChunks(t *testing.T) {
+	s, service, file, content := newResumableTest(t, 0)
+
+	resp, err := service.ResumableFile(file, &ResumableOptions{ChunkSize: 30})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.ID != "up1" || resp.Size != 100 {
+		t.Fatalf("got %+v", resp)
+	}
+	if string(s.received) != content {
+		t.Fatalf("server received %q", s.received)
+	}
+	if fmt.Sprint(s.patches) != "[0 30 60 90]" {
+		t.Fatalf("chunks sent at offsets %v", s.patches)
+	}
+	if !strings.Contains(s.metadata, "filename "+base64.StdEncoding.EncodeToString([]byte("big.txt"))) {
+		t.Fatalf("metadata %q does not name the file", s.metadata)
+	}
+}
+
+func TestResumeAfterMidUploadFailure(t *testing.T) {
+	s, service, file, content := newResumableTest(t, 3)
+
+	_, err := service.ResumableFile(file, &ResumableOptions{ChunkSize: 30})
+	var resumable *ResumableUploadError
+	if !errors.As(err, &resumable) {
+		t.Fatalf("got %v, want a *ResumableUploadError", err)
+	}
+	if resumable.Offset != 60 || !strings.HasSuffix(resumable.UploadURL, "/files/up1") {
+		t.Fatalf("interrupted at %d of %s, want 60 of .../files/up1", resumable.Offset, resumable.UploadURL)
+	}
+	if types.StatusCode(err) != http.StatusBadRequest {
+		t.Fatalf("got %v, want the failed chunk's 400", err)
+	}
+
+	resp, err := service.ResumeFile(resumable.UploadURL, file, resumable.Offset, 30)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.ID != "up1" || string(s.received) != content {
+		t.Fatalf("got %+v with %q received", resp, s.received)
+	}
+	if fmt.Sprint(s.patches) != "[0 30 60 60 90]" {
+		t.Fatalf("chunks sent at offsets %v", s.patches)
+	}
+}
+
+func TestResumeAsksServerForOffset(t *testing.T) {
+	s, service, file, content := newResumableTest(t, 2)
+
+	_, err := service.ResumableFile(file, &ResumableOptions{ChunkSize: 40})
+	var resumable *ResumableUploadError
+	if !errors.As(err, &resumable) {
+		t.Fatalf("got %v, want a *ResumableUploadError", err)
+	}
+
+	if _, err := service.ResumeFile(resumable.UploadURL, file, -1, 40); err != nil {
+		t.Fatal(err)
+	}
+	if string(s.received) != content {
+		t.Fatalf("server received %q", s.received)
+	}
+	if fmt.Sprint(s.patches) != "[0 40 40 80]" {
+		t.Fatalf("chunks sent at offsets %v", s.patches)
+	}
+}
+
+func TestResumableFileRejectsUnsupportedOptions(t *testing.T) {
+	_, service, file, _ := newResumableTest(t, 0)
+
+	if _, err := service.ResumableFile(file, &ResumableOptions{FileOptions: FileOptions{Vectorize: true}}); err == nil {
+		t.Fatal("Vectorize accepted")
+	}
+	if _, err := service.ResumableFile(file, &ResumableOptions{FileOptions: FileOptions{ExtraFields: map[string]string{"a": "b"}}}); err == nil {
+		t.Fatal("ExtraFields accepted")
+	}
+}
+
+func TestUploadFileID(t *testing.T) {
+	if id, err := uploadFileID("https://uploads.pinata.cloud/v3/files/abc-123/"); err != nil || id != "abc-123" {
+		t.Fatalf("got %q, %v", id, err)
+	}
+	for _, bad := range []string{"https://uploads.pinata.cloud/v3/files", "https://uploads.pinata.cloud/", "https://x/files/a%20b"} {
+		if _, err := uploadFileID(bad); err == nil {
+			t.Errorf("uploadFileID(%q) succeeded", bad)
+		}
+	}
+}
@@ -0,0 +1,311 @@
+package upload
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"sync"
+
+	"github.com/PinataCloud/pinata-go-sdk/pinata/internal/batch"
+	"github.com/PinataCloud/pinata-go-sdk/pinata/internal/request"
+	"github.com/PinataCloud/pinata-go-sdk/pinata/types"
+)
+
+// checkStream runs the local checks on a streamed upload, so callers can reject
+// it before its group is resolved or anything is sent. When opts restricts the
+// content type and it cannot be told from data's type or name, the leading bytes
+// are sniffed now; the returned data then carries the sniffed type, and a reader
+// that still yields those bytes if data.Reader cannot be rewound.
+func checkStream(data *FileData, opts *FileOptions) (*FileData, error) {
+	if err := checkExtraFields(opts); err != nil {
+		return nil, err
+	}
+
+	name := streamName(data, opts)
+
+	// A size of zero is unknown, so only a declared size can be checked up front
+	if err := checkFileSize(name, data.Size, opts); err != nil {
+		return nil, err
+	}
+
+	if opts == nil || len(opts.AllowedMimeTypes) == 0 {
+		return data, nil
+	}
+
+	contentType := streamContentType(data, name)
+	if contentType == "" {
+		sniffed := *data
+		head := make([]byte, sniffLen)
+		if seeker, ok := data.Reader.(io.Seeker); ok {
+			offset, err := seeker.Seek(0, io.SeekCurrent)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get reader position: %w", err)
+			}
+			n, err := io.ReadFull(data.Reader, head)
+			if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+				return nil, fmt.Errorf("failed to read file header: %w", err)
+			}
+			head = head[:n]
+			if _, err := seeker.Seek(offset, io.SeekStart); err != nil {
+				return nil, fmt.Errorf("failed to reset reader position: %w", err)
+			}
+		} else {
+			buffered := bufio.NewReaderSize(data.Reader, sniffLen)
+			head, _ = buffered.Peek(sniffLen)
+			sniffed.Reader = buffered
+		}
+		contentType = http.DetectContentType(head)
+		sniffed.ContentType = contentType
+		data = &sniffed
+	}
+
+	if err := checkMimeType(name, contentType, opts); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// streamName returns the name data is uploaded as
+func streamName(data *FileData, opts *FileOptions) string {
+	if opts != nil && opts.FileName != "" {
+		return opts.FileName
+	}
+	if data.Name != "" {
+		return data.Name
+	}
+	return "file"
+}
+
+// newStreamingRequest builds an upload request whose multipart body is written
+// from data.Reader while the request is being sent, without buffering it. If the
+// reader is an io.Seeker the body can be rebuilt, so the request may be retried.
+// data and opts must have passed checkStream.
+func newStreamingRequest(ctx context.Context, cfg *types.Config, network string, data *FileData, opts *FileOptions) (*http.Request, error) {
+	url := fmt.Sprintf("%s/files", cfg.UploadUrl)
+	name := streamName(data, opts)
+
+	// Remember where the reader starts so retries can rewind to it
+	seeker, canSeek := data.Reader.(io.Seeker)
+	var start int64
+	if canSeek {
+		offset, err := seeker.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get reader position: %w", err)
+		}
+		start = offset
+	}
+
+	boundaryWriter, err := newMultipartWriter(nil, opts)
+	if err != nil {
+		return nil, err
+	}
+	boundary := boundaryWriter.Boundary()
+
+	// Errors raised while writing the form would be raised again by a retry, so
+	// they are permanent
+	newBody := func() *streamBody {
+		pr, pw := io.Pipe()
+		writer := multipart.NewWriter(pw)
+		writer.SetBoundary(boundary)
+
+		return &streamBody{
+			PipeReader: pr,
+			done:       make(chan struct{}),
+			write: func() {
+				pw.CloseWithError(request.Permanent(writeStreamForm(writer, network, name, data, opts)))
+			},
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// The body's writer only starts on the first read, so a request that is
+	// never sent, e.g. on a closed client, leaves no goroutine behind
+	var mu sync.Mutex
+	current := newBody()
+	req.Body = current
+
+	if canSeek {
+		req.GetBody = func() (io.ReadCloser, error) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			// The previous writer must be done with the reader before it is rewound
+			current.stop()
+			if _, err := seeker.Seek(start, io.SeekStart); err != nil {
+				return nil, fmt.Errorf("failed to reset reader position: %w", err)
+			}
+
+			current = newBody()
+			return current, nil
+		}
+	}
+
+	req.Header.Set("Content-Type", "multipart/form-data; boundary="+boundary)
+
+	return req, nil
+}
+
+// errBodyReplaced stops the writer of a streamed body that a retry replaced
+var errBodyReplaced = errors.New("request body was replaced by a retry")
+
+// streamBody is the read end of a streamed multipart body. Its form is written by
+// a goroutine running write, started on the first Read, which closes done when
+// it returns. A body closed before it is read never starts the goroutine.
+type streamBody struct {
+	*io.PipeReader
+	write func()
+	once  sync.Once
+	done  chan struct{}
+}
+
+// Read starts the writer on the first call and reads the form it writes
+func (b *streamBody) Read(p []byte) (int, error) {
+	b.once.Do(func() {
+		go func() {
+			defer close(b.done)
+			b.write()
+		}()
+	})
+	return b.PipeReader.Read(p)
+}
+
+// Close closes the body, stopping its writer if it was started
+func (b *streamBody) Close() error {
+	b.once.Do(func() { close(b.done) })
+	return b.PipeReader.Close()
+}
+
+// stop closes the body and waits for its writer, if started, to return
+func (b *streamBody) stop() {
+	b.once.Do(func() { close(b.done) })
+	b.CloseWithError(errBodyReplaced)
+	<-b.done
+}
+
+// writeStreamForm writes the upload fields followed by the file part and closes the writer
+func writeStreamForm(writer *multipart.Writer, network string, name string, data *FileData, opts *FileOptions) error {
+	// Add the network parameter
+	if err := writer.WriteField("network", network); err != nil {
+		return fmt.Errorf("failed to add network field: %w", err)
+	}
+
+	if err := writer.WriteField("name", name); err != nil {
+		return fmt.Errorf("failed to add name field: %w", err)
+	}
+
+	// Add optional fields if provided
+	if opts != nil {
+		if opts.GroupID != "" {
+			if err := writer.WriteField("group_id", opts.GroupID); err != nil {
+				return fmt.Errorf("failed to add group_id field: %w", err)
+			}
+		}
+
+		// Add keyvalues if present
+		if len(opts.KeyValues) > 0 {
+			keyvaluesJSON, err := json.Marshal(opts.KeyValues)
+			if err != nil {
+				return fmt.Errorf("failed to marshal keyvalues: %w", err)
+			}
+
+			if err := writer.WriteField("keyvalues", string(keyvaluesJSON)); err != nil {
+				return fmt.Errorf("failed to add keyvalues field: %w", err)
+			}
+		}
+
+		// Vectorizing is only available for private files
+		if opts.Vectorize && network == "private" {
+			if err := writer.WriteField("vectorize", "true"); err != nil {
+				return fmt.Errorf("failed to add vectorize field: %w", err)
+			}
+		}
+	}
+
+	if err := writeExtraFields(writer, opts); err != nil {
+		return err
+	}
+
+	// Add the file, using the caller's content type when known and otherwise
+	// the file name's extension or the sniffed leading bytes
+	reader := data.Reader
+	contentType := streamContentType(data, name)
+	if contentType == "" {
+		buffered := bufio.NewReaderSize(data.Reader, sniffLen)
+		head, _ := buffered.Peek(sniffLen)
+		contentType = http.DetectContentType(head)
+		reader = buffered
+	}
+
+	part, err := createFilePart(writer, name, contentType)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(part, reader); err != nil {
+		return fmt.Errorf("failed to copy file data: %w", err)
+	}
+
+	// Close the writer
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("failed to close multipart writer: %w", err)
+	}
+
+	return nil
+}
+
+// streamContentType returns the caller's content type for data, or the one
+// registered for name's extension. It is empty when the data must be sniffed.
+func streamContentType(data *FileData, name string) string {
+	if data.ContentType != "" {
+		return data.ContentType
+	}
+	return contentTypeByName(name)
+}
+
+// streamUploads runs upload for every file received from files using at most
+// concurrency workers and sends each outcome on the returned channel. Files are
+// received until files is closed, even after ctx is done, so every file gets a
+// result: once ctx is done the remaining ones fail with its error without being
+// uploaded. The channel is closed after the last result has been received.
+func streamUploads(ctx context.Context, files <-chan *os.File, concurrency int, upload func(context.Context, *os.File) (*types.UploadResponse, error)) <-chan UploadResult {
+	if concurrency <= 0 {
+		concurrency = batch.DefaultConcurrency
+	}
+
+	results := make(chan UploadResult)
+	var wg sync.WaitGroup
+
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for file := range files {
+				if err := ctx.Err(); err != nil {
+					results <- UploadResult{File: file, Err: fmt.Errorf("upload not attempted: %w", err)}
+					continue
+				}
+
+				resp, err := upload(ctx, file)
+				results <- UploadResult{File: file, Response: resp, Err: err}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	return results
+}
@@ -0,0 +1,241 @@
+package upload
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/PinataCloud/pinata-go-sdk/pinata/internal/request"
+	"github.com/PinataCloud/pinata-go-sdk/pinata/types"
+)
+
+func openTempFiles(t *testing.T, n int) []*os.File {
+	t.Helper()
+
+	files := make([]*os.File, n)
+	for i := range files {
+		path := filepath.Join(t.TempDir(), "file.txt")
+		if err := os.WriteFile(path, []byte("content"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		file, err := os.Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { file.Close() })
+		files[i] = file
+	}
+	return files
+}
+
+func TestStreamUploadsResultPerFile(t *testing.T) {
+	files := openTempFiles(t, 5)
+
+	in := make(chan *os.File)
+	go func() {
+		defer close(in)
+		for _, file := range files {
+			in <- file
+		}
+	}()
+
+	results := streamUploads(context.Background(), in, 2, func(ctx context.Context, file *os.File) (*types.UploadResponse, error) {
+		return &types.UploadResponse{Name: file.Name()}, nil
+	})
+
+	seen := map[*os.File]bool{}
+	for result := range results {
+		if result.Err != nil {
+			t.Fatal(result.Err)
+		}
+		if result.Response.Name != result.File.Name() {
+			t.Fatalf("result for %s carries the response for %s", result.File.Name(), result.Response.Name)
+		}
+		seen[result.File] = true
+	}
+	if len(seen) != len(files) {
+		t.Fatalf("got results for %d files, want %d", len(seen), len(files))
+	}
+}
+
+func TestStreamUploadsCancelledReportsEveryFile(t *testing.T) {
+	files := openTempFiles(t, 4)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	in := make(chan *os.File)
+	go func() {
+		defer close(in)
+		for i, file := range files {
+			if i == 1 {
+				cancel()
+			}
+			in <- file
+		}
+	}()
+
+	uploaded := 0
+	results := streamUploads(ctx, in, 1, func(ctx context.Context, file *os.File) (*types.UploadResponse, error) {
+		uploaded++
+		return &types.UploadResponse{}, nil
+	})
+
+	var count, cancelled int
+	for result := range results {
+		count++
+		if errors.Is(result.Err, context.Canceled) {
+			cancelled++
+		}
+	}
+
+	if count != len(files) {
+		t.Fatalf("got %d results, want %d", count, len(files))
+	}
+	if uploaded+cancelled != len(files) || cancelled < len(files)-2 {
+		t.Fatalf("uploaded %d and cancelled %d of %d files", uploaded, cancelled, len(files))
+	}
+}
+
+func TestFileReaderUploadsFromBytesReader(t *testing.T) {
+	type upload struct{ network, name, contentType, content string }
+	var uploads []upload
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		content, _ := io.ReadAll(file)
+		uploads = append(uploads, upload{r.FormValue("network"), header.Filename, header.Header.Get("Content-Type"), string(content)})
+		fmt.Fprint(w, `{"data":{"id":"file-1","cid":"bafy"}}`)
+	})
+
+	newData := func() *FileData {
+		return &FileData{Reader: bytes.NewReader([]byte("in memory")), Name: "notes.txt", Size: 9, ContentType: "text/plain"}
+	}
+	if _, err := NewPublicService(cfg).FileReader(newData(), nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewPrivateService(cfg).FileReader(newData(), &FileOptions{FileName: "renamed.txt"}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []upload{
+		{"public", "notes.txt", "text/plain", "in memory"},
+		{"private", "renamed.txt", "text/plain", "in memory"},
+	}
+	if fmt.Sprint(uploads) != fmt.Sprint(want) {
+		t.Fatalf("got %+v, want %+v", uploads, want)
+	}
+}
+
+func TestFileReaderRequiresData(t *testing.T) {
+	cfg := &types.Config{PinataJWT: "jwt"}
+	if _, err := NewPublicService(cfg).FileReader(nil, nil); err == nil {
+		t.Fatal("FileReader without data succeeded")
+	}
+	if _, err := NewPrivateService(cfg).FileReader(&FileData{Name: "empty"}, nil); err == nil {
+		t.Fatal("FileReader without a reader succeeded")
+	}
+}
+
+func TestFileReaderDoesNotLeakUnsentBodies(t *testing.T) {
+	closed := &types.Config{PinataJWT: "jwt", APIUrl: "http://127.0.0.1:0", UploadUrl: "http://127.0.0.1:0"}
+	if err := request.Shutdown(context.Background(), closed); err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		name string
+		cfg  *types.Config
+		want error
+	}{
+		{"closed client", closed, types.ErrClientClosed},
+		{"empty JWT", &types.Config{APIUrl: "http://127.0.0.1:0", UploadUrl: "http://127.0.0.1:0"}, types.ErrMissingJWT},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			before := runtime.NumGoroutine()
+			for i := 0; i < 50; i++ {
+				data := &FileData{Reader: bytes.NewReader(bytes.Repeat([]byte("x"), 1<<16)), Name: "big.bin", Size: 1 << 16}
+				if _, err := NewPublicService(c.cfg).FileReader(data, nil); !errors.Is(err, c.want) {
+					t.Fatalf("got %v, want %v", err, c.want)
+				}
+			}
+			deadline := time.Now().Add(time.Second)
+			for runtime.NumGoroutine() > before+5 && time.Now().Before(deadline) {
+				time.Sleep(10 * time.Millisecond)
+			}
+			if n := runtime.NumGoroutine(); n > before+5 {
+				t.Fatalf("%d goroutines before the uploads, %d after", before, n)
+			}
+		})
+	}
+}
+
+// formFile builds a multipart form holding one file part and parses it back the
+// way an HTTP handler would, returning the part's header
+func formFile(t *testing.T, name string, contentType string, content string) *multipart.FileHeader {
+	t.Helper()
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="upload"; filename=%q`, name))
+	header.Set("Content-Type", contentType)
+	part, err := writer.CreatePart(header)
+	if err != nil {
+		t.Fatal(err)
+	}
+	part.Write([]byte(content))
+	writer.Close()
+
+	req := httptest.NewRequest("POST", "/upload", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	_, fh, err := req.FormFile("upload")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { req.MultipartForm.RemoveAll() })
+	return fh
+}
+
+func TestFileFromMultipart(t *testing.T) {
+	var uploaded []string
+	cfg := newTestConfig(t, func(w http.ResponseWriter, r *http.Request) {
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		content, _ := io.ReadAll(file)
+		uploaded = append(uploaded, fmt.Sprintf("%s|%s|%s|%s", header.Filename, header.Header.Get("Content-Type"), r.FormValue("name"), content))
+		fmt.Fprint(w, `{"data":{"id":"file-1","cid":"bafy"}}`)
+	})
+
+	if _, err := NewPublicService(cfg).FileFromMultipart(formFile(t, "photo.png", "image/png", "png bytes"), nil); err != nil {
+		t.Fatal(err)
+	}
+	// A browser's generic type gives way to the one detected from the name
+	if _, err := NewPrivateService(cfg).FileFromMultipart(formFile(t, "notes.txt", "application/octet-stream", "hello"), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"photo.png|image/png|photo.png|png bytes", "notes.txt|text/plain; charset=utf-8|notes.txt|hello"}
+	if fmt.Sprint(uploaded) != fmt.Sprint(want) {
+		t.Fatalf("uploaded %q, want %q", uploaded, want)
+	}
+
+	if _, err := NewPublicService(cfg).FileFromMultipart(nil, nil); err == nil {
+		t.Fatal("nil header: got no error")
+	}
+}
@@ -3,14 +3,61 @@ package upload
 import (
 	"io"
 	"os"
+	"time"
+
+	"github.com/PinataCloud/pinata-go-sdk/pinata/internal/request"
+	"github.com/PinataCloud/pinata-go-sdk/pinata/types"
 )
 
+// IdempotencyKeyHeader is the header carrying FileOptions.IdempotencyKey
+const IdempotencyKeyHeader = request.IdempotencyKeyHeader
+
 // FileOptions represents options for file uploads
 type FileOptions struct {
+	// FileName is the name the file is stored under, used for both the name
+	// field and the multipart part's file name. Empty uses the file's own name.
 	FileName  string
 	GroupID   string
 	KeyValues map[string]string
+	// GroupName, when GroupID is empty, adds the upload to the group with this
+	// name, creating the group if it does not exist yet. The resolved ID is
+	// remembered for later uploads with the same Config.
+	GroupName string
+	// NoGroup keeps the upload out of any group when GroupID and GroupName are
+	// empty, instead of using the Config's default group for the network
+	NoGroup bool
+	// Vectorize creates vector embeddings for the file on upload. It is only
+	// supported by private uploads.
 	Vectorize bool
+	// AllowedMimeTypes rejects files whose detected content type does not match
+	// one of these types before anything is sent. Wildcards such as "image/*"
+	// are supported. Empty allows every type.
+	AllowedMimeTypes []string
+	// MaxFileSize rejects uploads larger than this many bytes before anything is
+	// sent. For FileArray it applies to the combined size. Zero means unlimited.
+	MaxFileSize int64
+	// FailOnDuplicate makes an upload of content that is already stored return
+	// ErrDuplicate, along with the existing file's record. By default the
+	// existing record is returned without an error.
+	FailOnDuplicate bool
+	// VerifyCID recomputes the CID of the uploaded content and returns a
+	// *CIDMismatchError, along with the response, if the API returned a different
+	// one. The CID is computed in the format of the returned one: CIDv0, or CIDv1
+	// with the raw or dag-pb codec, using sha2-256. Only content of up to 256 KiB,
+	// which is stored as a single block, can be verified; ErrCIDUnverifiable is
+	// returned for larger content or readers that cannot be read twice.
+	VerifyCID bool
+	// IdempotencyKey is sent as the x-idempotency-key header. The same key is
+	// used for every retry, so the server can recognise a repeated upload.
+	IdempotencyKey string
+	// ExtraFields are written to the multipart form before the file, for API
+	// parameters the SDK does not have an option for yet. The file, network and
+	// name fields cannot be set this way.
+	ExtraFields map[string]string
+	// Boundary replaces the random multipart boundary, so the request body is
+	// the same on every run, e.g. for golden-file tests. It must be 1 to 70
+	// characters allowed by RFC 2046. Empty uses a random boundary.
+	Boundary string
 }
 
 // Base64Options represents options for base64 uploads
@@ -27,6 +74,11 @@ type JSONOptions struct {
 	GroupID   string
 	KeyValues map[string]string
 	Vectorize bool
+	// Indent pretty-prints the JSON with two space indentation
+	Indent bool
+	// ContentType overrides the application/json content type, e.g. with
+	// application/ld+json for JSON-LD metadata
+	ContentType string
 }
 
 // URLOptions represents options for URL uploads
@@ -35,6 +87,19 @@ type URLOptions struct {
 	GroupID   string
 	KeyValues map[string]string
 	Vectorize bool
+	// MaxSize fails the upload with ErrURLTooLarge when the content is larger
+	// than this many bytes. Zero means unlimited.
+	MaxSize int64
+	// MaxRedirects is the number of redirects followed when fetching the URL.
+	// Zero uses the default of 10 and a negative value follows none.
+	MaxRedirects int
+	// AllowedHosts restricts the URL and any redirect to these host names. Empty
+	// allows every host.
+	AllowedHosts []string
+	// FetchTimeout limits how long fetching the URL content may take, including
+	// reading the body while it is uploaded. It fails the upload with
+	// ErrURLFetchTimeout. Zero means no limit beyond the context.
+	FetchTimeout time.Duration
 }
 
 // CIDOptions represents options for pinning an existing CID
@@ -43,6 +108,8 @@ type CIDOptions struct {
 	Name      string
 	GroupID   string
 	KeyValues map[string]string
+	// HostNodes are multiaddrs of peers that already have the content, checked
+	// with types.ValidateHostNodes before the request is sent
 	HostNodes []string
 }
 
@@ -56,6 +123,16 @@ type SignedUploadOptions struct {
 	Vectorize   bool
 	MaxFileSize int64
 	MimeTypes   []string
+	// IdempotencyKey is sent as the x-idempotency-key header
+	IdempotencyKey string
+}
+
+// UploadResult is the outcome of one upload from UploadStream. Either Response
+// or Err is set.
+type UploadResult struct {
+	File     *os.File
+	Response *types.UploadResponse
+	Err      error
 }
 
 // FileData wraps either an os.File or io.Reader with additional metadata